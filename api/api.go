@@ -1,18 +1,27 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httputil"
 	"net/http/pprof"
+	"net/url"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ajvb/kala/api/middleware"
 	"github.com/ajvb/kala/job"
 
 	"github.com/gorilla/mux"
 	"github.com/phyber/negroni-gzip/gzip"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 )
@@ -24,13 +33,43 @@ const (
 	JobPath    = "job/"
 	ApiJobPath = ApiUrlPrefix + JobPath
 
+	CalendarPath    = "calendar/"
+	ApiCalendarPath = ApiUrlPrefix + CalendarPath
+
+	SchedulePath    = "schedule/"
+	ApiSchedulePath = ApiUrlPrefix + SchedulePath
+
+	WorkflowPath    = "workflow/"
+	ApiWorkflowPath = ApiUrlPrefix + WorkflowPath
+
+	GraphPath    = "graph/"
+	ApiGraphPath = ApiUrlPrefix + GraphPath
+
+	// How many run times are previewed if not asked for, and at most.
+	defaultNextRuns = 10
+	maxNextRuns     = 1000
+
 	contentType     = "Content-Type"
 	jsonContentType = "application/json;charset=UTF-8"
 
+	// Set on requests proxied to another node, so they are never proxied twice.
+	forwardedHeader = "X-Kala-Forwarded"
+
+	// How long wait=true waits for a run to finish by default.
+	DefaultWaitTimeout = time.Minute
+
 	MAX_BODY_SIZE       = 1048576
 	READ_HEADER_TIMEOUT = 0
 )
 
+var (
+	ErrShuttingDown    = errors.New("Kala is shutting down")
+	ErrInvalidN        = fmt.Errorf("n must be between 1 and %d", maxNextRuns)
+	ErrMissingSchedule = errors.New("A schedule is required")
+	ErrInvalidTimeout  = errors.New("timeout must be a positive duration, e.g. 30s")
+	ErrInvalidOrder    = errors.New("order must be asc or desc")
+)
+
 type KalaStatsResponse struct {
 	Stats *job.KalaStats
 }
@@ -46,7 +85,7 @@ func HandleKalaStatsRequest(cache job.JobCache) func(w http.ResponseWriter, r *h
 		w.Header().Set(contentType, jsonContentType)
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Errorf("Error occurred when marshaling response: %s", err)
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
 			return
 		}
 	}
@@ -54,9 +93,15 @@ func HandleKalaStatsRequest(cache job.JobCache) func(w http.ResponseWriter, r *h
 
 type ListJobStatsResponse struct {
 	JobStats []*job.JobStat `json:"job_stats"`
+	// Cursor of the next page of stats, if there are more.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
-// HandleListJobStatsRequest is the handler for getting job-specific stats
+// HandleListJobStatsRequest is the handler for getting job-specific stats.
+// The stats can be filtered and paged through as described by parseStatsQuery.
+// Without any of the paging parameters, limit, cursor and order, all of the
+// matching stats are listed oldest first, as they were before they could be
+// paged through.
 // /api/v1/job/stats/{id}
 func HandleListJobStatsRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -67,19 +112,114 @@ func HandleListJobStatsRequest(cache job.JobCache) func(w http.ResponseWriter, r
 			return
 		}
 
+		q, err := parseStatsQuery(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		if !isPaged(r) {
+			q.All, q.Ascending = true, true
+		}
+		stats, next, err := j.QueryStats(q)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
 		resp := &ListJobStatsResponse{
-			JobStats: j.Stats,
+			JobStats:   stats,
+			NextCursor: next,
 		}
 
 		w.Header().Set(contentType, jsonContentType)
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Errorf("Error occurred when marshaling response: %s", err)
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
+			return
+		}
+	}
+}
+
+type ListRunsResponse struct {
+	Runs []*job.JobStat `json:"runs"`
+	// Cursor of the next page of runs, if there are more.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// HandleListRunsRequest is the handler for getting the stats of runs of all
+// jobs, filtered and paged through as described by parseStatsQuery.
+// /api/v1/runs
+func HandleListRunsRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseStatsQuery(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		stats, next, err := job.QueryAllStats(cache, q)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(&ListRunsResponse{Runs: stats, NextCursor: next}); err != nil {
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
 			return
 		}
 	}
 }
 
+// parseStatsQuery reads the query parameters for listing stats: from and to,
+// as RFC 3339 times, a comma separated list of statuses, limit, cursor, and
+// order, which is asc or desc, the default.
+func parseStatsQuery(r *http.Request) (job.StatsQuery, error) {
+	params := r.URL.Query()
+	q := job.StatsQuery{Cursor: params.Get("cursor")}
+	var err error
+	if s := params.Get("from"); s != "" {
+		if q.From, err = time.Parse(time.RFC3339, s); err != nil {
+			return q, fmt.Errorf("Invalid from: %w", err)
+		}
+	}
+	if s := params.Get("to"); s != "" {
+		if q.To, err = time.Parse(time.RFC3339, s); err != nil {
+			return q, fmt.Errorf("Invalid to: %w", err)
+		}
+	}
+	if s := params.Get("status"); s != "" {
+		for _, status := range strings.Split(s, ",") {
+			q.Statuses = append(q.Statuses, job.RunStatus(status))
+		}
+	}
+	if s := params.Get("limit"); s != "" {
+		if q.Limit, err = strconv.Atoi(s); err != nil || q.Limit < 1 {
+			return q, job.ErrInvalidStatsLimit
+		}
+	}
+	switch params.Get("order") {
+	case "asc":
+		q.Ascending = true
+	case "", "desc":
+	default:
+		return q, ErrInvalidOrder
+	}
+	return q, nil
+}
+
+// isPaged reports whether the request has any of the query parameters for
+// paging through stats.
+func isPaged(r *http.Request) bool {
+	params := r.URL.Query()
+	for _, param := range []string{"limit", "cursor", "order"} {
+		if _, ok := params[param]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 type ListJobsResponse struct {
 	Jobs map[string]*job.Job `json:"jobs"`
 }
@@ -99,7 +239,7 @@ func HandleListJobsRequest(cache job.JobCache) func(w http.ResponseWriter, r *ht
 		w.Header().Set(contentType, jsonContentType)
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Errorf("Error occurred when marshaling response: %s", err)
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
 			return
 		}
 	}
@@ -114,13 +254,13 @@ func unmarshalNewJob(r *http.Request) (*job.Job, error) {
 
 	body, err := io.ReadAll(io.LimitReader(r.Body, MAX_BODY_SIZE))
 	if err != nil {
-		log.Errorf("Error occurred when reading r.Body: %s", err)
+		requestLogger(r).Errorf("Error occurred when reading r.Body: %s", err)
 		return nil, err
 	}
 	defer r.Body.Close()
 
 	if err := json.Unmarshal(body, newJob); err != nil {
-		log.Errorf("Error occurred when unmarshaling data: %s", err)
+		requestLogger(r).Errorf("Error occurred when unmarshaling data: %s", err)
 		return nil, err
 	}
 
@@ -128,7 +268,8 @@ func unmarshalNewJob(r *http.Request) (*job.Job, error) {
 }
 
 // HandleAddJob takes a job object and unmarshals it to a Job type,
-// and then throws the job in the schedulers.
+// and then throws the job in the schedulers. With ?dry_run=true the job is
+// only validated.
 func HandleAddJob(cache job.JobCache, defaultOwner string) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		newJob, err := unmarshalNewJob(r)
@@ -141,10 +282,15 @@ func HandleAddJob(cache job.JobCache, defaultOwner string) func(http.ResponseWri
 			newJob.Owner = defaultOwner
 		}
 
+		if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run")); dryRun {
+			handleValidateJob(w, cache, newJob)
+			return
+		}
+
 		err = newJob.Init(cache)
 		if err != nil {
 			errStr := "Error occurred when initializing the job"
-			log.Errorf(errStr+": %s", err)
+			requestLogger(r).Errorf(errStr+": %s", err)
 			errorEncodeJSON(errors.New(errStr), http.StatusBadRequest, w)
 			return
 		}
@@ -156,12 +302,36 @@ func HandleAddJob(cache job.JobCache, defaultOwner string) func(http.ResponseWri
 		w.Header().Set(contentType, jsonContentType)
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Errorf("Error occurred when marshaling response: %s", err)
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
 			return
 		}
 	}
 }
 
+type ValidateJobResponse struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors"`
+}
+
+// handleValidateJob responds with everything wrong with the job, without
+// creating it.
+func handleValidateJob(w http.ResponseWriter, cache job.JobCache, j *job.Job) {
+	resp := &ValidateJobResponse{
+		Errors: []string{},
+	}
+	for _, err := range j.Validate(cache) {
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+	resp.Valid = len(resp.Errors) == 0
+
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("Error occurred when marshaling response: %s", err)
+		return
+	}
+}
+
 // HandleJobRequest routes requests to /api/v1/job/{id} to either
 // handleDeleteJob if its a DELETE or handleGetJob if its a GET request.
 func HandleJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
@@ -170,7 +340,7 @@ func HandleJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Re
 
 		j, err := cache.Get(id)
 		if err != nil {
-			log.Errorf("Error occurred when trying to get the job you requested.")
+			requestLogger(r).WithField(job.LogFieldJobId, id).Error("Error occurred when trying to get the job you requested.")
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -192,6 +362,111 @@ func HandleJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Re
 	}
 }
 
+type NextRunsResponse struct {
+	NextRuns []time.Time `json:"next_runs"`
+}
+
+// handleNextRuns responds with up to n upcoming run times of the job, in the
+// given time zone if there is one.
+func handleNextRuns(w http.ResponseWriter, j *job.Job, n int, timezone string) {
+	if n == 0 {
+		n = defaultNextRuns
+	}
+	if n < 1 || n > maxNextRuns {
+		errorEncodeJSON(ErrInvalidN, http.StatusBadRequest, w)
+		return
+	}
+	var loc *time.Location
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+	}
+
+	resp := &NextRunsResponse{
+		NextRuns: j.NextRuns(n),
+	}
+	if loc != nil {
+		for i, t := range resp.NextRuns {
+			resp.NextRuns[i] = t.In(loc)
+		}
+	}
+
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("Error occurred when marshaling response: %s", err)
+		return
+	}
+}
+
+// HandleNextRunsRequest responds with the job's upcoming run times.
+// GET /api/v1/job/{id}/next-runs?n=10
+func HandleNextRunsRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		j, err := cache.Get(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var n int
+		if s := r.URL.Query().Get("n"); s != "" {
+			if n, err = strconv.Atoi(s); err != nil || n == 0 {
+				errorEncodeJSON(ErrInvalidN, http.StatusBadRequest, w)
+				return
+			}
+		}
+		handleNextRuns(w, j, n, r.URL.Query().Get("timezone"))
+	}
+}
+
+type SchedulePreviewRequest struct {
+	// ISO 8601 schedule, as for a job.
+	Schedule string `json:"schedule"`
+	// IANA time zone to give the run times in, e.g. "Europe/Paris".
+	Timezone string `json:"timezone"`
+	// ISO 8601 duration, as for a job. Requests with an invalid one are
+	// rejected.
+	Epsilon                   string `json:"epsilon"`
+	ResumeAtNextScheduledTime bool   `json:"resume_at_next_scheduled_time"`
+	// How many run times to return; 10 by default.
+	N int `json:"n"`
+}
+
+// HandleSchedulePreviewRequest responds with the run times a new job with the
+// given schedule would have, without creating it.
+// POST /api/v1/schedule/preview
+func HandleSchedulePreviewRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &SchedulePreviewRequest{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, MAX_BODY_SIZE)).Decode(req); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		if req.Schedule == "" {
+			errorEncodeJSON(ErrMissingSchedule, http.StatusBadRequest, w)
+			return
+		}
+
+		j := &job.Job{
+			Schedule:                  req.Schedule,
+			Epsilon:                   req.Epsilon,
+			ResumeAtNextScheduledTime: req.ResumeAtNextScheduledTime,
+		}
+		if clk, ok := cache.(job.Clocker); ok && clk.TimeSet() {
+			j.SetClock(clk.Time())
+		}
+		if err := j.InitDelayDuration(false); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		handleNextRuns(w, j, req.N, req.Timezone)
+	}
+}
+
 // HandleDeleteAllJobs is the handler for deleting all jobs
 // DELETE /api/v1/job/all
 func HandleDeleteAllJobs(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
@@ -221,14 +496,40 @@ func handleGetJob(w http.ResponseWriter, _ *http.Request, j *job.Job) {
 	}
 }
 
-// HandleStartJobRequest is the handler for manually starting jobs
+// unmarshalParams reads the parameters of a run from the request body, which
+// may be empty.
+func unmarshalParams(r *http.Request) (map[string]interface{}, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MAX_BODY_SIZE))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	var params map[string]interface{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return nil, fmt.Errorf("Parameters must be a JSON object: %w", err)
+	}
+	return params, nil
+}
+
+type JobRunResponse struct {
+	Run *job.JobRun `json:"run"`
+}
+
+// HandleStartJobRequest is the handler for manually starting jobs. A JSON
+// object in the body is passed to the run as its parameters. It responds
+// straight away with the run, unless wait=true is given, in which case it
+// waits for the run to finish for up to the timeout.
 // /api/v1/job/start/{id}
 func HandleStartJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := mux.Vars(r)["id"]
 		j, err := cache.Get(id)
 		if err != nil {
-			log.Errorf("Error occurred when trying to get the job you requested.")
+			requestLogger(r).WithField(job.LogFieldJobId, id).Error("Error occurred when trying to get the job you requested.")
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -237,10 +538,201 @@ func HandleStartJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *ht
 			return
 		}
 
+		params, err := unmarshalParams(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		wait, timeout, err := parseWait(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
 		j.StopTimer()
-		j.Run(cache)
+		run, err := j.Start(cache, params)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusInternalServerError, w)
+			return
+		}
 
-		w.WriteHeader(http.StatusNoContent)
+		// Runs that are still in progress get 202 Accepted, and can be
+		// polled for at the location given.
+		w.Header().Set("Location", ApiJobPath+j.Id+"/runs/"+run.Id+"/")
+		if wait && waitForRun(r, run, timeout) {
+			respondWithRun(w, run, http.StatusOK)
+			return
+		}
+		respondWithRun(w, run, http.StatusAccepted)
+	}
+}
+
+// HandleJobRunRequest responds with a run of a job, which is either in
+// progress or finished. As when starting a job, wait=true waits for the run to
+// finish for up to the timeout before responding.
+// GET /api/v1/job/{id}/runs/{runId}/
+func HandleJobRunRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		j, err := cache.Get(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		run, err := j.GetRun(cache, mux.Vars(r)["runId"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		wait, timeout, err := parseWait(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		if wait {
+			waitForRun(r, run, timeout)
+		}
+		respondWithRun(w, run, http.StatusOK)
+	}
+}
+
+// HandleCancelJobRunRequest cancels a run of a job that is in progress. It
+// responds straight away, unless wait=true is given, in which case it waits for
+// the run to stop for up to the timeout. Runs that have already finished get
+// 409 Conflict.
+// POST /api/v1/job/{id}/runs/{runId}/cancel/
+func HandleCancelJobRunRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		j, err := cache.Get(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		run, err := j.GetRun(cache, mux.Vars(r)["runId"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		wait, timeout, err := parseWait(r)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
+		if err := run.Cancel(); err != nil {
+			errorEncodeJSON(err, http.StatusConflict, w)
+			return
+		}
+		if wait && waitForRun(r, run, timeout) {
+			respondWithRun(w, run, http.StatusOK)
+			return
+		}
+		respondWithRun(w, run, http.StatusAccepted)
+	}
+}
+
+type BackfillRequest struct {
+	// The range to backfill, from From up to, but not including, To.
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// How many runs can happen at the same time; 1 by default.
+	Parallelism int `json:"parallelism"`
+}
+
+type BackfillResponse struct {
+	Backfill *job.Backfill `json:"backfill"`
+}
+
+// HandleBackfillRequest starts a backfill of a job, which runs it once for
+// each point of its schedule in the given range. It responds straight away
+// with the backfill.
+// POST /api/v1/job/{id}/backfill/
+func HandleBackfillRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		j, err := cache.Get(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		req := &BackfillRequest{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, MAX_BODY_SIZE)).Decode(req); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
+		b, err := j.Backfill(cache, req.From, req.To, req.Parallelism)
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
+		w.Header().Set("Location", ApiJobPath+j.Id+"/backfill/"+b.Id+"/")
+		respondWithBackfill(w, b, http.StatusAccepted)
+	}
+}
+
+// HandleGetBackfillRequest responds with a backfill of a job and its runs.
+// GET /api/v1/job/{id}/backfill/{backfillId}/
+func HandleGetBackfillRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		j, err := cache.Get(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b, err := j.GetBackfill(cache, mux.Vars(r)["backfillId"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		respondWithBackfill(w, b, http.StatusOK)
+	}
+}
+
+func respondWithBackfill(w http.ResponseWriter, b *job.Backfill, status int) {
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(&BackfillResponse{Backfill: b}); err != nil {
+		log.Errorf("Error occurred when marshaling response: %s", err)
+		return
+	}
+}
+
+// parseWait reads the wait and timeout query parameters.
+func parseWait(r *http.Request) (bool, time.Duration, error) {
+	q := r.URL.Query()
+	wait := q.Get("wait") == "true"
+	timeout := DefaultWaitTimeout
+	if s := q.Get("timeout"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return false, 0, ErrInvalidTimeout
+		}
+		timeout = d
+	}
+	return wait, timeout, nil
+}
+
+// waitForRun waits for the run to finish for up to the timeout, or until the
+// request is cancelled, and reports whether it finished.
+func waitForRun(r *http.Request, run *job.JobRun, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-run.Done():
+		return true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+	return false
+}
+
+func respondWithRun(w http.ResponseWriter, run *job.JobRun, status int) {
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(&JobRunResponse{Run: run}); err != nil {
+		log.Errorf("Error occurred when marshaling response: %s", err)
+		return
 	}
 }
 
@@ -251,7 +743,7 @@ func HandleDisableJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *
 		id := mux.Vars(r)["id"]
 		j, err := cache.Get(id)
 		if err != nil {
-			log.Errorf("Error occurred when trying to get the job you requested.")
+			requestLogger(r).WithField(job.LogFieldJobId, id).Error("Error occurred when trying to get the job you requested.")
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -276,7 +768,7 @@ func HandleEnableJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *h
 		id := mux.Vars(r)["id"]
 		j, err := cache.Get(id)
 		if err != nil {
-			log.Errorf("Error occurred when trying to get the job you requested.")
+			requestLogger(r).WithField(job.LogFieldJobId, id).Error("Error occurred when trying to get the job you requested.")
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -294,6 +786,229 @@ func HandleEnableJobRequest(cache job.JobCache) func(w http.ResponseWriter, r *h
 	}
 }
 
+type ListCalendarsResponse struct {
+	Calendars []*job.Calendar `json:"calendars"`
+}
+
+// HandleListCalendarsRequest responds with all calendars.
+// GET /api/v1/calendar/
+func HandleListCalendarsRequest(cache job.CalendarCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := &ListCalendarsResponse{
+			Calendars: cache.GetAllCalendars(),
+		}
+
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
+			return
+		}
+	}
+}
+
+type CalendarResponse struct {
+	Calendar *job.Calendar `json:"calendar"`
+}
+
+func handleGetCalendar(w http.ResponseWriter, status int, c *job.Calendar) {
+	resp := &CalendarResponse{
+		Calendar: c,
+	}
+
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("Error occurred when marshaling response: %s", err)
+		return
+	}
+}
+
+// calendarErrorStatus maps errors from calendar operations to a status code.
+func calendarErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, job.ErrCalendarDoesntExist):
+		return http.StatusNotFound
+	case errors.Is(err, job.ErrCalendarInUse):
+		return http.StatusConflict
+	case errors.Is(err, job.ErrCalendarsUnsupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// HandleAddCalendar creates a calendar, or replaces the one with the same name.
+// POST /api/v1/calendar/
+func HandleAddCalendar(cache job.CalendarCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := &job.Calendar{}
+		body, err := io.ReadAll(io.LimitReader(r.Body, MAX_BODY_SIZE))
+		if err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		defer r.Body.Close()
+		if err := json.Unmarshal(body, c); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+
+		if err := cache.SetCalendar(c); err != nil {
+			errorEncodeJSON(err, calendarErrorStatus(err), w)
+			return
+		}
+
+		handleGetCalendar(w, http.StatusCreated, c)
+	}
+}
+
+// HandleCalendarRequest gets or deletes a calendar.
+// GET, DELETE /api/v1/calendar/{name}/
+func HandleCalendarRequest(cache job.CalendarCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		if r.Method == "DELETE" {
+			if err := cache.DeleteCalendar(name); err != nil {
+				errorEncodeJSON(err, calendarErrorStatus(err), w)
+			} else {
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+
+		c, err := cache.GetCalendar(name)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handleGetCalendar(w, http.StatusOK, c)
+	}
+}
+
+// HandleImportCalendarRequest replaces the periods of a calendar with the
+// events of the iCalendar file in the request body. The calendar is created
+// if it doesn't exist.
+// POST /api/v1/calendar/{name}/ical/
+func HandleImportCalendarRequest(cache job.CalendarCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := &job.Calendar{Name: mux.Vars(r)["name"]}
+		if existing, err := cache.GetCalendar(c.Name); err == nil {
+			// Copied, as the cached calendar may be in use.
+			*c = *existing
+			c.Dates = append([]string(nil), existing.Dates...)
+			c.Weekly = append([]job.WeeklyWindow(nil), existing.Weekly...)
+		}
+
+		defer r.Body.Close()
+		if err := c.ImportICalendar(io.LimitReader(r.Body, MAX_BODY_SIZE)); err != nil {
+			errorEncodeJSON(err, http.StatusBadRequest, w)
+			return
+		}
+		if err := cache.SetCalendar(c); err != nil {
+			errorEncodeJSON(err, calendarErrorStatus(err), w)
+			return
+		}
+
+		handleGetCalendar(w, http.StatusOK, c)
+	}
+}
+
+type ListWorkflowRunsResponse struct {
+	WorkflowRuns []*job.WorkflowRun `json:"workflow_runs"`
+}
+
+// HandleListWorkflowRunsRequest responds with the workflow runs kept, newest
+// first.
+// GET /api/v1/workflow/
+func HandleListWorkflowRunsRequest(cache job.WorkflowCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := &ListWorkflowRunsResponse{
+			WorkflowRuns: cache.GetAllWorkflowRuns(),
+		}
+
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
+			return
+		}
+	}
+}
+
+type WorkflowRunResponse struct {
+	WorkflowRun *job.WorkflowRun `json:"workflow_run"`
+}
+
+// HandleWorkflowRunRequest responds with a workflow run and the state of each
+// of its jobs.
+// GET /api/v1/workflow/{id}/
+func HandleWorkflowRunRequest(cache job.WorkflowCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wf, err := cache.GetWorkflowRun(mux.Vars(r)["id"])
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp := &WorkflowRunResponse{
+			WorkflowRun: wf,
+		}
+
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
+			return
+		}
+	}
+}
+
+type GraphResponse struct {
+	Graph *job.Graph `json:"graph"`
+}
+
+// HandleGraphRequest responds with the dependency graph of the jobs, as JSON,
+// or as text with format=dot or format=mermaid.
+// GET /api/v1/graph/
+func HandleGraphRequest(cache job.JobCache) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g := job.NewGraph(cache)
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+		case "dot":
+			w.Header().Set(contentType, "text/vnd.graphviz; charset=utf-8")
+			fmt.Fprint(w, g.DOT())
+			return
+		case "mermaid":
+			w.Header().Set(contentType, "text/plain; charset=utf-8")
+			fmt.Fprint(w, g.Mermaid())
+			return
+		default:
+			errorEncodeJSON(fmt.Errorf("Unknown graph format %q. Formats supported: json, dot and mermaid", format), http.StatusBadRequest, w)
+			return
+		}
+
+		resp := &GraphResponse{
+			Graph: g,
+		}
+
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			requestLogger(r).Errorf("Error occurred when marshaling response: %s", err)
+			return
+		}
+	}
+}
+
+// requestLogger returns a log entry with the id of the request, for finding
+// everything logged while serving it.
+func requestLogger(r *http.Request) *log.Entry {
+	return log.WithField(middleware.LogFieldRequestId, middleware.RequestId(r.Context()))
+}
+
 type apiError struct {
 	Error string `json:"error"`
 }
@@ -308,6 +1023,31 @@ func errorEncodeJSON(errToEncode error, status int, w http.ResponseWriter) {
 	http.Error(w, string(js), status)
 }
 
+// ownerLocator is implemented by caches that shard jobs across a cluster.
+type ownerLocator interface {
+	OwnerAddress(id string) (string, bool)
+}
+
+// proxyToOwner forwards requests for a job to the node that owns it, since
+// only the owner runs the job and holds its up to date state.
+func proxyToOwner(cache job.JobCache, next http.HandlerFunc) http.HandlerFunc {
+	locator, ok := cache.(ownerLocator)
+	if !ok {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr, local := locator.OwnerAddress(mux.Vars(r)["id"])
+		if local || r.Header.Get(forwardedHeader) != "" {
+			next(w, r)
+			return
+		}
+
+		requestLogger(r).WithField(job.LogFieldJobId, mux.Vars(r)["id"]).Debugf("Proxying %s %s to %s", r.Method, r.URL.Path, addr)
+		r.Header.Set(forwardedHeader, "1")
+		httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: addr}).ServeHTTP(w, r)
+	}
+}
+
 // SetupApiRoutes is used within main to initialize all of the routes
 func SetupApiRoutes(r *mux.Router, cache job.JobCache, defaultOwner string) {
 	// Route for creating a job
@@ -315,19 +1055,70 @@ func SetupApiRoutes(r *mux.Router, cache job.JobCache, defaultOwner string) {
 	// Route for deleting all jobs
 	r.HandleFunc(ApiJobPath+"all/", HandleDeleteAllJobs(cache)).Methods("DELETE")
 	// Route for deleting and getting a job
-	r.HandleFunc(ApiJobPath+"{id}/", HandleJobRequest(cache)).Methods("DELETE", "GET")
+	r.HandleFunc(ApiJobPath+"{id}/", proxyToOwner(cache, HandleJobRequest(cache))).Methods("DELETE", "GET")
+	// Route for previewing a job's next runs
+	r.HandleFunc(ApiJobPath+"{id}/next-runs/", proxyToOwner(cache, HandleNextRunsRequest(cache))).Methods("GET")
+	// Route for getting a run of a job
+	r.HandleFunc(ApiJobPath+"{id}/runs/{runId}/", proxyToOwner(cache, HandleJobRunRequest(cache))).Methods("GET")
+	// Route for cancelling a run of a job
+	r.HandleFunc(ApiJobPath+"{id}/runs/{runId}/cancel/", proxyToOwner(cache, HandleCancelJobRunRequest(cache))).Methods("POST")
+	// Route for backfilling a job
+	r.HandleFunc(ApiJobPath+"{id}/backfill/", proxyToOwner(cache, HandleBackfillRequest(cache))).Methods("POST")
+	// Route for getting a backfill of a job
+	r.HandleFunc(ApiJobPath+"{id}/backfill/{backfillId}/", proxyToOwner(cache, HandleGetBackfillRequest(cache))).Methods("GET")
 	// Route for getting job stats
-	r.HandleFunc(ApiJobPath+"stats/{id}/", HandleListJobStatsRequest(cache)).Methods("GET")
+	r.HandleFunc(ApiJobPath+"stats/{id}/", proxyToOwner(cache, HandleListJobStatsRequest(cache))).Methods("GET")
 	// Route for listing all jops
 	r.HandleFunc(ApiJobPath, HandleListJobsRequest(cache)).Methods("GET")
 	// Route for manually start a job
-	r.HandleFunc(ApiJobPath+"start/{id}/", HandleStartJobRequest(cache)).Methods("POST")
+	r.HandleFunc(ApiJobPath+"start/{id}/", proxyToOwner(cache, HandleStartJobRequest(cache))).Methods("POST")
 	// Route for manually start a job
-	r.HandleFunc(ApiJobPath+"enable/{id}/", HandleEnableJobRequest(cache)).Methods("POST")
+	r.HandleFunc(ApiJobPath+"enable/{id}/", proxyToOwner(cache, HandleEnableJobRequest(cache))).Methods("POST")
 	// Route for manually disable a job
-	r.HandleFunc(ApiJobPath+"disable/{id}/", HandleDisableJobRequest(cache)).Methods("POST")
+	r.HandleFunc(ApiJobPath+"disable/{id}/", proxyToOwner(cache, HandleDisableJobRequest(cache))).Methods("POST")
 	// Route for getting app-level metrics
 	r.HandleFunc(ApiUrlPrefix+"stats/", HandleKalaStatsRequest(cache)).Methods("GET")
+	// List the runs of all jobs.
+	r.HandleFunc(ApiUrlPrefix+"runs/", HandleListRunsRequest(cache)).Methods("GET")
+	// Route for previewing the runs of a schedule
+	r.HandleFunc(ApiSchedulePath+"preview/", HandleSchedulePreviewRequest(cache)).Methods("POST")
+	// Route for getting the dependency graph of the jobs
+	r.HandleFunc(ApiGraphPath, HandleGraphRequest(cache)).Methods("GET")
+
+	if calendars, ok := cache.(job.CalendarCache); ok {
+		// Route for creating or replacing a calendar
+		r.HandleFunc(ApiCalendarPath, HandleAddCalendar(calendars)).Methods("POST")
+		// Route for listing all calendars
+		r.HandleFunc(ApiCalendarPath, HandleListCalendarsRequest(calendars)).Methods("GET")
+		// Route for deleting and getting a calendar
+		r.HandleFunc(ApiCalendarPath+"{name}/", HandleCalendarRequest(calendars)).Methods("DELETE", "GET")
+		// Route for importing an iCalendar file into a calendar
+		r.HandleFunc(ApiCalendarPath+"{name}/ical/", HandleImportCalendarRequest(calendars)).Methods("POST")
+	}
+
+	if workflows, ok := cache.(job.WorkflowCache); ok {
+		// Route for listing workflow runs
+		r.HandleFunc(ApiWorkflowPath, HandleListWorkflowRunsRequest(workflows)).Methods("GET")
+		// Route for getting a workflow run
+		r.HandleFunc(ApiWorkflowPath+"{id}/", HandleWorkflowRunRequest(workflows)).Methods("GET")
+	}
+}
+
+// stopper is implemented by caches that can be shut down gracefully.
+type stopper interface {
+	Stopping() bool
+}
+
+// rejectWritesWhenStopping refuses any request that could change a job once
+// the cache has started shutting down.
+func rejectWritesWhenStopping(s stopper) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && s.Stopping() {
+			errorEncodeJSON(ErrShuttingDown, http.StatusServiceUnavailable, w)
+			return
+		}
+		next(w, r)
+	}
 }
 
 func MakeServer(listenAddr string, cache job.JobCache, defaultOwner string, profile bool) *http.Server {
@@ -352,7 +1143,27 @@ func MakeServer(listenAddr string, cache job.JobCache, defaultOwner string, prof
 		r.Handle("/debug/pprof/mutex", pprof.Handler("mutex"))
 	}
 
-	n := negroni.New(negroni.NewRecovery(), &middleware.Logger{Logger: log.Logger{}}, gzip.Gzip(gzip.DefaultCompression))
+	// Prometheus metrics, from a registry of the server's own so that more than
+	// one server can run in a process.
+	metrics := &middleware.Metrics{Router: r, Prefixes: []string{"/webui/"}}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		job.NewCacheCollector(cache),
+	)
+	registry.MustRegister(job.RunCollectors()...)
+	registry.MustRegister(metrics.Collectors()...)
+	// The response is compressed by the gzip middleware.
+	r.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{DisableCompression: true})).Methods("GET")
+
+	// Spans of requests, which go nowhere unless tracing has been set up.
+	tracing := &middleware.Tracing{Router: r, Prefixes: []string{"/webui/"}}
+
+	n := negroni.New(negroni.NewRecovery(), &middleware.Logger{}, metrics, tracing, gzip.Gzip(gzip.DefaultCompression))
+	if s, ok := cache.(stopper); ok {
+		n.Use(rejectWritesWhenStopping(s))
+	}
 	n.UseHandler(r)
 
 	return &http.Server{
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,13 +11,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ajvb/kala/api/middleware"
 	"github.com/ajvb/kala/job"
 
 	"testing"
 
 	"github.com/gorilla/mux"
+	"github.com/mixer/clock"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func generateNewJobMap() map[string]string {
@@ -138,6 +148,34 @@ func (a *ApiTestSuite) TestHandleAddJobFailureBadSchedule() {
 	a.True(strings.Contains(respErr.Error, "when initializing"))
 }
 
+func (a *ApiTestSuite) TestHandleAddJobDryRun() {
+	t := a.T()
+	cache := job.NewMockCache()
+	jobMap := generateNewJobMap()
+	jobMap["schedule"] = "R/yesterday/PT1H"
+	jobMap["command"] = ""
+	jsonJobMap, err := json.Marshal(jobMap)
+	a.NoError(err)
+	w, req := setupTestReq(t, "POST", ApiJobPath+"?dry_run=true", jsonJobMap)
+
+	handler := HandleAddJob(cache, "")
+	handler(w, req)
+
+	a.Equal(http.StatusOK, w.Code)
+	var resp ValidateJobResponse
+	unmarshallRequestBody(t, w.Result(), &resp)
+	a.False(resp.Valid)
+	a.Len(resp.Errors, 2)
+	a.Empty(cache.GetAll().Jobs)
+
+	w, req = setupTestReq(t, "POST", ApiJobPath+"?dry_run=true", []byte(`{"name": "a", "command": "true"}`))
+	handler(w, req)
+	unmarshallRequestBody(t, w.Result(), &resp)
+	a.True(resp.Valid)
+	a.Empty(resp.Errors)
+	a.Empty(cache.GetAll().Jobs)
+}
+
 func (a *ApiTestSuite) TestDeleteJobSuccess() {
 	t := a.T()
 	cache, j := generateJobAndCache()
@@ -221,6 +259,29 @@ func (a *ApiTestSuite) TestGetJobSuccess() {
 	a.Equal(resp.StatusCode, http.StatusOK)
 }
 
+func (a *ApiTestSuite) TestGetJobHidesWebhookSecrets() {
+	t := a.T()
+	cache, j := generateJobAndCache()
+	j.Webhooks = []*job.Webhook{{Url: "https://example.com/hook", Secret: "s3cr3t"}}
+
+	r := mux.NewRouter()
+	r.HandleFunc(ApiJobPath+"{id}", HandleJobRequest(cache)).Methods("DELETE", "GET")
+	r.HandleFunc(ApiJobPath, HandleListJobsRequest(cache)).Methods("GET")
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, url := range []string{ts.URL + ApiJobPath + j.Id, ts.URL + ApiJobPath} {
+		_, req := setupTestReq(t, "GET", url, nil)
+		resp, err := http.DefaultClient.Do(req)
+		a.NoError(err)
+		body, err := io.ReadAll(resp.Body)
+		a.NoError(err)
+		resp.Body.Close()
+		a.Contains(string(body), "https://example.com/hook")
+		a.NotContains(string(body), "s3cr3t")
+	}
+}
+
 func (a *ApiTestSuite) TestHandleListJobStatsRequest() {
 	cache, j := generateJobAndCache()
 	j.Run(cache)
@@ -247,6 +308,185 @@ func (a *ApiTestSuite) TestHandleListJobStatsRequest() {
 	a.Equal(jobStatsResp.JobStats[0].NumberOfRetries, uint(0))
 	a.True(jobStatsResp.JobStats[0].Success)
 }
+
+func (a *ApiTestSuite) TestHandleListJobStatsRequestListsAllByDefault() {
+	cache, j := generateJobAndCache()
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < job.DefaultStatsLimit+1; i++ {
+		stat := job.NewJobStat(j.Id)
+		stat.RanAt = start.Add(time.Duration(i) * time.Second)
+		j.Stats = append(j.Stats, stat)
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc(ApiJobPath+"stats/{id}", HandleListJobStatsRequest(cache)).Methods("GET")
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	_, req := setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"stats/"+j.Id, nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	var statsResp ListJobStatsResponse
+	unmarshallRequestBody(a.T(), resp, &statsResp)
+	if a.Len(statsResp.JobStats, job.DefaultStatsLimit+1) {
+		a.Equal(j.Stats[0].Id, statsResp.JobStats[0].Id)
+		a.Equal(j.Stats[job.DefaultStatsLimit].Id, statsResp.JobStats[job.DefaultStatsLimit].Id)
+	}
+	a.Empty(statsResp.NextCursor)
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"stats/"+j.Id+"?order=desc", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	unmarshallRequestBody(a.T(), resp, &statsResp)
+	if a.Len(statsResp.JobStats, job.DefaultStatsLimit) {
+		a.Equal(j.Stats[job.DefaultStatsLimit].Id, statsResp.JobStats[0].Id)
+	}
+	a.NotEmpty(statsResp.NextCursor)
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"stats/"+j.Id+"?status=skipped", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func (a *ApiTestSuite) TestHandleListRunsRequest() {
+	cache, j := generateJobAndCache()
+	j.Run(cache)
+	j.Run(cache)
+	failing := job.GetMockJobWithGenericSchedule(time.Now())
+	failing.Command = "false"
+	a.NoError(failing.Init(cache))
+	failing.Run(cache)
+
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	_, req := setupTestReq(a.T(), "GET", ts.URL+ApiUrlPrefix+"runs/?status=failed", nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	var runsResp ListRunsResponse
+	unmarshallRequestBody(a.T(), resp, &runsResp)
+	if a.Len(runsResp.Runs, 1) {
+		a.Equal(failing.Id, runsResp.Runs[0].JobId)
+	}
+	a.Empty(runsResp.NextCursor)
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"stats/"+j.Id+"/?limit=1&order=asc", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	var statsResp ListJobStatsResponse
+	unmarshallRequestBody(a.T(), resp, &statsResp)
+	if a.Len(statsResp.JobStats, 1) {
+		a.Equal(j.Stats[0].Id, statsResp.JobStats[0].Id)
+	}
+	a.NotEmpty(statsResp.NextCursor)
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiUrlPrefix+"runs/?from=yesterday", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (a *ApiTestSuite) TestMetrics() {
+	cache, j := generateJobAndCache()
+	j.Run(cache)
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	_, req := setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+j.Id+"/", nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	resp.Body.Close()
+
+	// An id that is also a segment of the route's path.
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"job/", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	resp.Body.Close()
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+"/metrics", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	a.NoError(err)
+	resp.Body.Close()
+
+	a.Contains(string(body), `kala_jobs{state="enabled"} 1`)
+	a.Contains(string(body), fmt.Sprintf(`kala_job_runs_total{job_id="%s",job_name="%s",status="succeeded",trigger_source="manual"} 1`, j.Id, j.Name))
+	a.Contains(string(body), `kala_http_requests_total{code="200",method="GET",route="/api/v1/job/{id}/"}`)
+	a.Contains(string(body), `kala_http_requests_total{code="404",method="GET",route="/api/v1/job/{id}/"}`)
+}
+
+func (a *ApiTestSuite) TestTracing() {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	cache, j := generateJobAndCache()
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	_, req := setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+j.Id+"/", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	resp.Body.Close()
+
+	// Only the request's span, not those of any job runs.
+	spans := []sdktrace.ReadOnlySpan{}
+	for _, s := range sr.Ended() {
+		if s.SpanKind() == trace.SpanKindServer {
+			spans = append(spans, s)
+		}
+	}
+	if a.Len(spans, 1) {
+		a.Equal("GET "+ApiJobPath+"{id}/", spans[0].Name())
+		a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		a.Equal("00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		a.Contains(spans[0].Attributes(), attribute.Int("http.status_code", http.StatusOK))
+	}
+}
+
+func (a *ApiTestSuite) TestRequestIds() {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	cache, j := generateJobAndCache()
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	// Requests get an id of their own, unless the client sent one.
+	_, req := setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+j.Id+"/", nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	resp.Body.Close()
+	a.NotEmpty(resp.Header.Get(middleware.RequestIdHeader))
+
+	_, req = setupTestReq(a.T(), "GET", ts.URL+ApiJobPath+"not-a-job/", nil)
+	req.Header.Set(middleware.RequestIdHeader, "my-request")
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	resp.Body.Close()
+	a.Equal("my-request", resp.Header.Get(middleware.RequestIdHeader))
+
+	// Everything logged while serving the request has its id.
+	var handlerLogged, completedLogged bool
+	for _, e := range hook.AllEntries() {
+		if e.Data[middleware.LogFieldRequestId] != "my-request" {
+			continue
+		}
+		if e.Data[job.LogFieldJobId] == "not-a-job" {
+			handlerLogged = true
+		}
+		if e.Data["status"] == http.StatusNotFound {
+			completedLogged = true
+		}
+	}
+	a.True(handlerLogged)
+	a.True(completedLogged)
+}
+
 func (a *ApiTestSuite) TestHandleListJobStatsRequestNotFound() {
 	cache, _ := generateJobAndCache()
 	r := mux.NewRouter()
@@ -300,7 +540,7 @@ func (a *ApiTestSuite) TestHandleStartJobRequest() {
 	r.HandleFunc(ApiJobPath+"start/{id}", HandleStartJobRequest(cache)).Methods("POST")
 	ts := httptest.NewServer(r)
 
-	_, req := setupTestReq(t, "POST", ts.URL+ApiJobPath+"start/"+j.Id, nil)
+	_, req := setupTestReq(t, "POST", ts.URL+ApiJobPath+"start/"+j.Id+"?wait=true", nil)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -308,12 +548,144 @@ func (a *ApiTestSuite) TestHandleStartJobRequest() {
 
 	now := time.Now()
 
-	a.Equal(resp.StatusCode, http.StatusNoContent)
+	a.Equal(resp.StatusCode, http.StatusOK)
+	var runResp JobRunResponse
+	unmarshallRequestBody(t, resp, &runResp)
+	a.Equal(job.RunSucceeded, runResp.Run.Status)
 
 	a.Equal(j.Metadata.SuccessCount, uint(1))
 	a.WithinDuration(j.Metadata.LastSuccess, now, 2*time.Second)
 	a.WithinDuration(j.Metadata.LastAttemptedRun, now, 2*time.Second)
 }
+func (a *ApiTestSuite) TestHandleStartJobRequestWithParams() {
+	t := a.T()
+	cache, j := generateJobAndCache()
+	r := mux.NewRouter()
+	r.HandleFunc(ApiJobPath+"start/{id}", HandleStartJobRequest(cache)).Methods("POST")
+	ts := httptest.NewServer(r)
+
+	_, req := setupTestReq(t, "POST", ts.URL+ApiJobPath+"start/"+j.Id+"?wait=true", []byte(`{"date": "2026-10-01"}`))
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	if a.Len(j.Stats, 1) {
+		a.Equal(map[string]interface{}{"date": "2026-10-01"}, j.Stats[0].Params)
+	}
+
+	_, req = setupTestReq(t, "POST", ts.URL+ApiJobPath+"start/"+j.Id, []byte(`["2026-10-01"]`))
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+	a.Len(j.Stats, 1)
+}
+
+func (a *ApiTestSuite) TestHandleJobRunRequest() {
+	t := a.T()
+	cache := job.NewMockCache()
+	j := job.GetMockJobWithGenericSchedule(time.Now())
+	j.Command = "sleep 0.2"
+	a.NoError(j.Init(cache))
+	defer j.StopTimer()
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	_, req := setupTestReq(t, "POST", ts.URL+ApiJobPath+"start/"+j.Id+"/", nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusAccepted, resp.StatusCode)
+	var started JobRunResponse
+	unmarshallRequestBody(t, resp, &started)
+	a.Equal(job.RunRunning, started.Run.Status)
+	location := resp.Header.Get("Location")
+	a.Equal(ApiJobPath+j.Id+"/runs/"+started.Run.Id+"/", location)
+
+	_, req = setupTestReq(t, "GET", ts.URL+location+"?wait=true&timeout=5s", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	var finished JobRunResponse
+	unmarshallRequestBody(t, resp, &finished)
+	a.Equal(job.RunSucceeded, finished.Run.Status)
+	if a.NotNil(finished.Run.Stat) {
+		a.Equal(started.Run.Id, finished.Run.Stat.Id)
+	}
+
+	_, req = setupTestReq(t, "GET", ts.URL+location+"?wait=true&timeout=soon", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	_, req = setupTestReq(t, "GET", ts.URL+ApiJobPath+j.Id+"/runs/not-a-run/", nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (a *ApiTestSuite) TestHandleCancelJobRunRequest() {
+	t := a.T()
+	cache := job.NewMockCache()
+	j := job.GetMockJobWithGenericSchedule(time.Now())
+	j.Command = "sleep 30"
+	a.NoError(j.Init(cache))
+	defer j.StopTimer()
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	run, err := j.Start(cache, nil)
+	a.NoError(err)
+	cancelURL := ts.URL + ApiJobPath + j.Id + "/runs/" + run.Id + "/cancel/"
+
+	_, req := setupTestReq(t, "POST", cancelURL+"?wait=true&timeout=5s", nil)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	var cancelled JobRunResponse
+	unmarshallRequestBody(t, resp, &cancelled)
+	a.Equal(job.RunCancelled, cancelled.Run.Status)
+
+	_, req = setupTestReq(t, "POST", cancelURL, nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (a *ApiTestSuite) TestHandleBackfillRequest() {
+	t := a.T()
+	cache := job.NewMockCache()
+	j := job.GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	a.NoError(j.Init(cache))
+	defer j.StopTimer()
+	ts := httptest.NewServer(MakeServer("", cache, "", false).Handler)
+	defer ts.Close()
+
+	body, err := json.Marshal(&BackfillRequest{From: time.Now().Add(-3 * time.Hour), To: time.Now(), Parallelism: 3})
+	a.NoError(err)
+	_, req := setupTestReq(t, "POST", ts.URL+ApiJobPath+j.Id+"/backfill/", body)
+	resp, err := http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusAccepted, resp.StatusCode)
+	var started BackfillResponse
+	unmarshallRequestBody(t, resp, &started)
+	a.Len(started.Backfill.LogicalTimes, 3)
+	location := resp.Header.Get("Location")
+	a.Equal(ApiJobPath+j.Id+"/backfill/"+started.Backfill.Id+"/", location)
+
+	_, req = setupTestReq(t, "GET", ts.URL+location, nil)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusOK, resp.StatusCode)
+	var found BackfillResponse
+	unmarshallRequestBody(t, resp, &found)
+	a.Equal(started.Backfill.Id, found.Backfill.Id)
+
+	body, err = json.Marshal(&BackfillRequest{From: time.Now(), To: time.Now().Add(-time.Hour)})
+	a.NoError(err)
+	_, req = setupTestReq(t, "POST", ts.URL+ApiJobPath+j.Id+"/backfill/", body)
+	resp, err = http.DefaultClient.Do(req)
+	a.NoError(err)
+	a.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
 func (a *ApiTestSuite) TestHandleStartJobRequestNotFound() {
 	t := a.T()
 	cache := job.NewMockCache()
@@ -419,6 +791,188 @@ func (a *ApiTestSuite) TestSetupApiRoutes() {
 	a.IsType(r, mux.NewRouter())
 }
 
+func (a *ApiTestSuite) TestWritesRejectedWhenShuttingDown() {
+	cache := job.NewMockCache()
+	cache.Clock.SetClock(clock.NewMockClock())
+	j := job.GetMockJobWithGenericSchedule(cache.Time().Now())
+	a.NoError(j.Init(cache))
+	srv := MakeServer("", cache, "", false)
+	a.NoError(cache.Shutdown(context.Background()))
+
+	w, req := setupTestReq(a.T(), "POST", ApiJobPath+"disable/"+j.Id+"/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusServiceUnavailable, w.Code)
+	a.False(j.Disabled)
+
+	w, req = setupTestReq(a.T(), "GET", ApiJobPath+j.Id+"/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+}
+
+func (a *ApiTestSuite) TestCalendarRoutes() {
+	cache := job.NewLockFreeJobCache(job.NewMemoryDB())
+	cache.Clock.SetClock(clock.NewMockClock())
+	srv := MakeServer("", cache, "", false)
+
+	body := []byte(`{"name": "holidays", "dates": ["2026-12-25"]}`)
+	w, req := setupTestReq(a.T(), "POST", ApiCalendarPath, body)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusCreated, w.Code)
+
+	ical := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260101\r\nSUMMARY:New Year\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	w, req = setupTestReq(a.T(), "POST", ApiCalendarPath+"holidays/ical/", []byte(ical))
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+
+	w, req = setupTestReq(a.T(), "GET", ApiCalendarPath+"holidays/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var calResp CalendarResponse
+	unmarshallRequestBody(a.T(), w.Result(), &calResp)
+	a.Equal([]string{"2026-12-25"}, calResp.Calendar.Dates)
+	if a.Len(calResp.Calendar.Periods, 1) {
+		a.Equal("New Year", calResp.Calendar.Periods[0].Summary)
+	}
+
+	j := job.GetMockJobWithGenericSchedule(cache.Time().Now())
+	j.Calendars = []string{"holidays"}
+	a.NoError(j.Init(cache))
+
+	w, req = setupTestReq(a.T(), "DELETE", ApiCalendarPath+"holidays/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusConflict, w.Code)
+
+	w, req = setupTestReq(a.T(), "GET", ApiCalendarPath+"missing/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusNotFound, w.Code)
+}
+
+func (a *ApiTestSuite) TestWorkflowRoutes() {
+	cache := job.NewMockCache()
+	parent := job.GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	a.NoError(parent.Init(cache))
+	defer parent.StopTimer()
+	child := job.GetMockJob()
+	child.ParentJobs = []string{parent.Id}
+	a.NoError(child.Init(cache))
+	parent.Run(cache)
+	<-cache.GetAllWorkflowRuns()[0].Done()
+	srv := MakeServer("", cache, "", false)
+
+	w, req := setupTestReq(a.T(), "GET", ApiWorkflowPath, nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var listResp ListWorkflowRunsResponse
+	unmarshallRequestBody(a.T(), w.Result(), &listResp)
+	if !a.Len(listResp.WorkflowRuns, 1) {
+		return
+	}
+	id := listResp.WorkflowRuns[0].Id
+
+	w, req = setupTestReq(a.T(), "GET", ApiWorkflowPath+id+"/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var resp WorkflowRunResponse
+	unmarshallRequestBody(a.T(), w.Result(), &resp)
+	a.Equal(parent.Id, resp.WorkflowRun.RootJob)
+	a.Equal(job.WorkflowSucceeded, resp.WorkflowRun.Status)
+	if a.Contains(resp.WorkflowRun.Jobs, child.Id) {
+		a.Equal(job.WorkflowSucceeded, resp.WorkflowRun.Jobs[child.Id].Status)
+	}
+
+	w, req = setupTestReq(a.T(), "GET", ApiWorkflowPath+"missing/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusNotFound, w.Code)
+}
+
+func (a *ApiTestSuite) TestHandleGraphRequest() {
+	cache := job.NewMockCache()
+	parent := job.GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	parent.Name = "extract"
+	a.NoError(parent.Init(cache))
+	defer parent.StopTimer()
+	child := job.GetMockJob()
+	child.Name = "load"
+	child.ParentJobs = []string{parent.Id}
+	child.Disabled = true
+	a.NoError(child.Init(cache))
+	srv := MakeServer("", cache, "", false)
+
+	w, req := setupTestReq(a.T(), "GET", ApiGraphPath, nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var resp GraphResponse
+	unmarshallRequestBody(a.T(), w.Result(), &resp)
+	a.Len(resp.Graph.Nodes, 2)
+	if a.Len(resp.Graph.Edges, 1) {
+		a.Equal(parent.Id, resp.Graph.Edges[0].From)
+		a.Equal(child.Id, resp.Graph.Edges[0].To)
+	}
+
+	w, req = setupTestReq(a.T(), "GET", ApiGraphPath+"?format=dot", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	a.Contains(w.Body.String(), `"`+parent.Id+`" -> "`+child.Id+`";`)
+
+	w, req = setupTestReq(a.T(), "GET", ApiGraphPath+"?format=mermaid", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	a.Contains(w.Body.String(), "n0 --> n1")
+
+	w, req = setupTestReq(a.T(), "GET", ApiGraphPath+"?format=png", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (a *ApiTestSuite) TestHandleNextRunsRequest() {
+	cache := job.NewMockCache()
+	cache.Clock.SetClock(clock.NewMockClock(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	j := job.GetMockJob()
+	j.Schedule = "R/2026-01-01T10:00:00Z/PT12H"
+	a.NoError(j.Init(cache))
+	srv := MakeServer("", cache, "", false)
+
+	w, req := setupTestReq(a.T(), "GET", ApiJobPath+j.Id+"/next-runs/?n=3&timezone=Asia/Tokyo", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var resp NextRunsResponse
+	unmarshallRequestBody(a.T(), w.Result(), &resp)
+	if a.Len(resp.NextRuns, 3) {
+		a.Equal("2026-01-01T19:00:00+09:00", resp.NextRuns[0].Format(time.RFC3339))
+		a.Equal("2026-01-02T07:00:00+09:00", resp.NextRuns[1].Format(time.RFC3339))
+	}
+
+	w, req = setupTestReq(a.T(), "GET", ApiJobPath+j.Id+"/next-runs/?n=-1", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusBadRequest, w.Code)
+
+	w, req = setupTestReq(a.T(), "GET", ApiJobPath+"missing/next-runs/", nil)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusNotFound, w.Code)
+}
+
+func (a *ApiTestSuite) TestHandleSchedulePreviewRequest() {
+	cache := job.NewMockCache()
+	cache.Clock.SetClock(clock.NewMockClock(time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)))
+	srv := MakeServer("", cache, "", false)
+
+	body := []byte(`{"schedule": "R/2026-01-01T10:30:00Z/PT1H", "resume_at_next_scheduled_time": true}`)
+	w, req := setupTestReq(a.T(), "POST", ApiSchedulePath+"preview/", body)
+	srv.Handler.ServeHTTP(w, req)
+	a.Equal(http.StatusOK, w.Code)
+	var resp NextRunsResponse
+	unmarshallRequestBody(a.T(), w.Result(), &resp)
+	if a.Len(resp.NextRuns, 10) {
+		a.Equal(time.Date(2026, time.January, 1, 12, 30, 0, 0, time.UTC), resp.NextRuns[0].UTC())
+	}
+
+	for _, body := range []string{`{}`, `{"schedule": "R/tomorrow/PT1H"}`, `{"schedule": "R/2026-01-01T10:30:00Z/PT1H", "timezone": "Mars/Olympus"}`, `{"schedule": "R/2026-01-01T10:30:00Z/PT1H", "epsilon": "5 seconds"}`} {
+		w, req = setupTestReq(a.T(), "POST", ApiSchedulePath+"preview/", []byte(body))
+		srv.Handler.ServeHTTP(w, req)
+		a.Equal(http.StatusBadRequest, w.Code, body)
+	}
+}
+
 // setupTestReq constructs the writer recorder and request obj for use in tests
 func setupTestReq(t assert.TestingT, method, path string, data []byte) (*httptest.ResponseRecorder, *http.Request) {
 	w := httptest.NewRecorder()
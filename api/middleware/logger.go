@@ -1,25 +1,91 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"time"
+	"unicode"
 
+	uuid "github.com/nu7hatch/gouuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 )
 
+// RequestIdHeader is the header that carries the id of a request. Clients can
+// set it to have their own id logged, and it is set on every response.
+const RequestIdHeader = "X-Request-Id"
+
+// LogFieldRequestId is the log field of the id of the request being served.
+const LogFieldRequestId = "request_id"
+
+// Request ids sent by clients longer than this are replaced.
+const maxRequestIdLength = 128
+
+type requestIdKey struct{}
+
+// RequestId returns the id of the request that ctx is the context of, or "" if
+// it didn't go through Logger.
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
 // Logger is a middleware handler that logs the request as it goes in and the response as it goes out.
+// Each request gets an id, logged with everything about it, which handlers can
+// get with RequestId.
 type Logger struct {
-	// Logger inherits from log.Logger used to log messages with the Logger middleware
+	// Logger inherits from log.Logger used to log messages with the Logger middleware.
+	// A zero Logger logs through logrus' standard logger instead, so that it
+	// uses the same format as the rest of the logs.
 	log.Logger
 }
 
+func (l *Logger) logger() *log.Logger {
+	if l.Out == nil {
+		return log.StandardLogger()
+	}
+	return &l.Logger
+}
+
 func (l *Logger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	start := time.Now()
-	l.Infof("Started %s %s", r.Method, r.URL.Path)
+	id := requestId(r)
+	rw.Header().Set(RequestIdHeader, id)
+	r = r.WithContext(context.WithValue(r.Context(), requestIdKey{}, id))
+
+	entry := l.logger().WithFields(log.Fields{
+		LogFieldRequestId: id,
+		"method":          r.Method,
+		"path":            r.URL.Path,
+	})
+	entry.Debugf("Started %s %s", r.Method, r.URL.Path)
 
 	next(rw, r)
 
 	res := rw.(negroni.ResponseWriter)
-	l.Infof("Completed %v %s in %v", res.Status(), http.StatusText(res.Status()), time.Since(start))
+	entry.WithFields(log.Fields{
+		"status":   res.Status(),
+		"duration": time.Since(start).Seconds(),
+	}).Infof("Completed %s %s with %v %s in %v", r.Method, r.URL.Path, res.Status(), http.StatusText(res.Status()), time.Since(start))
+}
+
+// requestId returns the id the client sent with the request, if it's one that
+// can be logged as is, or a new one.
+func requestId(r *http.Request) string {
+	id := r.Header.Get(RequestIdHeader)
+	valid := id != "" && len(id) <= maxRequestIdLength
+	for _, c := range id {
+		if c > unicode.MaxASCII || !unicode.IsPrint(c) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return id
+	}
+	u4, err := uuid.NewV4()
+	if err != nil {
+		return ""
+	}
+	return u4.String()
 }
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/negroni"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kala",
+		Name:      "http_requests_total",
+		Help:      "Requests to the API, by route and response status code.",
+	}, []string{"method", "route", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kala",
+		Name:      "http_request_duration_seconds",
+		Help:      "How long requests to the API took to serve, by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Metrics is a middleware handler that counts and times requests by the route
+// of the router that they match, so that requests for different jobs are
+// counted together.
+type Metrics struct {
+	Router *mux.Router
+	// Requests for paths under these prefixes are counted under the prefix.
+	Prefixes []string
+}
+
+// Collectors returns the collectors of the metrics about requests, for
+// registering with a prometheus registry.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{requestsTotal, requestDuration}
+}
+
+func (m *Metrics) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	start := time.Now()
+	route := routeOf(m.Router, m.Prefixes, r)
+
+	next(rw, r)
+
+	res := rw.(negroni.ResponseWriter)
+	requestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(res.Status())).Inc()
+	requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+}
+
+// routeOf returns the path template of the route of the router that the
+// request matches, e.g. /api/v1/job/{id}/, or the prefix the path is under.
+// The router hasn't handled the request yet, so the route is matched here
+// rather than taken from mux.CurrentRoute.
+func routeOf(router *mux.Router, prefixes []string, r *http.Request) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return prefix
+		}
+	}
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return "unmatched"
+	}
+	template, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
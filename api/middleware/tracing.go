@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans go to the global tracer provider, which does nothing unless tracing
+// has been set up.
+var tracer = otel.Tracer("github.com/ajvb/kala/api")
+
+// Tracing is a middleware handler that starts a span for each request, named
+// after the route of the router that it matches, carrying on the trace of the
+// client if the request has a trace context.
+type Tracing struct {
+	Router *mux.Router
+	// Requests for paths under these prefixes are named after the prefix.
+	Prefixes []string
+}
+
+func (t *Tracing) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	route := routeOf(t.Router, t.Prefixes, r)
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := tracer.Start(ctx, r.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPTargetKey.String(r.URL.Path),
+		),
+	)
+	defer span.End()
+
+	next(rw, r.WithContext(ctx))
+
+	status := rw.(negroni.ResponseWriter).Status()
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
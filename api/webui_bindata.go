@@ -342,7 +342,7 @@ func webuiJsFormdataJs() (*asset, error) {
 	return a, nil
 }
 
-var _webuiJsActionsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x58\x5f\x6f\xe3\x36\x0c\x7f\xcf\xa7\x60\x5e\x6a\x1b\x08\x8c\xee\x61\x18\xd0\xc2\xb8\x87\x75\x07\x5c\xb1\x6e\xc3\xdd\xbd\x15\x45\x20\xdb\x4c\xa2\x56\xb6\x72\x92\xdc\x6b\x71\x97\xef\x3e\x50\x91\x6c\xd9\x8e\x93\x6c\xc5\xfe\xbc\xd9\x14\xf9\x23\xf9\x23\x2d\x51\x7e\x66\x0a\x58\x61\xb8\xac\x35\x64\x10\xaf\x9a\xda\xbe\xc4\x09\x7c\x9b\xcd\x00\x68\x59\xe1\x97\x3b\xbd\x86\x0c\xa2\xcf\x1b\xae\x61\xc5\x51\x94\xc0\x35\xc9\x1b\xae\xb0\x4c\xa3\xeb\x19\x80\xb7\x84\x0d\xd3\x1f\xdd\xca\x7b\x52\xd5\xb1\xb5\xd0\x84\x08\x00\xc0\x57\x10\xcf\xf7\xa2\xb4\x66\x15\x7a\x39\x80\x36\x52\x61\x5a\xca\x38\xd2\x68\x7e\x56\xc8\x0c\xfe\xa2\x54\xb4\x80\x88\xf4\xa2\x85\x8b\x24\xb9\x76\xfa\x0a\x4d\xa3\x6a\x58\x31\xa1\x71\x2f\xdb\xb5\x1e\x9c\x03\xf3\xba\x45\xc8\xb2\x0c\x2e\xe1\xe2\x02\xbc\xdb\x42\x56\x15\xab\xcb\x73\x3c\x3b\xd5\xf3\x9d\x3b\xb9\x51\x8d\x15\xef\x3c\x8b\x1d\xc9\x96\x58\x00\x23\xd7\x6b\x81\xb7\x32\xbf\xe1\x9a\xe5\x02\xcb\xab\x96\xc3\x98\x07\xb1\x91\xf1\xa3\xcc\x21\xf3\x51\x32\xc3\xd2\x47\x99\xeb\x7b\x5e\x3e\xf8\x68\x28\xe5\x47\x99\x77\x56\x40\x36\x69\xe9\xa0\xe1\x9d\xf7\x9f\x62\x4d\x92\x5b\x99\x93\x7e\x4a\x8e\xae\xda\x35\xa7\x1e\x2c\x7a\xfc\x7d\x6e\xbb\xc5\x3e\xf4\x4e\x6f\x22\xe6\x80\x4f\xc3\x94\xf9\x55\xb2\x92\xd7\xeb\xa8\x85\x7b\x62\x82\x85\xde\x78\x99\xa4\x66\x83\x75\xbf\xff\x7c\x26\xbd\xea\x04\x84\x45\x0b\xe0\xe5\xc2\x52\xdd\x05\xea\x9e\x7c\xa8\x6d\xba\x6f\x89\xb4\xe3\xec\x4d\x81\xda\x5e\x99\x8c\x54\x35\xf5\xdf\x0f\xf3\x0f\x25\x2b\xae\x31\x65\x42\xc4\xf7\x6d\x3c\x36\xf8\x3d\x2e\xa1\x2d\xda\x85\x1a\xbf\x7a\x93\x2e\x13\x85\x5a\x8a\x67\x0c\x13\x02\xd0\x68\x3e\xf3\x0a\x65\x63\xfc\xfa\x02\x7e\xfa\xf1\x32\x69\x55\x76\xfe\xf1\x61\xc8\x8c\x42\xdd\x08\xa3\x43\x3c\xf7\x71\xd8\xb8\xd6\x96\xa2\x38\x6c\xb2\x21\xc2\xa0\xa3\x87\xf4\x46\x0b\x6a\xf2\x49\x42\x4b\x14\x68\xde\x50\xfa\x23\x9c\xb6\xd0\xff\x06\xad\xad\xec\x48\xe7\x85\xd9\xb4\xc1\xd9\xb6\x6b\xd3\x19\x13\x54\xd8\x8d\xae\x4f\x50\x8f\xf2\x53\x0c\x85\xe5\x6c\xc1\x2c\xc4\x54\xcc\x21\xff\x01\x42\x7c\x3e\x73\x67\x70\x47\x99\x26\x3d\x93\xa3\xcc\x9d\x6e\xcc\x41\x49\x7a\x8f\xc7\x5b\xf6\x64\xd3\xf6\xb1\x0a\x66\x8a\x4d\x07\x86\x2f\x7d\xac\x42\xd6\x5a\x0a\x4c\x51\x29\xa9\x68\x75\xba\xb6\x6b\x34\x77\x68\x14\x2f\x74\x50\xdc\xbf\x5c\xd9\x23\x9f\x40\xb5\x47\x8f\xff\xb3\xf6\x3f\xb0\xbd\xec\x0f\x4b\x17\x19\x64\xe0\x54\xee\x2f\x1f\xae\xa7\x2a\xe2\x48\x8a\x16\xde\x6c\x5c\x99\x80\xd2\x5b\x99\xff\x53\x7c\x3a\xf4\xff\x21\x9f\x0a\xf5\xf6\x3c\x32\x29\x01\x3b\x29\xe9\xad\x9d\x50\xa6\xb9\x54\xb8\x52\xa8\x37\x01\x97\x4a\x36\x06\x3f\xdc\x74\xee\x69\xa4\x71\x42\x3b\xc1\x45\x84\x18\x85\xe1\x99\x0d\xd7\x1d\x6f\xed\x31\x00\x28\x34\x8e\xcd\x5d\x7d\x0f\x22\xdc\xf9\x66\x3e\x3c\xef\xe8\x26\xaf\xb8\x9b\x0b\xfb\xdb\xa5\x46\x81\x85\x91\xea\x8c\x51\xb2\x6e\x84\x68\xf1\xbb\xa1\xee\xdb\x2e\x94\x29\xd4\x68\xde\x4b\x55\x79\xa1\x51\xaf\x41\xbc\xa4\xb2\x92\xaa\x82\xcc\x36\x07\x29\xde\x30\xc3\xe2\x52\x16\x4d\x85\xb5\x49\xbf\x34\xa8\x5e\x3f\xb9\xa0\xba\xe8\x82\x42\x10\x04\xd6\x46\x71\xa4\x2f\x84\xc0\x52\xf7\x1a\x0f\xb4\xb8\x41\xd5\x49\xbe\x6e\xb8\x40\x88\xe7\x31\x89\x21\xf3\x18\x69\x8d\x2f\x26\x4e\x92\xb4\x94\x35\x8e\x7b\xe7\x09\x5f\x21\xb3\x48\xe9\x33\x13\x0d\x0e\xba\x87\x54\x9e\x99\xe8\xab\xfc\xd0\x53\xa1\x3a\x5a\x14\xaa\x21\xf5\x60\x85\x4b\x66\x96\xe4\x76\xa9\x8b\x0d\x96\x8d\xc0\x72\x69\x78\x85\xd1\x70\x5b\x7f\x94\xf9\xfd\x13\xbe\x3e\xd0\xc5\xc6\x7a\x21\x08\x59\x47\x09\xbc\xb3\x43\x23\x5c\x85\xe3\xfb\xa8\x7b\x5a\xaf\x8f\x32\xb7\x17\x90\x43\xf8\xfb\xab\x09\xb9\x20\x0f\xdf\xbf\x43\x14\x9d\x82\x2b\xe5\x47\xaa\xf2\x08\xae\xad\x3d\x64\x30\x9f\x3f\x33\x71\x0a\x88\x2e\x37\x11\xf9\xec\x24\xfb\x4d\xa0\x2f\x54\x68\x2b\x75\x8c\x1e\x8a\xfd\xe2\x02\xb6\x4c\x69\xfc\x50\x1b\x62\x2b\x21\x8c\xcb\x53\x21\x6c\x90\x95\xa8\x74\x44\xd6\xd6\xa8\xef\xa2\xdf\xbd\x23\xb7\xb7\x9f\x7e\xff\x2d\xb5\x4e\xad\xc7\xc1\x71\x0b\xf6\x34\x84\x18\x50\x29\x18\x1f\xd9\xd3\x77\x36\x1f\xd4\x82\x2c\x87\xa8\x27\x12\xc2\x97\x2d\x16\x06\xcb\x25\x6d\x62\xb2\xd6\xb8\x2c\x64\x79\x92\xbc\x54\x6f\x05\x37\x71\xb4\x88\x92\x74\xc5\x85\x41\xd5\xed\xab\xc5\xa6\xa9\x9f\x26\x27\x8e\xf9\xdc\xae\x0f\x06\x8d\xb4\x62\xdb\x00\x41\x96\xa3\x21\xa8\x05\x68\x6b\x66\xb5\x86\x13\xcb\x44\xfd\x0e\x94\xaa\x9f\x50\xcf\x6e\x36\x7c\x9a\x2e\xcd\x60\x42\xe9\xd1\x3f\xbe\x31\x77\x88\xde\xae\xc4\xbc\x59\xc7\xe1\x80\x64\x7f\x17\x8c\xff\x29\x90\xca\x81\x9b\xc5\x41\x70\x7f\xbd\x3d\x6f\x44\xed\x13\x63\x8f\x11\xff\x61\x0e\x39\x3b\xb5\xed\xa6\xd6\x32\x4e\x26\xd8\x3c\xf6\x2f\xc1\x9e\x3c\xf6\xe7\x81\x5b\x74\x49\x5c\xcf\x66\xbb\x84\x10\xff\x0c\x00\x00\xff\xff\xe8\x49\x9d\xdf\xb9\x11\x00\x00")
+var _webuiJsActionsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x58\xdf\x6f\xdb\x36\x10\x7e\xf7\x5f\x41\xbf\x54\x12\x60\x10\xd9\xc3\x30\xa0\x41\xd0\x87\x65\x1d\x5a\x2c\xdb\xd0\xf6\x2d\x08\x0c\x5a\x3a\x5b\x5a\xf4\xc3\x25\xa9\x34\x41\xeb\xff\x7d\x77\x14\x29\x51\x92\x65\xb9\x2d\xb2\x35\x2f\x91\x8f\x77\xdf\xdd\x7d\x77\x22\x8f\x7a\x10\x92\x89\x58\x67\x55\xa9\xd8\x15\x0b\xb7\x75\x69\x7e\x84\x11\xfb\xbc\x58\x30\xf6\x80\xcb\x12\x3e\xde\xa8\x1d\xae\x06\x1f\xd2\x4c\xb1\x6d\x06\x79\xc2\xf0\x01\xe5\x75\x26\x21\xe1\xc1\x25\x6a\x3a\x4b\x96\x0a\xf5\xce\xae\xbc\x26\x55\x15\x1a\x0b\x45\x88\x0c\xff\xb2\x2d\x0b\x97\x8d\x88\x97\xa2\x00\x27\x67\x4c\xe9\x4a\x02\x4f\xaa\x30\x50\xa0\x7f\x95\x20\x34\xfc\x26\x65\xb0\x62\x01\xe9\xe1\xff\x26\x92\xe8\xd2\xea\x4b\xd0\xb5\x2c\xd9\x56\xe4\x0a\x1a\xd9\xa1\xf5\x60\x1d\xe8\xa7\x3d\xb0\xab\xab\x2b\x76\xc1\x5e\xbc\x60\xce\x6d\x5c\x15\x85\x28\x93\x73\x3c\x5b\xd5\xf3\x9d\x5b\xb9\x96\xb5\x11\x1f\x1c\x8b\x1d\xc9\x86\x58\xc6\x74\xb5\xdb\xe5\xf0\xb6\xda\x5c\x67\x4a\x6c\x72\x48\x5e\xb6\x1c\x86\x99\x17\x1b\x19\xff\x53\x6d\xd0\xd0\x46\x29\xb4\xe0\x28\x50\xb7\x59\x72\xe7\xa2\xa1\x94\x51\xd6\x59\x31\xb2\xe1\x89\x85\x66\xaf\x9c\x7f\x0e\x25\x49\xd0\x2d\xe9\x73\x72\xf4\xb2\x5d\xb3\xea\xde\xa2\xc3\x6f\x72\x3b\xac\x9a\xd0\x3b\xbd\x89\x98\x3d\x3e\xb5\x90\xfa\x8f\x4a\x24\x59\xb9\x0b\x5a\xb8\x7b\x91\x0b\xdf\x1b\xda\x72\x9d\x42\xd9\xef\x3f\x97\x49\xaf\x3a\x1e\x61\x58\x94\x2c\x59\x19\xaa\xbb\x40\xed\x93\x0b\xb5\x4d\xf7\x7b\x22\xed\x38\xfb\xae\x40\x4d\xaf\x4c\x46\x2a\xeb\xf2\xdb\xc3\xfc\x5b\x56\x45\xa6\x80\x8b\x3c\x0f\x6f\xdb\x78\x4c\xf0\x0d\x2e\xa1\xad\xda\x85\x12\x3e\x39\x93\x2e\x13\x09\xaa\xca\x1f\xc0\x4f\x08\x1d\x83\xfe\x90\x15\x50\xd5\xda\xad\xaf\xd8\x2f\x3f\x5f\x44\xad\xca\xc1\x3d\xde\x0d\x99\x41\xfd\x3a\xd7\xca\xc7\xb3\x2f\x87\x89\x6b\x67\x28\x0a\xfd\x26\x1b\x22\x0c\x3a\x7a\x48\x2f\xd2\x4a\x1a\x53\x84\x26\x90\x83\x86\x67\xe1\xb4\x85\xfe\x2f\x68\x6d\x65\x27\x3a\xcf\xcf\xa6\x0d\xce\xb4\x5d\x9b\xce\x98\xa0\xd8\x6c\x74\x7d\x82\x7a\x94\xcf\x31\xe4\x97\xb3\x05\x33\x10\x53\x31\xfb\xfc\x7b\x08\xe1\xf9\xcc\x9d\xc1\x1d\x65\x1a\xf5\x4c\x4e\x32\x37\xdf\x98\x83\x92\xf4\x1e\x4f\xb7\xec\x6c\xd3\xf6\xb1\x62\xa1\xe3\xb4\x03\x83\xc7\x3e\x56\x8c\x9b\x74\x95\x03\x07\x29\x2b\x49\xab\xd3\xb5\xc5\x24\x6e\x40\xcb\x2c\x56\x5e\x71\xbf\xba\xb2\x27\x5e\x81\xa2\x41\x0f\xff\xb7\xf6\x3f\xb2\xbd\x34\x87\xa5\x8d\x0c\x0f\x4c\xab\x72\x7b\x71\x77\x39\x55\x11\x4b\x12\x56\xc5\x9a\x8d\x2b\xe3\x51\xfa\xbb\x14\xfb\xf4\x99\x08\x75\xf0\x3f\x18\xa3\x3d\xb6\x4c\x80\x66\x16\x72\xc4\x9e\xa4\x0b\xdb\x5d\x3d\x1f\x5b\x84\xfe\x03\xb6\x1f\x2e\xec\xcf\xeb\x3d\x4a\xa0\x21\x73\x6f\x06\xba\x69\x2e\x25\x6c\x51\xcb\xef\x3c\x89\xc1\xc3\x9b\xeb\xce\x3d\x4d\x80\x56\x68\x06\xde\x80\x10\x03\x3f\x3c\x8d\xd3\x7b\xc7\x5b\x7b\x6a\x32\xc0\xa9\x64\x6c\x6e\x5f\x87\xa3\x08\x37\xee\xdd\x9f\x03\xd9\x99\x7e\x39\x06\x61\x5b\xfd\xf8\x7c\xa9\xea\x4d\x91\xd9\x39\xbc\x7f\x3c\x29\x3c\xdb\x62\xe4\xf0\x8c\xd1\xbd\xac\xf3\xbc\xc5\xef\x86\xe8\xcf\x07\x5f\x86\xac\x82\x7e\x5d\xc9\xc2\x09\xb5\x7c\xf2\xa2\x25\x95\x2d\xae\xa2\x1d\x75\x17\x29\x5e\xe3\xf8\x1d\x26\x55\x5c\x17\x50\x6a\xfe\xb1\x06\xf9\xf4\xde\x06\xd5\x45\xe7\x55\x92\x20\x50\x53\x66\x40\x3b\x12\x81\x71\xfb\x33\x1c\x68\x65\x1a\x64\x27\xf9\x94\x66\x39\xe0\x4d\x29\x24\x31\x5a\x5a\x23\x5e\xc2\xa3\x0e\xa3\x08\x33\x2e\x61\xdc\x7c\xf7\xf0\x84\xba\x64\xc2\x1f\x44\x5e\xc3\xa0\xfd\x48\x05\xe5\x7d\x95\x9f\x7a\x2a\x54\x43\x83\x42\xf5\xa3\x26\x2e\x60\x2d\xf4\x9a\xdc\xae\x55\x9c\x42\x52\xe3\x40\xbb\xd6\xf8\xfa\x04\xc3\x63\x14\x09\xbe\x45\xd3\x3b\xba\x48\x1a\x2f\x04\x51\x95\xa8\xf7\xca\x0c\xe9\x78\xd3\xf0\xae\x4b\xa3\xce\x69\xbd\x22\x8e\xb9\xf0\x1d\xc3\x6f\xae\x82\xe4\x82\x3c\x7c\xf9\xc2\x82\x60\x0e\x2e\xa9\xde\x51\x95\x47\x70\x6d\xed\x11\x6c\xb9\x44\xb8\x39\x20\xba\x4c\x06\xe4\xb3\x93\x34\xbb\x48\x5f\x28\xc1\x54\xea\x14\x3d\x14\x3b\xde\x47\xf7\x42\x2a\x78\x53\x6a\x62\x2b\x22\x8c\x8b\xb9\x10\x52\x10\x09\x48\x15\x90\xb5\x31\xea\xbb\xe8\x77\xef\xc8\xed\xdb\xf7\x7f\xfd\xc9\x8d\x53\xe3\x71\x30\xde\x30\x33\x7d\xb0\x90\xe1\x78\xc1\xc6\x23\xd2\xf4\x1d\xd9\x05\xb5\x22\xcb\x21\xea\x4c\x42\xf0\xb8\xc7\x57\x06\x3b\x8a\x76\x41\x1c\x6f\x60\x1d\x57\xc9\x2c\x79\x5c\xed\xf3\x4c\x87\xc1\x2a\x88\xf8\x36\xcb\xb1\x97\xbb\x8d\x39\x4e\xeb\xf2\x7e\x72\xc2\x5b\x2e\xcd\xfa\x60\xb0\xe3\x85\xd8\x7b\x08\x18\xc1\x24\x40\x5b\x33\xa3\x35\x9c\x10\x27\xea\x77\xa4\x54\xfd\x84\x7a\x76\x8b\xe1\xd3\x74\x69\x06\x13\x61\x8f\xfe\xf1\x17\x8a\x0e\xd1\xd9\x25\xb0\xa9\x77\xa1\x3f\x90\x9a\xcf\x33\xe3\x6f\x38\xa4\x72\xe4\x26\x77\x14\xdc\x7d\x4e\x38\xef\x4a\xd0\x27\xc6\x1c\x21\xee\xc5\x1c\x72\x36\xb7\xed\x72\x63\x19\x46\x13\x6c\x9e\xfa\x76\x63\x4e\x1e\xf3\xb1\xc6\x2e\xda\x24\x2e\x17\x8b\x43\x44\x88\xff\x02\x36\x55\xae\xc8\x29\x13\x00\x00")
 
 func webuiJsActionsJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -357,12 +357,12 @@ func webuiJsActionsJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/actions.js", size: 4537, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/actions.js", size: 4905, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webuiJsAppJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x5b\x5b\x6f\xdb\xb8\xf2\x7f\xef\xa7\xe0\x6a\x83\x95\x83\xfa\x92\x74\x17\x7f\xfc\x91\xda\x2e\xb2\x89\x77\xdb\xa2\x6d\x8a\x34\x7b\x5e\x8a\xc2\xa5\xc4\xb1\xc5\x44\x22\x75\x48\x2a\x89\x91\xf5\x77\x3f\x20\x29\xd9\xba\x50\x8e\x93\x38\xc9\x8b\x25\x5e\x66\x38\xc3\xe1\xcc\x6f\x86\xca\x39\xc0\xac\x4f\x20\xc8\xe6\x1d\x25\x32\xd8\x7f\xfb\xea\xd5\x35\x16\x08\xa7\x29\x1a\x21\x06\x37\x48\x0f\xe8\xf8\xbf\xe2\x34\xf5\xbb\xe8\xee\x15\x42\x08\x49\xc5\x05\x1c\xd9\x9f\xae\x69\x11\x3c\x53\x20\x8e\xf2\x5f\xdb\xa6\x20\x49\x63\xac\xe0\x08\xcd\x32\x16\x2a\xca\x59\x27\x15\x3c\x95\x5d\x3b\x6a\x3f\x27\xa6\xff\x0c\xc7\x50\xd1\x6b\xf8\x8b\x8b\x73\xdd\x2b\xd1\x68\x3d\xad\x3c\x74\x35\x5c\xcc\xf5\x98\x63\x21\xf0\xa2\x9f\x0a\xae\xb8\x5a\xa4\xd0\x97\x31\x0d\xa1\x1f\xe2\x38\xee\x60\x31\xcf\x12\x60\x4a\xee\xbf\xad\xcc\x16\xa0\x32\xc1\x0c\x81\xbe\xe4\x09\x74\x56\x7c\xcc\xba\x3e\x9c\xd6\xd9\x95\x26\xe5\x23\xd0\x68\x34\xb2\xcf\x7d\x4a\x2a\x63\x97\xfb\xe8\x1d\xf2\x7d\x74\x84\x7c\x2a\x7b\x11\x25\x04\x98\xbf\x1a\xb1\x7c\x55\xa3\x17\xa9\x24\xfe\x99\x37\x0e\x09\xbd\x46\x94\x8c\x3c\x86\xaf\xbd\xf1\x70\x40\xe8\xf5\xb8\xe8\x92\x60\x56\x88\xc2\x18\x4b\x39\xf2\xf2\x57\x6f\xbc\xa2\x67\x26\xe7\xbd\x21\x67\x0a\x53\x06\xa2\xd4\x8f\xd0\x30\x3a\x2c\x06\x28\xaa\x62\xa8\x74\x22\xb4\x77\x67\xe5\x31\x7d\xcb\xd5\x2b\x25\x46\x56\xff\x92\x07\xd2\xd7\xa2\xa1\x1e\xf2\xd1\x6b\x74\x16\x5c\x42\xa8\xfa\x57\xb0\x90\x76\x57\xfb\x7a\xc4\x7e\x3f\x06\x36\x57\x91\x16\xdf\x5f\x56\xe8\x0f\x65\x8a\x57\xeb\xa7\xb2\x97\x66\x71\x0c\xa4\x27\xe8\x3c\x52\x68\xef\xae\xb6\xfb\x1d\xcb\xb0\x8b\xfc\x04\x94\xa0\xa1\xf4\xf7\x97\xb5\x05\x23\x34\x0c\x32\xa5\xd6\x4a\xc9\xdf\xa8\xec\x09\x9e\x31\x02\x44\x3f\x52\x36\xe3\xfa\x97\x67\x2a\xa6\x0c\x88\x87\x38\x0b\x63\x1a\x5e\x8d\x3c\x6c\x74\x28\xfb\x02\x66\x02\x64\xd4\xf1\xd7\x32\x2f\xfd\x7d\x0f\xed\xdd\x59\xc1\x62\x8e\x09\x65\x73\xf4\xdb\x6f\xc8\x27\x54\xe2\x20\x06\xe2\x2f\xeb\x8b\xa9\x4b\x18\x56\xb6\xa7\x34\x8a\x16\x43\x66\x58\xa2\x19\xee\xc9\x05\x0b\xf5\x76\x53\x07\xc5\x81\x26\xd9\xc2\x69\x7c\x6e\xd7\xed\x1e\x34\x1c\x58\x75\x54\xdb\x9b\x63\x87\x83\xe8\xb0\xf2\x5e\xb2\xa3\x80\xdf\xd6\x44\x70\x5b\x19\x92\x6a\x11\xc3\xc8\x4b\x28\xeb\x45\xa0\x37\xf4\x08\xfd\x7e\x70\x90\xd6\x67\x97\x4c\x5c\xef\xee\x85\xd6\xa4\x57\x90\x6b\x33\x01\xbd\xef\x95\xb3\xd0\xa0\x95\x5b\xc8\x57\xcc\x20\xde\x44\xae\x6c\x49\x9b\x29\x86\x02\xb0\x82\xaf\x78\xbe\x71\x79\x76\xd4\x3d\xe4\xf2\xe9\xda\x86\x40\xf4\x6e\x04\x4e\x53\x10\x0d\xd3\x7a\x67\xbc\x85\xe5\xe0\xdb\xc3\xe3\x30\x9e\x26\x41\x6d\xd9\x39\x8d\xb6\x45\x34\x1b\x1b\x4d\xb5\x86\xaa\xe7\x19\xe4\xbe\x66\x5c\xf7\x52\x97\x3c\x38\x05\x85\x69\xfc\x99\x13\x1c\x57\xd8\x5b\x8f\xb6\xec\xbe\x5a\x16\xe1\x84\xe1\xeb\x00\x8b\x6a\x44\x61\xf8\xfa\x81\x11\x05\x2b\x85\xc3\xe8\x82\x1f\xe9\xf0\xb4\x83\x20\x53\x8e\x31\x2d\xbe\xbf\xec\xf7\x0b\x67\x58\x04\x81\xe6\xae\x6d\xe9\xe8\x19\x5e\xed\x63\xae\x18\x2a\x7b\x04\x8b\x2b\x0f\x09\xae\x4f\x12\xc3\xd7\x74\x8e\x8d\x8b\x47\x58\x50\xdc\x8b\x71\x00\xf1\xc8\x4b\x30\x65\xa8\xd4\xe9\xf6\xff\x96\x66\x2f\x10\x98\x91\x6a\x08\xc0\xb5\x21\x54\x41\xe2\xa1\x48\xc0\x6c\xe4\x0d\x6e\x20\xc8\xe8\xa0\x7e\xe2\x69\x32\x47\x52\x84\xda\xe2\xe6\xbc\x9f\xb2\xb9\x87\xec\x19\x1f\x79\x6f\xfe\xbf\x4a\x7d\x80\xc7\xaf\xaa\xdc\xac\x34\xd6\x13\x79\xf5\xe5\x65\x62\x0e\x02\xd9\x9f\x9a\x98\xc0\xb2\xbc\x05\x6e\x53\xac\x7d\xb9\x76\x97\xb1\x04\x0f\x11\xac\x70\x4f\x61\x31\x07\x55\x90\xfa\x13\x4b\x1a\x4e\x6e\x71\x92\x36\xa2\x9a\xf5\xc9\x86\x92\x8d\xc5\x23\x4f\xa3\x1c\x6d\xad\x4d\xaf\xf9\x5c\x83\x8d\x62\x6a\xa7\x6b\xd3\xc6\x19\xf1\xdb\x9c\x72\x3e\x46\x2a\x2c\x54\x5d\x58\xd7\xf6\x36\x62\x6b\xc5\x11\xe6\x7b\x9f\xb7\x34\x7c\xce\x67\xdb\x5e\x73\x1f\xf8\x71\x6c\x0b\x77\x9e\xf3\xd4\xaf\x0d\x86\x1f\x79\xb0\x23\x6e\x6b\xef\x9c\xf3\xb3\x0d\x0d\x8e\x27\xa6\x79\x13\xcf\xfa\x8e\x39\xf7\x03\x6a\x47\xcd\x39\xc8\x1c\xb7\x4d\x61\xc2\x9e\x94\xa6\x5a\xca\x62\xaf\x61\xce\x4d\x44\x15\x54\xc1\x8d\x15\x35\x52\x2a\x95\x47\x83\xc1\x9c\xaa\x28\x0b\xfa\x21\x4f\x06\xf8\xf2\x3a\x18\x5c\xe1\x18\xbb\x41\xc9\x56\xd0\xa5\x0a\x5e\x02\x0d\x5e\x2c\x87\x16\xf8\xd2\x0e\x60\x0a\x08\xf3\x37\x55\xef\xb3\xa0\x15\xe6\xd4\xf7\x7e\x17\xe1\x8c\xe1\xd6\x20\xb5\x02\x25\xd5\x38\xb5\x6a\xde\x10\xad\x9e\x1e\x99\x04\xbf\xd1\xf9\x4c\x8e\xab\x81\x29\x41\xa1\x0c\xad\xd1\xbf\xff\xa2\xbb\xe5\x7e\x5f\x00\xc9\xc2\x52\xce\x82\xc3\xb0\x8b\xf4\xf0\x45\x17\x51\x72\xeb\x4a\x95\x74\xd8\xb2\x43\xbe\x1f\xfc\x78\xdb\xe8\xbe\xe4\xc1\xaa\xff\xf0\x87\x3b\x57\x0a\x43\xf4\xba\x12\xc3\x10\x1a\x2a\x51\xd1\xba\x22\xe3\xbd\x3b\x4a\x6e\xd1\x6b\x74\xb8\x1c\x0e\x14\xa9\xf7\x0e\xf1\x1a\x7b\xe7\x74\x8d\x06\xfb\x84\x77\x7c\x19\xf1\x9b\x8f\x05\xa2\xd0\xb8\x5f\xd3\xd2\x30\xdc\x10\x5d\x6a\x53\x70\xd1\xdc\xbb\xbb\xe4\x41\x9f\xe1\x04\x9c\x2c\x6d\x37\xbf\x61\x20\xda\xfb\x2f\x16\x29\x74\xf4\x38\x9d\x40\xee\x6f\xa0\x53\x80\x7f\x1d\xf8\x4f\x8b\x44\x40\xc7\xfd\x09\xcb\x93\x82\xf6\xb9\x09\x28\xac\x43\x17\x7a\x87\xca\xaf\x7d\x99\x85\x21\x48\x39\x0d\x79\xc6\x14\x7a\x8d\xfc\x81\xce\xae\x2a\x43\x58\x96\x04\x20\xa6\x7c\x36\x9d\x51\x46\x65\x04\x64\x2a\x32\x26\xd1\x11\x3a\xd8\xc0\x90\xca\x29\xe1\xac\xae\x96\xe1\x60\xbd\x6b\xeb\xad\x5c\x76\x91\xef\x97\xb2\x64\x17\x66\x51\xe6\x60\x14\x09\xa4\x79\xa1\xb2\x37\xcb\xe2\xf8\x86\x12\x15\xe9\x17\xa9\x04\x4d\xa1\xec\x07\x87\x2a\x02\x5c\x5b\xa0\xa8\x1d\x5f\x15\xe9\x9d\x8d\x9a\xad\x1f\x4e\xdd\xed\x5f\x70\x02\xee\x9e\x33\xbd\xd1\xee\x2e\xbd\xc9\xee\x9e\x6f\x0a\xab\x4c\xb6\xf4\xd9\xdd\x71\x77\x9e\x72\xd6\x20\x59\xd6\xaf\x79\xab\xca\x3f\x54\x01\x27\x8b\xf2\x04\x9d\x73\xde\xc8\x65\x79\x4a\x79\xc8\x70\x60\x54\xbd\xc1\x63\xd9\x13\xd3\xf0\x58\x25\x68\xfe\x6c\x6e\x8b\xce\xd0\xda\x41\x59\x86\xad\xee\xa7\x36\x4e\x27\xd3\xb5\xa6\x3e\x25\x4e\xdf\x53\xf3\x3a\xa5\x68\x99\x68\xe9\x56\xb9\xd4\x9a\xf6\x7d\xd9\x54\x83\x46\x2f\xc0\xe1\xd5\xdc\x54\x0d\x4a\xf5\x81\xb5\x73\x0a\x63\xc0\x62\xed\x9d\xf6\x5d\xb9\x56\x93\x68\x88\x45\x03\x13\x68\x73\x00\xd1\x1c\xd7\xd3\x1d\x4d\x68\x90\x3a\x46\xba\x8a\x37\xd6\x92\xea\x0a\x35\x6e\x71\x73\x99\x42\xe1\xb9\x63\x62\xd9\xd1\x69\x9c\x81\x05\xa3\x6c\x5e\x14\xb5\x72\x97\xe5\x4c\x51\x5d\xcb\xb8\xdf\x6d\x6e\x59\xf8\x18\x0e\xd2\x7b\x4a\x40\x04\x62\x50\x50\x4d\x26\xc2\x98\xeb\xbc\x61\xcb\x6d\x75\x57\x4d\xec\xb6\xd5\x13\x80\x6a\x4d\xae\xb4\x45\xfa\x04\x3b\x36\x53\xc0\x78\xef\xee\xe3\xb7\xb3\x2f\x7d\xed\x29\xd9\x9c\xce\x16\xf5\xf3\xd3\x45\x19\x23\x30\xd3\x60\xae\x8b\xfe\xd0\xc1\x48\xcf\xaa\xd7\x70\xaa\x19\x79\xde\x3c\xe3\x5c\x39\x4d\x4b\x77\x34\x57\xf3\x4b\xaf\xd7\x5e\x40\x4b\x05\x4d\xb0\x58\x78\xc6\x39\xca\x95\x56\x50\xaf\xb7\x75\x0d\xae\x20\xd1\xac\xb7\x29\x3e\x9f\xc7\xa0\xf5\x9e\x1b\x43\x67\x1d\xec\xb7\xb0\xea\xb2\x39\x59\x0b\x32\xc6\x94\x13\x6b\x18\x93\x7b\x4b\x1f\xb5\x70\x91\xb1\x8f\x3c\x58\xaf\x76\xd3\xe2\x6a\xc5\xc2\xf3\x8c\xa1\xcf\x98\x65\x38\x8e\x17\x0f\x5e\x11\xc1\xcc\xa4\xc8\x8d\x05\x59\x73\xaf\xac\x69\x7c\x6a\xda\xda\x0c\xd9\x1a\x49\x7b\xa2\xb2\x83\x3a\xd5\xc3\xab\x54\xf7\x21\xf8\x86\x6a\x72\xe3\xd6\x07\xdb\xd0\xc6\x62\xbe\xd3\x43\x5f\xe1\x5f\x82\x49\x08\x62\x09\xee\x02\x91\xef\xbf\xad\x15\x7f\xca\x71\xba\x5c\xa2\xac\x86\xea\x72\xcf\xb3\x05\xea\xed\xf1\x5c\xc0\x05\x01\xf1\x18\x0c\x67\x4b\x05\x6e\xa4\xf4\x1f\x1c\x67\x4f\x87\x4a\x0e\xb6\x64\x7c\x6c\x6c\xd1\xd4\x0d\xea\x58\xb8\x40\xc3\xd6\x84\x73\x45\xf7\xad\xf1\x4e\x8b\x94\xca\x3f\x70\xa0\xf6\x41\x3d\xb5\x71\x30\x2e\x9c\xd7\x03\x58\x17\xfe\xe0\xe9\xcc\xb7\xe7\xf9\x64\x56\x13\x21\xb8\x40\x27\x3a\x3d\xd9\x8e\x23\xe8\x09\x79\x3e\xf3\x14\xc6\x39\xf6\x7e\x08\xeb\x6a\x32\xf5\x14\xe6\x5f\xe0\x56\x21\xed\xb4\x8f\xb7\x64\xcd\xe0\x56\xe9\xb4\x6c\x8a\xd5\xe3\x38\x7e\xc2\x52\xa1\x63\xa5\x4f\xb5\x02\xf2\x20\xde\x31\x96\x6a\x8a\x8b\xa9\x7a\x15\x8f\x5b\x42\x5e\xed\x43\x7f\x03\x03\x81\xf5\x2a\xb6\x5d\x81\xad\xad\x91\x7b\xd8\x3e\x2c\xb7\x29\x2e\x61\x1a\x2e\x73\x7d\x3b\xf3\xac\x05\x19\x62\x02\x84\xe1\xff\x17\x17\x3a\x7f\x2c\x5f\x17\xd8\x45\xe8\x56\x57\xca\x03\x42\x94\x07\xcf\x28\xc4\x44\xa7\xad\x5d\xc4\x33\x95\x66\xca\x75\xc3\xbc\x9e\x67\x75\x6b\x39\x4c\x84\xf8\xbe\x9a\xfe\xa3\x31\x29\xf7\xec\x7a\xde\x3b\xd4\xb1\xc4\xb5\xe1\x83\x10\xfb\xd5\x1b\x09\x54\xb9\x95\x40\x79\xfa\xb6\x16\xc3\x5e\xf3\xc6\x3c\xc4\xb1\xbf\xe1\x02\xbc\x9a\x8d\x55\xa3\xbd\x59\x67\x3d\xe3\x31\x01\x79\x85\x07\xf4\x8b\x37\x3e\xe1\x49\x82\x19\x19\x0e\xcc\x7b\x3b\x1a\x09\x39\x53\x82\xc7\x4d\x88\x41\x99\x96\xb3\xa8\x57\x9a\x97\xc2\x24\xd7\x6a\xf3\x43\xcb\xc6\xff\x61\xd0\xd8\x0a\x48\xf9\x4b\x0f\xa9\x45\x0a\x23\x4f\xc1\xad\xf2\x50\x1a\xe3\x10\x22\x1e\x13\x10\x23\x2f\xc0\x32\x42\xbd\x10\xf9\x04\x2b\xf0\x3d\xa4\x73\x28\xbd\x10\x43\xa9\x2e\x9b\x03\xca\xac\x32\xb6\x08\xe2\xb4\x04\xde\xc6\x1b\xd7\xa7\x5d\x95\xf6\x54\xe9\x06\x14\xf4\xb3\xba\x95\x16\x8b\xb8\xf6\x50\x40\xc2\x15\x38\x37\x31\xc7\x22\x11\x27\x12\x8d\xd0\x77\xef\xef\xc9\x85\xd7\x45\xde\xd7\xb3\x6f\xe6\xf7\xfd\xe4\xf8\xd4\xbc\xff\x63\x5e\x4f\x27\x9f\x26\x17\x13\xfd\x74\x72\xf6\xe5\xcb\xe4\xc4\x34\x9e\x7d\xbd\xf8\x70\xf6\xe5\x9b\x19\x76\x7c\x71\xf2\x5e\x3f\x5c\x9c\x1f\x9f\x4c\xbc\x1f\xee\xda\xa4\xe5\xe7\x2c\x4e\xd6\x6c\xab\x54\x65\x1c\xf2\xd4\xe4\x56\xd7\x1a\x39\x8c\xbc\xbd\x3b\x4b\x65\xa9\x31\x37\x25\xb7\x46\xd0\x03\x0b\xb2\x61\x86\xb3\x58\x7d\x83\x18\x42\x65\xb0\xf6\x6a\xf0\x70\x60\xa9\x8c\x7f\x36\xb8\xda\x8a\xd7\xd6\x46\x6e\x2c\x5b\x82\x6a\x37\x55\x97\xed\xb7\x58\xff\x3f\xe7\x9f\x9c\x96\xbf\x9d\xed\x3b\xad\xbf\x30\xe8\x4c\xc4\x85\xc9\xea\xc7\xc7\x54\xcf\x1f\x2d\xd6\x67\xa3\xf4\xa7\x49\x56\x1a\x25\xcd\x86\xb6\xdc\x56\x98\xbe\x5c\x50\xbb\xd9\x2d\x17\x16\x85\x2d\xc8\x66\x8d\xc1\x66\xd2\x9a\x90\xeb\xe6\xe1\x91\x57\xe9\x8f\xd6\xde\x7b\x53\x61\x90\x4f\x53\x9f\xf6\x67\x58\x00\x36\x97\x09\x23\xef\xf7\xd5\x95\xeb\xaa\xc3\xe1\x85\x6c\x6d\x43\xde\xe7\x25\xad\xb2\xf3\xc1\x35\xa7\x79\xf7\xdb\x7f\x33\xae\xde\x5e\xc1\xc2\x3e\x1c\xa1\xef\xf6\xe1\x1a\xc7\x87\xf6\xa9\x8b\x56\x2d\x6f\xec\xd3\x0f\xf3\xdd\x44\xb1\xb2\x2d\x94\xfd\x40\xef\xba\x96\xcb\xe9\x5d\x77\xb9\x79\x7f\x72\xb2\xd8\xe9\xce\xfd\x5f\x63\xe7\x5c\x9b\x61\xcb\x4c\x0f\xd2\xe1\xee\x64\xbe\xa0\x09\xf0\x4c\x3d\x97\x27\xb3\x97\x1e\x35\x3b\x93\x10\x72\x46\x64\x21\xbf\xb2\x4b\x78\x59\x37\x37\xb9\x4d\x4d\xa0\x41\xe7\x20\x53\xce\x24\xa0\x13\x4e\xe0\x89\x07\xb7\x5d\x0f\x0e\x88\xf2\xe6\xe0\xa0\xfb\xe6\xe0\xb0\xfb\xe6\xe0\x8f\x42\x13\x90\x2f\x6a\x2a\xf2\x45\x4d\x43\xbd\xa8\x47\x69\x66\x38\x68\x46\x3b\x27\xf6\xd8\x8c\x11\xb5\xe4\xe3\x7f\x98\xcc\xd2\x94\x0b\xad\x2f\x2d\xcf\x2f\x96\xdb\xcf\x16\x54\xba\xac\x00\xf0\x30\x82\xf0\x0a\x88\x03\x7c\x37\xf0\x73\xce\xb8\xec\x03\x56\x78\x48\xa1\x77\xc8\xcf\x69\x8d\xbc\xfc\xc1\x7b\xd0\xf7\x3a\x33\x2e\x92\xd2\x87\x61\x7f\x71\x91\x78\x88\x33\x99\x05\x09\x55\xab\xcb\xcc\xa2\x2a\x67\x9b\xed\x77\x05\xa6\x36\xf7\xeb\x7a\x5a\xa5\xc6\x59\x35\x8e\x38\x4b\x6a\x37\xff\xcd\x7e\x73\xd9\xcf\xa0\xa7\x22\xda\xbc\x5b\x78\x9c\x3d\x7f\xe4\x01\xb2\x37\x6a\xbb\x35\x60\x57\xa8\xd1\xc6\xba\x5d\x9c\xb9\xe4\x81\x7e\xd8\xc6\x80\x1f\x18\x16\xf2\x35\xec\x34\x26\x6c\xa7\xa3\x8a\xf6\x05\x26\x94\xb7\x7c\x20\x6a\xd4\x67\x75\x62\x87\x15\xfe\x6e\x91\x82\x57\x40\xe2\x03\x53\xdd\x8c\xb4\xa0\xe5\xf2\xa6\x84\xdc\xf2\xb4\xfd\x7f\xb2\xd9\x9c\x06\xcd\xd5\xc3\xd4\x29\x12\x3d\xc7\x67\xac\x08\x99\x69\x0e\x4c\xe4\x34\x90\x9d\x0b\x76\xb8\x85\x60\xe7\x79\x8a\xe3\x92\xac\x48\x7f\x9c\xa2\xd9\x89\xdb\xca\xf6\xac\x81\xc4\x5c\x56\xfb\x12\x4d\x12\x4c\xe3\xe7\x0d\x1f\x56\xcb\xe6\x33\x88\x97\x0d\x96\xdf\xc2\x08\x48\x16\xef\xdc\xb9\xb8\xc4\x93\x39\xaf\x17\x86\x03\xa9\xa4\x31\x67\x2f\x21\x20\x58\x56\x2f\x2b\xdf\x39\x98\x0f\x91\x9e\x1b\xe6\x59\x09\x85\x65\xf6\x7c\x12\x3e\xc2\x51\x1b\xf7\xd2\xfc\x12\xbe\x22\x99\x95\x65\x35\x72\x25\x8d\xcc\x12\x98\x62\x35\x35\xc5\xe1\xc2\x3e\xc9\x54\xe3\x56\x27\xb9\x73\x33\x03\x1d\x2b\x64\xea\xcf\xc5\xe9\x21\x48\x83\xed\x17\x77\x5a\x2f\xaa\x2c\xc2\xcf\x41\x82\xf2\x50\x5e\xcf\x39\xb1\x5e\xbd\x45\x4b\xa0\x90\x46\x53\x3b\x56\x49\xdb\xfd\x6b\x7e\xad\x50\xd8\xac\xc5\x77\xde\xd8\x86\x23\xd7\x4d\x6b\x13\x53\x37\x90\x5c\xe3\xbf\x6f\x9a\xe5\xe6\x4e\x1d\xce\xee\x2f\xdb\x79\xd4\xbe\x72\xd4\x88\xd5\x55\x58\x1f\x0c\xd0\x07\x46\x15\xc5\xb1\xf9\x90\x1a\xc5\x1c\x93\x57\x05\x70\x9d\x83\xfa\xc8\x03\xd9\xb1\x75\xb1\xbe\x8a\x80\x75\x9c\xff\x83\x55\x9a\x90\xdf\x19\xe4\x73\x34\x8f\xff\x05\x00\x00\xff\xff\x21\x5e\x09\xa8\x51\x36\x00\x00")
+var _webuiJsAppJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x5b\xeb\x6f\xdb\x38\x12\xff\x9e\xbf\x82\xd5\x16\x2b\x07\xe7\x47\xd2\x5d\x1c\x0e\xa9\xe3\x22\x9b\x64\xfb\x40\x9b\x14\x49\x7a\x5f\x8a\x22\xa5\x25\xda\x52\x23\x8b\x3a\x92\xca\x03\x59\xff\xef\x37\x43\x4a\xb2\x1e\x94\x63\x27\x4e\x0a\x14\x96\xf8\x98\xe1\x0c\x87\x33\xbf\x19\x2a\x67\x8c\x4d\xfa\x3e\x1b\xa7\xd3\x8e\x12\x29\xdb\x7e\xbb\xb5\x75\x4d\x05\xa1\x49\x42\xf6\x49\xcc\x6e\xc8\x19\x0c\xe8\xb8\xbf\x41\x83\xdb\x25\xf7\x5b\x04\xfe\x49\xc5\x05\xdb\x33\x3f\x5d\xdd\x22\x78\xaa\x98\xd8\xcb\x7e\x4d\x9b\x62\xb3\x24\xa2\x0a\x06\x4e\xd2\xd8\x53\x21\x8f\x3b\x89\xe0\x89\xec\x9a\x51\xdb\x19\x31\xfc\xa7\x39\xc2\x90\x6b\xf6\x37\x17\x67\xd8\x2b\x81\x7b\x31\xad\x3c\xb4\x18\x2e\xa6\x38\xe6\x40\x08\x7a\xd7\x07\xba\x8a\xab\xbb\x84\xf5\x65\x14\x7a\xac\xef\xd1\x28\xea\xc0\x88\x74\xc6\x62\x25\x41\xa6\xf2\x6c\xc1\x54\x2a\x62\x4d\xa0\x2f\xf9\x8c\x75\x0a\x3e\x7a\x5d\x1f\x8f\xea\xec\x4a\x93\xb2\x11\x64\x7f\x7f\xdf\x3c\xf7\x43\xbf\x32\x76\xbe\x4d\xde\x11\xd7\x25\x7b\xc4\x0d\x65\x2f\x08\x7d\x9f\xc5\x6e\x31\x62\xbe\x55\xa3\x17\xa8\x59\xf4\x33\x6b\x1c\xfa\xe1\x35\x09\xfd\x7d\x27\xa6\xd7\xce\x68\x38\x80\xd7\x51\xde\x25\x99\x5e\x21\xf1\x22\x2a\xe5\xbe\x93\xbd\x3a\xa3\x82\x9e\x9e\x9c\xf5\x7a\x3c\x56\x34\x8c\x99\x28\xf5\xc3\x88\x60\x37\x1f\xa0\x42\x15\xb1\x4a\x27\x21\xaf\xef\x8d\x3c\xba\x6f\x5e\xbc\x86\xbe\x96\xd5\xfd\xc5\xc7\xd2\x45\xd1\x48\x0f\xfe\xff\x8b\x9c\x8e\x7f\xc1\x1a\xfa\x57\xec\x4e\x9a\x5d\xed\xe3\x88\xed\x7e\xc4\xe2\xa9\x0a\x50\x7c\x77\x5e\xa1\x3f\x94\x09\x2d\xd6\x0f\xaa\x49\xd2\x28\x62\x7e\x4f\x84\xd3\x40\x01\xb7\xda\xee\x77\x0c\xc3\x2e\x71\x67\x4c\x89\xd0\xd3\x8f\x53\x41\x93\xc0\xdd\x9e\xd7\x56\x0e\xb4\xc7\xa9\x52\x0b\xed\x64\x6f\xc0\x04\x64\x88\x7d\xe6\xe3\x63\x18\x4f\x38\xfe\x02\xf9\x08\x74\xe3\x3b\x84\xc7\x1e\x18\xcb\xd5\xbe\x43\xb5\x32\x65\x5f\xb0\x89\x60\x32\xe8\xb8\x0b\xe1\xe7\xee\xb6\x03\xab\x33\x12\x46\x9c\xfa\x61\x3c\x25\xbf\xff\x4e\x5c\x3f\x94\x74\x0c\x02\xb8\xf3\x51\xc3\x56\xaa\xa2\x7a\x95\x7d\x2a\x8d\x0a\xf3\x21\x13\x2a\xc9\x84\xf6\xe4\x5d\xec\xe1\xbe\x87\x16\x8a\x03\x24\xd9\xc2\x69\x74\x66\xd6\x6d\x1f\x34\x1c\x18\x75\x54\xdb\x9b\x63\x87\x83\x60\xb7\xf2\x5e\x32\xa8\x31\xbf\xad\x89\x60\x37\x37\xf0\x08\x77\x11\xdb\x77\x66\x61\xdc\x0b\x18\xee\xec\x1e\xf9\x63\x67\x27\xb9\x6d\xee\x58\x6e\xeb\xb8\xcd\x17\xa8\x49\x27\x27\xd7\x66\x0b\xb8\xef\x95\x43\xd1\xa0\x95\x99\xca\x57\x1a\xb3\x68\x19\xb9\xdc\xa4\x1e\xa6\xa8\x2d\xee\x41\x7a\x0b\xbb\x5c\x4e\xcd\x13\x0c\xdc\xe1\x57\x3a\x5d\x2a\xac\x19\xf5\x00\xb9\x6c\x3a\x5a\x24\x13\xbd\x1b\x58\x40\xc2\x44\xc3\x50\xdf\x69\x27\x64\x38\xb8\xe6\x4c\x5a\x4c\xb1\x49\x10\xcf\x49\x46\xa3\x6d\x11\xcd\xc6\x46\x53\xad\xa1\xea\xd0\x06\x99\x0b\x1b\x6d\x35\x0d\xe2\x88\x81\x3d\x45\x5f\xb8\x4f\xa3\x0a\x7b\xe3\x28\xe7\xdd\xad\x79\x1e\xa5\xc0\x53\x8e\xe1\xa7\x12\xa8\xa0\x6d\xcd\x40\x45\x95\xa2\x5e\x70\xc1\xf7\x30\xea\x6d\x20\x76\x95\x43\x57\x4b\x48\x29\x87\x93\xdc\xc7\xe6\xb1\xa5\xb9\x6b\x2b\xc6\x0f\x90\x3c\xdf\xc7\x4c\x31\x40\xc7\xa7\xe2\xca\x01\xda\x78\x2e\xa1\x35\x9c\x52\x1d\x39\x20\xfc\x85\xb4\x17\xd1\x31\x8b\xe0\xe0\xc0\xf1\x25\xa5\x4e\x7b\x58\x31\x34\x7b\x63\x41\x63\xbf\x1a\x59\x68\x6d\x48\x08\xea\x73\x48\x00\xfe\x74\xdf\x19\xdc\x00\xb6\x08\x07\x75\xff\x11\xce\xa6\x44\x0a\x0f\x2d\x6e\xca\xfb\x09\x18\x1a\x31\x1e\x63\xdf\x79\xf3\x9f\x2a\xf5\x01\x1d\x6d\x55\xb9\x19\x69\x8c\x5f\x73\xea\xcb\x4b\xc5\x14\x2c\xd8\xfc\xd4\xc4\x64\x71\x9a\xb5\xb0\x5b\x70\x7f\x10\x19\xd0\xf9\x46\x12\xce\xa3\x4f\x15\xed\x29\xc0\x04\x4c\xe5\xa4\xfe\xa2\x32\xf4\x8e\x6f\x29\x58\x42\x3d\x58\x1a\x0f\xaf\x29\x99\x10\x0f\x31\x15\xc0\x13\x5a\x6b\xd3\x07\x3f\xd7\x60\xad\x98\xda\xe9\x5a\xb6\x71\x5a\xfc\x36\x17\x9f\x8d\x91\xa0\x02\x55\x17\xd6\xb6\xbd\x8d\x90\x5d\x71\xab\xd9\xde\x67\x2d\x0d\x9f\xf3\xc5\xb4\xd7\xdc\x07\x7d\x1c\xdb\x3c\x38\x64\x3c\xf1\xb5\xc1\xf0\x13\x34\x6e\x86\x5b\xe1\xeb\x33\x76\xfa\xbd\xc1\xef\x3d\xb6\x6e\x86\xe1\x22\x1c\x64\x1c\x4d\x43\x83\xe5\xa1\x6e\x5e\xc6\xb3\x6e\x22\x56\x03\x60\xb5\xb3\x6d\x1d\xa4\xcf\xf7\xb2\xb8\x64\x8e\xa6\xb4\x05\x1b\xda\x44\x69\x37\x01\x10\xac\x62\x33\x23\x6a\xa0\x54\x22\xf7\x06\x83\x69\xa8\x82\x74\xdc\xf7\xf8\x6c\x40\x7f\x5d\x8f\x07\x57\x34\xa2\x76\x4c\xb5\x12\xf2\xaa\x62\xaf\x31\x62\x2f\xc3\xa1\x05\x7d\xb5\xe3\xaf\x1c\x81\xbd\x0f\xd5\x87\x74\xdc\x8a\xd2\xea\x7b\xbf\x89\xf8\x09\x5b\xd1\x16\x15\x0b\x4c\x55\x0d\x8c\x45\xf3\x92\xf0\xf8\xf4\x50\x28\xf8\x0d\xe6\x65\x59\x7e\x00\xe9\x97\x08\x59\x39\x45\x20\xff\xfc\x43\xee\xe7\xdb\x00\xb6\xfd\xd4\x2b\xe5\x5e\xd4\xf3\xba\x04\x87\xdf\x75\x01\x07\xdc\xda\x52\x3e\x8c\x93\x66\xc8\xf7\x9d\x1f\x6f\x1b\xdd\x40\xbd\xe8\xdf\xfd\x61\xcf\xf9\x3c\x0f\x72\x97\x72\xd0\x04\x55\x2a\x51\xd1\xba\xf2\x47\xaf\xef\x61\x05\x30\x70\x77\x3e\x1c\xc0\x6b\xad\x17\x4c\xb8\x48\x1d\x32\xba\x5a\x83\x7d\x9f\x77\x5c\x19\xf0\x9b\x4f\x39\x84\xc1\xa4\x05\x69\x61\x16\xa1\x89\xce\xd1\x14\x6c\x34\x5f\xdf\xc3\xea\xfb\x31\x9d\xb1\x79\x7b\x37\xbf\x01\x90\xdd\xde\x7f\x01\xf9\x6f\x07\xc7\x61\x22\xbc\xbd\x84\x4e\x9e\xbb\x20\xd2\x38\xca\xf3\x18\x04\x1a\xc7\x71\x96\xd3\xb4\xcf\x05\x97\x4e\x31\x56\xc2\xdc\xf2\x6b\x5f\xa6\x9e\xc7\xa4\xbc\xf4\x20\xe3\x52\xa0\x3a\x77\x80\x59\x62\x65\x48\x9c\xce\xc6\x4c\x5c\xf2\xc9\xe5\x24\x8c\x43\x19\x30\xff\x52\xa4\xb1\x04\xc6\x3b\x4b\x18\x86\xf2\xd2\xe7\x71\x5d\x2d\xf0\x56\xec\xda\x62\x2b\xe7\xa0\x6f\xb7\x94\xed\xdb\x40\x92\xd2\x07\x23\x4f\x84\xf5\x0b\x78\x9e\x09\x64\xa3\x37\xa1\x0f\x99\x2b\xbc\x48\xb0\xd9\x84\x95\xfd\xe0\x50\x05\x8c\xd6\x16\x28\x6a\xc7\x57\x05\xb8\xb3\x41\xb3\xf5\xe3\x91\xbd\xfd\x04\x76\xdb\xde\x73\x8a\x1b\x6d\xef\xc2\x4d\xb6\xf7\x9c\x2b\xaa\x52\xd9\xd2\x67\x76\xc7\xde\x79\x04\xea\xad\xf7\x94\xf5\xab\xdf\xaa\xf2\x0f\xd5\x98\xfb\x77\xe5\x09\x98\x32\xdf\xc8\x79\x79\x4a\x79\x08\xbc\xa2\xaa\x97\x78\x2c\x73\x62\x1a\x1e\xab\x94\x0b\x3c\x9b\xdb\x0a\x27\x64\xe1\xa0\x0c\xc3\x56\xf7\x53\x1b\x87\xb5\x80\x5a\x13\xc0\x79\xab\xef\xa9\x79\x9d\x52\xb4\x9c\xa1\x74\x45\xf2\xb6\xa0\xfd\x50\xfa\xd6\xa0\xd1\x1b\x53\xef\x6a\xaa\x8b\x1e\xa5\xf2\xc6\xc2\x39\x79\x11\xa3\x62\xe1\x9d\xb6\x6d\xc9\x5d\x93\xa8\x47\x45\x03\x13\xa0\x39\x00\xca\x6e\x8c\xeb\x61\x47\x13\x1a\x24\x96\x91\xb6\x22\x94\xb1\xa4\xba\x42\xb5\x5b\x5c\x5e\x65\x51\x74\x6a\x99\x58\x76\x74\x88\x33\xa8\x88\x21\xa5\xcd\x8b\x73\x99\xcb\xb2\xe6\xc4\xe4\x01\x6a\x76\xb7\xb9\x62\xdd\x66\x38\x48\x1e\xa8\x60\xf9\x2c\x62\x80\xf1\x2a\xd9\x8b\x17\x71\x4c\x54\x56\xdc\x56\x7b\xd1\xc7\x6c\x5b\x3d\xe3\xa8\xd6\x16\x4b\x5b\x84\x27\xb8\xa1\x9c\xd7\xf7\xf2\x2a\x4c\xc0\x3f\x9e\x81\xf3\xd6\x78\xa2\x7e\x78\xfa\xd9\x00\xed\xde\xb7\xe7\x0d\x63\x10\x0c\x7c\xfb\xa7\xf3\xd3\x93\x3e\x7a\xda\x78\x1a\x4e\xee\xea\x24\xba\x04\x0b\x77\x13\x04\x83\x5d\xf2\x27\x06\x33\x9c\x55\x2f\x61\x55\x4b\x08\x59\xf3\x84\x73\x65\x35\x4d\xec\x68\x9a\xe6\xab\x5e\xaf\xbd\x7e\x98\x88\x70\x46\x05\xe8\x00\x9d\xab\x2c\xb4\x4a\x7a\xbd\x95\x4b\x90\x39\x89\x66\xb9\x51\xf1\xe9\x34\x62\xb8\x6f\x99\x31\x75\x16\x60\x61\x6b\x3d\x73\x34\x16\xa8\x8d\x31\x23\xd6\x30\x46\xbb\x49\x3c\x6a\xe1\xb0\xad\xb0\xea\xc5\x6a\x97\x2d\xae\x56\x2b\x05\x9b\x21\x5f\x68\x9c\xd2\x28\xba\x5b\x7b\x45\x3e\x8d\x75\x4e\xdf\x58\x90\x39\x2e\x95\x35\x8d\x8e\x74\x5b\xdb\x41\x30\x46\xd2\x9e\xe8\x6c\xa0\xb0\xb6\x7e\x59\xed\xa1\x0c\xa0\xa1\x9a\xcc\xb8\xd1\x31\x68\xda\x58\xbe\xd8\xa4\xd3\xa8\xf0\x2f\xc1\x2c\xc2\x22\xe0\x68\xad\x68\xb9\xee\xdb\x5a\xb5\xaa\x1c\xe7\xcb\x15\xda\x6a\xa8\x2f\xf7\x3c\x5b\xa0\x5f\x1d\x0f\x8e\xb9\x80\x0d\x7b\x0c\x06\x34\xb5\x0d\x3b\xd2\xfa\x2f\x8d\xd2\xa7\x43\x2d\x0b\x5b\x7f\x74\xa0\x6d\x51\x17\x3a\xea\x58\x3a\x47\xd3\xc6\x84\x33\x45\xf7\x8d\xf1\x5e\xe6\x29\x99\xbb\x63\x41\xfd\x83\x7a\x6a\x64\x61\x9c\x3b\xaf\x35\x58\xe7\xfe\xe0\xe9\xcc\x57\xe7\xf9\x64\x56\xc7\x42\x70\x41\x0e\x31\xbd\x59\x8d\x23\xc3\x09\x59\x3e\xf4\x14\xc6\x19\x76\x5f\x87\x75\x35\x19\x7b\x0a\xf3\x13\x76\xab\x08\x3a\xed\x83\x15\x59\xc7\x30\x01\xe3\xfe\x25\x55\x8f\xe3\xf8\x99\x4a\x05\xdc\xf0\x54\x2b\xb0\xaa\x75\x78\xc3\x19\x56\xc0\x37\x9b\x8a\xab\x78\xdc\x12\xb2\xf2\x24\x79\xcf\x20\x0d\xa3\xb8\x8a\x55\x57\x60\x6a\x73\xfe\x03\x6c\xd7\xcb\x8d\x16\x77\x50\x55\x8f\xb9\x68\x7f\xd6\xc4\xe8\x95\x91\x50\x73\xeb\xc7\xdc\x67\x32\xbb\xe3\x6d\xb9\xd2\xd0\xce\x75\x98\x8c\x4e\xb8\xae\x43\x11\x9f\x25\x2c\xf6\x21\x02\x11\x0e\x9e\xcd\x14\xa7\xc8\x1d\x53\x7d\x44\xc1\x3f\xd7\xb8\x19\xcf\xee\x16\xf9\x35\x13\x93\x88\xdf\xf4\x6e\x41\xa6\x54\xf1\x92\x87\x7e\x7d\x6f\x98\xb1\xd8\xbb\xd3\x15\xd8\xf3\xeb\x69\xa7\xb4\xfc\x02\x8a\x2e\xbd\x53\xca\x6f\xe9\x1a\x0a\x5f\x5c\xdf\x3d\x6b\x01\xcd\xd7\x01\x59\xf3\xff\x9b\x0b\xcc\xf7\xcb\xf7\x49\x66\x11\xd8\x6a\x4b\x51\xc1\xe7\x94\x07\x4f\x42\x16\xf9\x58\x66\xe8\x12\xe0\x94\xa4\xca\xf6\x65\xc3\x62\x9e\x51\x95\xe1\x00\xee\xee\x7b\x31\xfd\x47\xdb\xe7\x10\x38\xef\x1d\xe9\x18\xe2\xe8\x68\xa0\x61\xbb\x7a\x65\x55\xdd\xdc\xdc\xaa\x16\x62\x98\xcf\x0b\x22\xee\x41\x82\xbf\xe4\xc3\x8b\x6a\xf6\x5c\x45\x57\x7a\x9d\xf5\x0c\x55\x03\xa0\x02\x7f\xe1\x8b\x33\x3a\xe4\xb3\x19\x8d\xfd\xe1\x40\xbf\x2f\xbf\xd1\x16\x3c\x6a\x42\xba\x30\x46\x39\xf3\xfa\xb2\x7e\xc9\x5d\xc0\x42\x6d\xae\x67\xd8\xb8\x3f\x34\xfa\x2d\x80\x2b\x60\x44\x82\xa5\x39\x80\x19\xe0\x24\x1d\x02\x46\xe1\xb1\x80\x47\x00\x34\x00\xe5\x52\x19\x90\x9e\x07\x60\x19\xab\xfd\x0e\xc1\x9c\x17\x17\xa2\x29\x39\x0f\xe0\xc4\x72\x86\x1d\xb0\x28\x29\x81\xe5\xd1\xd2\xf5\x61\x68\xc0\xc8\x90\x2c\x41\x9d\x3f\xab\x5b\x69\xb0\x9f\x6d\x0f\x05\x9b\x71\xbc\xa9\x68\xb1\x31\xf0\x91\x01\xf7\xb1\x36\xfc\xdd\x79\x7f\x7c\xe1\x74\x89\xf3\xf5\xf4\x5c\xff\x7e\x38\x3e\x38\xd2\xef\xdf\xf4\xeb\xd1\xf1\xe7\xe3\x8b\x63\x7c\x3a\x3c\x3d\x39\x39\x3e\xd4\x8d\xa7\x5f\x2f\x3e\x9e\x9e\x9c\xeb\x61\x07\x17\x87\x1f\xf0\xe1\xe2\xec\xe0\xf0\xd8\xf9\x61\xaf\x25\x1b\x7e\xd6\x62\x72\x7b\x55\x78\xc8\x13\x9d\x0b\x5f\x23\x52\xc3\xeb\x79\x43\x65\x8e\x39\x0e\xd6\x84\x51\xd0\x1d\x93\xd4\xb0\x09\x4d\x23\x75\x0e\x69\x86\xa7\x74\x6e\x53\x0c\x1e\x0e\x0c\x95\xd1\xcf\x06\x57\x53\xa1\x5c\xd9\xc8\xb5\x65\x4b\xa6\xda\x4d\xd5\x66\xfb\x2d\xd6\xff\xed\xec\xb3\xd5\xf2\x57\xb3\x7d\xab\xf5\xe7\x06\x9d\x8a\x28\x37\x59\x7c\x7c\xcc\x6d\xc7\xa3\xc5\xfa\xa2\x95\xfe\x34\xc9\x4a\xa3\xa4\xde\xd0\x96\xdb\x25\xdd\x97\x09\x6a\x36\xbb\xe5\x82\x29\xb7\x05\x39\xb7\xdf\x26\x69\x42\xb6\x9b\xa2\x47\x7e\x6b\xf1\x68\xed\x7d\xd0\x15\x21\xf9\x34\xf5\xa1\x3f\xa3\xe0\x0d\xf4\xe5\xcf\xbe\xf3\x47\x71\x27\x5f\x74\x58\xbc\x90\xa9\x45\xc9\x87\xbc\xa4\x51\x76\x36\xb8\xe6\x34\xef\x7f\xff\x5f\xca\xd5\xdb\x2b\x76\x67\x1e\xf6\xc8\x77\xf3\x00\xc7\x77\xd7\x3c\x75\x49\xd1\xf2\xc6\x3c\xfd\xd0\x1f\xd6\xe4\x2b\x5b\x41\xd9\x6b\x7a\xd7\x85\x5c\x56\xef\xba\xc9\xcd\xfb\x0b\x50\xe4\x46\x77\xee\xdf\x8d\x9d\xb3\x6d\x86\x29\x0b\xae\xa5\xc3\xcd\xc9\x7c\x11\xce\x18\xe0\x8d\xe7\xf2\x64\xe6\x92\xaa\x66\x67\x92\x01\x2d\x5f\xe6\xf2\x2b\xb3\x84\x97\x75\x73\xc7\xb7\x89\x0e\x34\x80\x46\x65\xc2\x63\x88\xc1\x87\x08\xc6\x9f\x4b\x0f\x16\x88\xf2\x66\x67\xa7\xfb\x66\x67\x17\xfe\xff\x99\x6b\x82\x65\x8b\xba\x14\xd9\xa2\x20\xe5\x84\x45\x39\x8f\xbb\xee\x6e\x46\x3b\x2b\xf6\x58\x8e\x11\x51\xf2\xd1\xb7\x58\xa6\x49\xc2\x05\xea\x0b\xe5\x79\x65\xb8\xfd\x6c\x41\xa5\xf3\x0a\x00\xf7\x02\xe6\x5d\x31\xdf\x02\xbe\x55\x4b\xce\x53\xf6\x01\x05\x1e\x52\x58\x25\xcc\x68\xc1\x26\x98\x07\x67\xad\x0f\xba\x26\x5c\xcc\x4a\x5f\x0e\xc2\x8a\x66\x58\xcb\x93\xe9\x78\x16\xaa\xe2\xf2\x39\xaf\x82\x9a\x66\xf3\x1d\x88\xae\x85\xfe\xb6\x98\x56\xa9\x29\x57\x8d\x23\x4a\x67\xb5\x2f\x35\x9a\xfd\xfa\xe3\x8c\x98\xf5\x54\x10\x8a\x65\xdf\x87\xac\x61\xcf\xb0\x42\x62\x6e\x40\x37\x6b\xc0\xb6\x50\x83\xc6\xba\x5a\x9c\x81\xec\x14\x1f\x9c\xcd\x87\x85\x6c\x0d\x1b\x8d\x09\xab\xe9\xa8\xa2\x7d\x41\xfd\x90\xb7\x7c\x8f\xac\xd5\x67\x74\x62\x86\xe5\xfe\x0e\x9a\x9c\x1c\x12\xef\xe8\x6a\x72\x80\x82\x96\xcb\xc9\x70\x6c\x0f\x0b\xfb\xff\x6c\xb2\x39\x04\xcd\xd5\xc3\xd4\xc9\x13\xbd\xb9\x6d\x05\x7a\x9a\x05\x13\x59\x0d\x64\xe3\x82\xed\xae\x20\xd8\x59\x96\xe2\xd8\x24\xcb\xd3\x1f\xab\x68\x66\xe2\xaa\xb2\x3d\x6b\x20\xd1\x1f\x17\xb8\x92\x1c\xcf\x68\x18\x3d\x6f\xf8\x30\x5a\xd6\x9f\xad\xbc\x6c\xb0\x3c\x87\xcd\xf1\xd3\x88\xbd\x84\x78\x32\xe3\xf5\xc2\x70\x20\x91\x61\xc4\xe3\x97\x10\x90\x19\x56\x2f\x2b\xdf\x19\xd3\x1f\x8e\x3d\x37\xcc\x33\x12\x0a\xc3\xec\xf9\x24\x7c\x84\xa3\xd6\xee\xa5\xf9\x87\x17\x16\x97\x56\x8c\x2c\xa4\x91\xe9\x8c\x5d\x52\x75\xa9\x8b\xf1\xb9\x7d\xfa\x97\x88\x5b\x1d\xbb\x77\xc2\x19\xe4\x40\x11\x5d\xef\xcf\x4f\x8f\x4f\x10\x6c\xbf\xb8\xd3\x7a\x51\x65\xf9\x1c\x84\x67\x60\x17\x59\x3d\xe7\xd0\x78\xf5\x16\x2d\x31\x45\x10\x4d\x6d\x58\x25\x6d\xf7\xdd\xd9\x35\x4e\x6e\xb3\x06\xdf\x39\x23\x13\x8e\x6c\x37\xdb\x4d\x4c\xdd\x40\x72\x8d\xbf\xfa\x6a\x96\x9b\x3b\x75\x38\x5b\xf9\x84\x63\xf9\x57\xa9\x88\x58\x6d\x85\xf5\xc1\x80\x7c\x8c\x43\x15\xd2\x48\x7f\x69\x4f\xf0\x1a\x7c\x2b\x07\xae\x53\xa6\xf0\x22\xaf\x63\xea\x62\x7d\x15\xb0\xb8\x63\xfd\xdb\xbf\xd2\x84\xec\x8e\x26\x9b\x33\x5f\x67\xaa\xbe\x1a\x28\x26\xbe\xdd\xfa\x3f\x6b\x3c\x31\xbb\x02\x39\x00\x00")
 
 func webuiJsAppJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -377,7 +377,7 @@ func webuiJsAppJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/app.js", size: 13905, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/app.js", size: 14594, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -422,7 +422,7 @@ func webuiJsFontawesomeJs() (*asset, error) {
 	return a, nil
 }
 
-var _webuiJsKalaJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdc\x56\x4f\x8b\xdb\x3e\x10\xbd\xfb\x53\x0c\xb9\xc8\xc6\xc1\xe1\x77\x8d\x09\x3f\x5a\x58\x28\x5b\x68\x0f\x0b\xbd\xcb\xf6\x24\x96\xeb\xd5\x18\x69\xf2\x67\x59\xf2\xdd\x8b\x14\x3b\xb1\x9b\xcd\x36\xff\xba\x87\xde\x82\xe6\xcd\x9b\xf7\xe6\x29\x46\xe1\x7c\xa9\x73\x56\xa4\xc3\xb5\xd2\x05\xad\x23\x78\x0d\x02\x80\x95\x34\xf0\x55\xd6\xf2\x0b\x59\x86\x19\xec\x6a\x49\x4d\xb9\x74\xd8\xa4\x31\xc4\x94\x53\x0d\x31\x8c\x26\x93\x11\xc4\x47\x88\x92\x2c\x6b\xf9\x8c\x10\x43\x78\xd4\x4d\x86\xe1\x7f\x10\x53\xf1\x46\xa3\x2f\x4e\x41\x88\x28\x6d\x75\xfc\x40\x63\x15\x69\x98\x81\x58\xfd\x27\xba\xd3\x4f\x8d\xfa\x2c\x2d\xc2\xec\xa0\xd3\x89\x91\x8d\xf2\x7a\xba\x26\x77\x36\xea\xf5\x3c\x52\xf6\xa0\x8b\x86\x94\x76\xbe\x3a\x92\x18\x46\x15\x65\x0e\xd8\x22\x2d\x9a\x95\xca\x1d\xfb\x6b\x00\x00\xb0\x40\x7e\xa4\xcc\x4e\x61\xbf\xae\xa8\xad\x00\x18\xe4\xa5\xd1\x30\x47\xce\xcb\x70\x38\x22\x6a\x21\x00\x09\x97\xa8\x0f\xcb\x36\x68\x1b\xd2\x16\x0f\x2c\x3d\xa6\xae\x98\x54\xd6\x0d\xda\x03\xb6\x3d\xba\x5c\xba\x69\x7b\x3e\xdc\x0c\x99\x72\xd2\x96\x6a\x4c\xd0\x18\x32\xa1\x68\xa4\xb1\x4a\x2f\xa0\xa2\xcc\xc2\x5c\xaa\x1a\x8b\x29\x88\x31\xe0\xa6\xcf\x9e\xfa\xdf\xdb\x71\xcf\x71\xcf\xb0\x2a\xce\xb2\x0c\x31\xa8\x02\x62\x10\x13\xf1\xf7\xdc\x0f\xe9\x1c\x70\x48\xa5\xe6\xe0\x4f\x77\xfe\x87\x35\x00\x2e\x0d\xad\x41\xe3\x1a\x1e\xfc\x7a\x7a\xc8\xb4\x07\xdc\x1e\x6b\xf3\xc8\x8a\xb2\xf4\xbe\x99\x9c\x8a\xe4\x58\x2a\x6e\xa2\xf4\x54\x62\x85\xb2\x32\xab\xf1\xda\xd4\x44\xdb\x3f\x11\xbd\x08\xc7\x3d\x07\xcf\xc8\x25\x39\x91\x0d\x59\x16\xc1\xad\xfe\x77\xe3\xfe\xb0\x81\x96\xbe\x75\x88\xfa\x26\x83\xbb\xf6\x8f\xf2\xe7\xa7\x5d\x64\xcf\x2c\xf5\xd5\xde\x2c\x4b\xc3\x1f\x65\xcd\x0f\xbb\xc8\x5a\x81\x35\xf2\xd5\xc9\xbd\x6b\x69\x47\x7d\x87\xfb\xe8\x78\x2e\x32\x95\x1b\x94\xbf\x9b\xaa\x28\x3b\xcb\xd5\xe9\x74\xc6\xfb\xf3\x8c\x8a\x97\x29\x3c\x3e\x7d\xff\x96\x58\x36\x4a\x2f\xd4\xfc\xc5\x0f\x78\xc3\xeb\xbf\xf4\x81\x55\xc5\x3d\xbe\xaf\x3e\x9d\x8b\xf2\xac\x28\x7b\x62\xc9\xf6\xea\x7f\x20\xdb\xc1\x3f\xf0\x6a\xe9\x0b\xe4\xbd\x72\x4f\x7b\x9e\xfe\x67\x64\xa3\xf2\xf3\x1e\x29\xed\xb3\xa7\x53\xfd\xee\x4d\x3a\x79\x8b\x6e\xb9\x41\xfd\xc0\xfd\xd6\xef\x10\x79\xb7\xb7\x76\x11\xe7\x6c\x6d\xeb\x5e\x7c\x5c\x2a\x9b\xfc\x94\xb5\x84\x59\xf7\xf2\x4b\x83\x60\x1b\x85\xae\x10\xa5\xc1\xaf\x00\x00\x00\xff\xff\x80\x39\x9a\x7e\x25\x0b\x00\x00")
+var _webuiJsKalaJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x56\x4d\x8b\xdb\x30\x10\xbd\xe7\x57\x0c\xb9\xc8\xa6\xc1\xa1\xd7\x98\x52\xb6\xb0\xb4\x6c\xa1\x3d\x2c\xf4\xae\xd8\x93\x58\x5b\xaf\x64\x24\x25\xe9\xb2\xf8\xbf\x77\xa4\x28\xae\xbc\xd9\x10\xe7\xa3\x5b\x7a\x49\x82\x66\xde\x9b\x79\x6f\x24\x45\xc9\x62\x25\x0b\x2b\x94\x4c\x36\x42\x96\x6a\x93\xc2\xf3\x68\x04\xb0\xe6\x1a\xbe\xf2\x9a\x7f\x51\xc6\xc2\x07\xd8\xc6\xb2\x5a\x15\xdc\xe5\x66\x8d\x56\x56\x15\xaa\x86\x77\x30\x9e\x4e\xc7\xf4\xf5\x32\xa3\x22\xa0\xe4\x8f\x48\xa1\x64\x0f\xad\xb4\x85\x8f\xc0\x66\xec\x15\xa0\x0f\xce\x80\xb1\x34\x0f\x7d\xfc\x40\x6d\x28\x42\x6d\xb0\xf5\x7b\xb6\x5b\xbd\x69\xc4\x27\x6e\x90\x56\xbb\x3e\x5d\x33\xbc\x11\xbe\x9f\x1d\xc8\xad\x8d\x23\xcc\x9d\x9a\xdf\xca\xb2\x51\x42\x3a\x5d\x3b\x12\xca\x7a\x50\x73\x97\x18\x32\x0d\xea\xb5\x28\x1c\xfb\x33\xad\x00\x2c\xd1\x12\xd2\xcc\xa0\xb3\x2b\x0d\x11\x00\x8d\x76\xa5\x25\x2c\xd0\x16\x55\xd2\x2f\x91\x86\x14\x80\xcc\x56\x28\x93\x0e\xad\xd1\x34\x4a\x1a\xfc\xc3\x12\x31\xed\x82\xd9\x83\x71\x85\xba\x84\x36\xa2\x23\xb7\xa8\x5a\xc7\x87\xbf\xfa\x4c\x05\xe1\x55\x8d\x19\x6a\xad\x74\xc2\x1a\x4e\x6e\xc8\x25\x90\x48\x03\x0b\x2e\x6a\x2c\xc9\xe2\x09\x10\x2c\x62\xcf\xfd\xef\x76\x12\x29\x8e\x04\x8b\x72\x90\x64\xf2\x52\x94\xf4\xc1\xa6\xec\xef\xa9\xef\xd3\xb9\xc4\x3e\x95\x58\x80\x5f\xdd\xea\xef\xc7\x00\x6c\xa5\xd5\x06\x24\x6e\xe0\xd6\xdb\x13\x65\xe6\x51\x62\xbb\xdf\x9b\xcf\x24\x13\xf3\xeb\xce\xe4\xd0\x48\xf6\x5b\xa5\x68\x7e\x68\x62\xa5\x30\x7c\x5e\xe3\xb9\x53\x63\x01\x3f\x65\xd1\x08\x27\x91\x82\x47\xb4\x95\x72\x4d\x36\x74\xd8\xd8\xe8\x52\xfd\xdb\x72\x47\x1c\x08\xf4\x41\x21\xca\x8b\x04\x6e\xe1\x6f\xa5\xcf\x57\x3b\x49\x9e\x5e\xc9\xb3\xb5\x19\xcb\xb5\x7d\x2b\x69\xbe\xd8\x49\xd2\x4a\xac\xd1\xe2\xa5\x17\xca\xab\x92\xb6\xd4\x57\xd8\x8f\x8e\xe7\x24\x51\x85\x46\xfe\x52\x14\x81\x07\xa9\x3a\x3c\x9d\x49\xb7\x3e\x57\xe5\xd3\x0c\xee\xee\xbf\x7f\xcb\x8c\xd5\xd4\x9a\x58\x3c\xf9\x02\xa3\x63\x37\xe2\xff\x7d\xc1\x8a\xf2\x1a\xf7\xab\x9f\xce\x49\xf3\xa4\xc4\x7b\xcb\xad\x39\xfb\x04\x5a\xd3\x3b\x81\x67\xb7\x4e\xff\xbd\x5d\xe7\x9e\x76\x58\xff\x04\xfb\xac\x79\x53\x0d\x7a\xa5\x84\x77\x0f\x5b\x3a\xc4\x91\xff\xea\x83\xdb\xe8\x92\x2d\x14\x4f\xdc\x37\x71\x85\x99\xef\x8c\xf3\x7c\xc3\x4c\xa3\xc3\xa7\x45\x61\x4e\xf2\x2c\x8c\xfa\xdf\x7a\xe6\xb7\xea\x15\x3d\x0b\x46\x0c\x71\xad\x75\xcf\x64\x5b\x09\x93\xfd\xa4\xb7\x37\x3d\x92\xc3\x73\x99\x9e\xcf\x6d\x9a\xb8\x00\x9d\xf3\xdf\xe9\xad\xe2\x14\x5a\x0c\x00\x00")
 
 func webuiJsKalaJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -437,7 +437,7 @@ func webuiJsKalaJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/kala.js", size: 2853, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/kala.js", size: 3162, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -502,7 +502,7 @@ func webuiJsReefRouterMinJs() (*asset, error) {
 	return a, nil
 }
 
-var _webuiJsRoutesJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xcc\x31\x4b\xc4\x30\x18\xc6\xf1\x3d\x9f\xe2\xd9\xa2\x50\xec\x1e\x71\x72\xd1\x8a\x4b\x17\x07\x71\x48\xea\x5b\x8c\xf4\x5a\x78\x93\x5c\x87\x5c\xbe\xfb\xd1\xb4\x85\xa3\xe5\x6e\xb9\x0c\x21\xfc\x79\xf2\x3b\x6a\x06\x0f\xc1\x13\xe3\x05\x3d\x8d\xa8\x89\xda\xa7\x3a\x97\x87\x28\x00\x6f\x7d\x47\x0a\x32\xc6\xfc\x4a\x09\x27\x7c\xe8\x4e\xe3\x8b\x0c\xde\x7b\x4f\xdc\xea\x86\x64\x21\x30\x3b\x4e\xe1\x5b\x00\x40\xcc\x37\x60\x7f\x15\xe4\xff\x60\x5c\xde\x4c\x67\x25\xab\xcb\x18\xb8\x53\x90\xe5\x48\x26\xd8\x72\xa9\xa9\xb8\x43\x9a\x86\xa5\xbc\xea\x1c\xc8\xb3\x6d\xf6\xd4\xe7\xa6\x2f\xda\x32\xbf\x01\x36\x4c\xda\xd3\xce\x7b\xcd\x19\xd5\x60\xb6\xe4\xfc\x61\x15\x05\xf0\x33\x0d\x82\xa3\x37\xed\xfe\x14\x3c\x07\x2a\x44\x7a\x7c\x16\xe7\x00\x00\x00\xff\xff\x4c\x1f\x43\xd9\xa4\x01\x00\x00")
+var _webuiJsRoutesJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x8f\xbd\x0e\x82\x30\x14\x46\x77\x9e\xe2\xdb\xd0\x84\xc8\x8e\x71\x72\xf0\x2f\x2e\x2c\x0e\xc6\xa1\xe0\x45\x30\x08\xa6\xb4\x32\x60\xdf\xdd\x52\x4b\xa2\x10\x59\xec\x70\x73\x73\x72\xee\x49\xfa\x60\x1c\xbc\x94\x82\x38\x16\x28\xa8\x46\x48\x94\xcc\x42\x43\x26\x8d\x03\x88\x4c\xe4\x14\xc0\x6d\x1a\xb3\x29\x85\x27\x76\x2c\x67\x38\x50\x84\x4d\xa1\xb5\x84\xc5\xe4\x7a\x5a\x35\x9d\x2a\xc0\x51\xef\x40\x63\x26\x90\x9d\xf5\xf5\xb5\x8c\x2a\xe3\xb4\xaf\x4b\x6e\x3f\xa1\xe4\xb9\x46\x7e\x4d\x91\xcc\x7c\x4b\x95\xf7\x47\xa9\x15\x7d\xf7\x67\xe7\x46\x82\x67\xf1\x30\xb5\xef\x71\x5b\xb3\xfa\x48\xf0\xc2\xd9\x3d\x1d\xe4\x56\x5f\xd4\xc6\x8c\x3a\x92\x8a\x39\x31\x41\x83\xd6\xd2\x60\xe8\xcf\xf6\x83\xef\x83\xae\xa8\xe7\xa9\x15\x64\x45\x6b\x56\xa5\x01\x04\x97\xe4\x39\x6a\x3a\x77\x5e\x98\x58\x3f\x34\xef\x01\x00\x00")
 
 func webuiJsRoutesJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -517,12 +517,12 @@ func webuiJsRoutesJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/routes.js", size: 420, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/routes.js", size: 495, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webuiJsStoreJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\x41\x6e\xdb\x30\x10\xbc\xfb\x15\x73\x4a\x1c\x40\xd0\x03\x64\xf0\x54\xe7\x90\x20\xbd\xa4\xbd\x05\x39\xd0\xd2\xba\x65\x40\x8b\xc6\x92\x6e\x50\x18\xfa\x7b\x41\x4a\x94\x25\x85\x4c\x9b\xe6\x22\x40\xdc\xe1\x8c\x66\x77\x35\xbf\x24\xc3\x3a\xc3\x04\x81\x96\x5e\xf1\x48\xb4\x2f\xbf\xf9\x83\xf5\x79\x05\x34\xd2\xc9\x0a\xe7\xd5\x0a\x00\x5e\xcc\xce\x56\x38\x77\x45\x7c\xdb\x92\x93\x4a\x57\x68\x4f\x5a\xf7\x87\x07\x72\xac\xea\x09\x4a\x1b\xd9\xa8\xf6\x47\x85\xbd\xd4\x96\x8a\x9e\xa8\x66\x92\x8e\xbe\xff\x3e\x52\x85\x6b\x6d\x6a\xa9\xaf\x8b\x49\xe1\x96\x39\x12\x84\x87\x25\xe7\x88\x3d\x69\x00\x59\x27\xd9\x3d\x8c\xbc\xa7\xb6\x76\xca\xb4\xeb\x23\x9b\xa3\xbd\x19\x30\x40\x78\x2d\x07\x79\x08\x38\x3e\xd1\x26\xd4\x86\x2f\xb3\xe4\xee\x83\xa1\x39\x43\x11\x6c\x2e\x79\xfc\x19\x44\x28\x6d\x32\x02\xc1\x60\x42\x21\x29\x90\xe2\x7f\x7a\x31\xbb\x52\x35\xcf\xbd\x4e\x94\x51\x7b\xac\x47\x4c\xdf\x70\x5c\x5d\x61\x71\x54\xaa\x06\x42\x84\x8b\xa5\x6a\x2e\xec\x58\x02\xe7\xe4\xdd\xbf\x7b\xf9\x69\x5e\xef\x2f\x13\x4f\x58\xba\xdb\x26\x4c\x8d\xa2\x73\x9b\x77\xdb\xe7\x19\x7b\xad\x49\x72\x96\xfe\x3d\x5e\xbf\x7a\x89\xa6\x6f\x95\x95\x3b\x4d\x4d\xe6\x4b\x0b\x34\x03\x20\xbf\x30\x93\x1e\x2c\xa6\x10\x2d\x24\xdb\x1c\x8b\x65\x54\x80\x18\xc5\x36\x23\x3c\x31\xd5\xc9\x08\xa7\xad\x4c\xd8\x7e\x9f\xba\x9b\xcd\x76\xe8\x4a\x43\x9a\x1c\xe5\xb6\xf1\xed\xe8\xfe\xb7\x0d\xbd\x4e\x6e\xda\x9f\x76\x3e\x1b\x78\xd6\xac\x25\xf7\x35\xe6\xd0\xd2\xed\x10\x50\x4b\xbf\xc3\x31\x44\x04\x7c\xe8\x2f\xff\x32\xe6\xd9\x83\x0f\xb3\xbf\xed\xef\x25\xfe\x20\x62\xfe\x65\x19\x1f\xe9\x60\x1c\x7d\x8c\x92\xc3\x9d\x34\x67\x08\xd7\x65\x57\x5a\x79\xa0\x02\xc4\x9c\xe6\xbd\x65\x86\xc0\xb9\x9b\xae\x81\xbf\xf2\xf6\x07\x18\xf1\x4f\xbe\xee\xa3\x8c\x98\xe7\x69\xd3\xf5\xb1\xde\xdd\x6c\x56\x7f\x02\x00\x00\xff\xff\xfe\x1f\x95\x27\x7d\x06\x00\x00")
+var _webuiJsStoreJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x55\xcb\x6e\xc2\x30\x10\xbc\xf3\x15\x7b\x02\x2a\x45\x7c\x00\x88\x53\xa9\x2a\x50\x7b\xa1\xbd\x21\x0e\x6e\xb2\x40\x2a\x13\x23\xc7\xb4\xaa\x68\xfe\xbd\xeb\x47\x42\x62\xec\xb6\xb4\x97\xc4\x5e\xaf\x67\x3c\xbb\xce\xe4\x8d\x49\x28\x95\x90\x08\x53\x28\xf0\x1d\x96\x88\x9b\xd1\x93\x0e\x0c\x4f\x3d\x80\x8c\x29\x36\x86\x53\x8f\x86\x00\xaf\xe2\xa5\xa4\x49\x95\xd4\xb3\x19\x2a\x96\xf3\x31\x14\x47\xce\x6d\x70\x8f\x4a\xe6\x69\x2b\x6b\x2b\xd9\x61\x47\xd3\x42\x64\x48\xe1\xd5\x3a\x01\xcc\xb6\x76\xe8\x52\xb8\x60\x59\x5e\x6c\xc7\xb0\x61\xbc\xc4\xc4\x72\xa5\x12\x99\xc2\xe7\x8f\x03\x8e\x61\xc0\x45\xca\xf8\x20\x69\x2d\xdc\x49\x59\x73\x98\x47\x89\x4a\xa1\xd4\xbc\x26\xa9\x54\x4c\xaa\x87\x06\xf7\x58\xa4\x2a\x17\xc5\xf0\x20\xc5\xa1\xbc\x71\x39\x00\x66\x3a\x72\xf4\xa4\x5f\xc9\x23\x4e\xcc\x9a\x3b\x19\xa1\x2e\x8c\xe6\x2e\x42\x62\x2a\xe1\xe3\xe8\x18\x81\xe8\xd7\x24\x42\x60\x04\x06\x18\x82\x04\x21\xfc\x15\x3d\x46\x79\xb6\xb6\x3c\x35\x4d\xbe\x81\x61\x93\x63\x7b\x02\xfd\x3e\x78\x21\xda\x07\xd3\xa9\xd9\x48\xc3\x33\x3a\xf8\x89\x5d\xf0\xea\xf7\x5a\x76\xe2\x7d\x71\xbe\x14\x01\x49\xf3\x59\x40\x54\x43\xda\x95\x39\x9f\xad\x3b\xe8\x29\x47\x26\xa3\xf0\xdf\xe1\xea\xdb\x19\x28\xfa\x2c\x2f\xd9\x0b\xc7\x2c\x72\xd2\x04\x32\x97\x10\xbf\x30\xad\x1a\x78\x5d\xa8\x25\x04\xcb\x5c\x2f\x8e\x6a\x06\x82\xaa\x87\x93\x26\x3d\xd0\xd5\x56\x0b\xdb\xa5\x0c\xc8\xfe\x1e\xba\xea\xf4\xd6\x55\x25\x43\x8e\x0a\x63\xb7\xf1\xb2\x75\x7f\x2d\x83\xe5\x89\x75\xfb\xdf\xca\x3b\x0d\x8f\x8a\xa5\x2b\x70\x6f\x9d\xc9\xd7\x6a\x0c\xcb\xd7\x6a\x82\x84\x6c\xdf\x9f\x9f\x11\x3f\xbb\xea\xbb\x7f\xac\xad\xd2\x3f\x81\xf3\x50\xff\x0c\x2e\x4c\x70\x6e\x74\x15\xdb\x6d\xe3\xa7\x0f\xda\x4c\x7f\xfa\x7e\xce\xf6\x4b\x80\xce\x7f\xa3\x88\x4b\xdc\x0b\x85\xd7\x41\x4a\xb3\x27\x8c\x69\xcc\xdd\xaf\x4a\xc1\xf6\x48\xb5\x96\x32\x8c\x4b\x7b\x08\xf6\x54\xb5\xaf\xa1\xde\x72\xf9\x01\x36\xf9\x2b\xbd\xae\xad\x94\x40\xbb\x6e\x57\xd9\xdf\x4a\x75\x33\xe9\x7d\x01\x0a\xe8\xb0\xf5\x20\x07\x00\x00")
 
 func webuiJsStoreJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -537,12 +537,12 @@ func webuiJsStoreJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/store.js", size: 1661, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/store.js", size: 1824, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _webuiJsUtilsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x8f\xbb\x4e\xc4\x30\x10\x45\x7b\x7f\xc5\xed\xec\x55\x22\xc4\xb6\x20\x3a\x4a\x4a\xa8\x10\x85\x19\x7b\x77\x0d\xc9\x18\xf9\xc1\x06\x11\xff\x3b\x32\x79\x49\xa9\x3c\xf6\x3d\x3e\xba\x73\xca\x4c\xc9\x79\xc6\x87\x7f\x7f\xfe\xf9\xb2\x8a\x73\x7f\xc0\xaf\x00\xe2\xd5\x25\xba\x60\x7b\x00\x48\x47\x8b\xdb\xbb\xff\x19\x08\x36\xe5\xc0\x90\x4f\x9e\x74\x27\x37\xe0\xb8\x07\x1e\xed\x49\xe7\x2e\x4d\x88\x99\x2e\x7b\xe6\x85\x3f\xd9\x5f\xb9\x32\x45\x14\x21\xd6\x5e\x97\xd4\x77\x2a\xa6\xe0\xf8\x1c\xa7\x1e\xdf\x3a\x40\x87\x73\xc4\x43\x3d\x72\x6f\x39\xc5\x7b\xb1\xaa\x66\xf6\x26\x58\x93\xc9\xaa\x45\xa4\x34\x51\x5b\xc3\x16\xce\x0c\xcb\x46\xf3\x1f\x4d\x84\xa6\x86\x68\xa0\xaa\xfb\xd5\x99\x01\x0d\x8e\x6f\x18\x47\x48\x79\xa8\xfe\xd2\xd6\x49\x14\xf1\x17\x00\x00\xff\xff\xd0\x8d\x69\x39\x34\x01\x00\x00")
+var _webuiJsUtilsJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x57\xdb\x6e\xe3\x38\xd2\xbe\xf7\x53\xd4\x30\x46\x24\xff\x96\x25\xd9\x9d\x43\x4f\x22\x79\xf0\xcf\xcc\xa2\x1b\x8b\xf4\xcd\xa6\xf7\x84\x20\xe8\x30\x12\x6d\xa9\x23\x93\x5a\x92\x8a\xed\x71\x04\xec\xd3\xec\x83\xed\x93\x2c\x8a\xa2\x2c\xd9\xed\x9e\xc5\xc2\x01\x42\x56\x15\xab\xbe\x3a\x92\x5a\x54\x3c\xd1\xb9\xe0\xf0\x55\x3c\x7f\xde\x96\xcc\xe5\xd5\x6a\x04\xbb\x01\x80\x5a\xe7\x3a\xc9\xa0\x23\x00\x24\x54\x31\x08\x6f\xcc\x1a\x40\x32\x5d\x49\x0e\xce\x9d\x48\x68\xe1\x74\x02\xd3\x63\x81\x5f\xd9\x82\x56\x85\x6e\x44\xd2\x66\x73\x2c\xf3\x67\xfe\xc2\xc5\x9a\xa3\x4c\x3d\xa8\x07\x83\x3d\xae\x4c\xaf\x0a\x57\x69\x99\xf3\xa5\x6a\x70\xbc\x52\x09\x54\x2e\x15\xc4\xf8\xaf\x5a\x31\xae\xd5\xed\x60\xaf\xca\xca\xfa\x92\xa5\x55\xc2\xdc\x56\x91\x4b\x93\xc4\x03\xa5\xa5\x07\x79\xba\x69\x3d\xb2\x67\x68\x92\xc0\x18\x99\x30\x06\x17\x75\x3f\xe4\xe9\x06\xc6\x30\x7d\x84\xb7\x37\x70\x9c\x11\xea\xaf\x3d\x5c\x1d\x80\x53\x2f\x79\x59\xb2\xf4\x4f\x15\x57\x9f\xe9\x73\xc1\xdc\x1e\xa1\x31\x91\x2f\xc0\xfd\xa1\x47\x45\x7d\xbd\xad\x5f\x30\xbe\xd4\x19\xc4\x71\x0c\xe1\x11\x28\xc7\x31\x56\xad\xc7\x52\xac\xd1\xe3\xfe\x59\x55\xe4\x09\x73\x47\xbe\x64\xaf\x4c\xaa\x66\x75\xc2\x67\x59\xf1\x93\xee\x62\x64\x9f\x6c\x1a\x22\x2d\xe7\x76\x09\x10\xe9\x74\x3e\xdc\xc9\x8a\xfb\x2a\xc9\x58\x5a\x15\x2c\xfd\x42\x75\x1d\x05\x3a\x3d\x25\x94\xd0\x82\xf1\x94\xca\x03\x81\x28\x68\x35\x3e\xed\x43\xd7\x4b\x52\x67\x3b\xca\x66\x90\x14\x54\xa9\x98\xa8\xea\x59\xe7\xba\x60\x64\x7e\xdf\x78\x09\x18\xd8\x28\xc8\x66\x8d\xa6\x48\x63\x8c\x5b\xe9\x66\x93\xab\xc9\xa2\x2a\x8a\x75\x9e\xea\x0c\x72\x35\xc1\xec\x97\x2c\x25\x7b\x1c\x3a\x63\xf4\x00\x76\xcf\x51\xc3\x9e\xdf\xb7\x4e\xc2\xff\xeb\x28\xd0\xd9\x31\xff\x17\xeb\xdf\x21\xaf\x73\xd0\xac\xfb\x56\x22\xfd\x2c\xd2\x6d\xbb\x03\x18\xee\x30\x79\x75\xcb\x0d\x7a\xec\x28\x30\x6e\xa0\xec\xd3\x41\x65\x31\x95\xd0\x92\x7d\xfc\xfc\xe9\xce\xb5\x65\x6f\x23\x77\x6f\xca\xdb\x55\x23\x73\xde\x97\xac\x2c\x68\xc2\xdc\xe0\x3c\x58\x7a\xe0\x9c\xd3\x55\x79\xeb\x1c\xf3\xa2\x86\x57\xe8\x6f\x59\xf3\x86\xb5\x3c\xc1\x22\x0d\xeb\x1f\x95\x38\xc1\x74\x1a\xe6\xd9\xbb\x1f\x6f\x31\xb1\x7d\xe8\x5a\xe6\xcb\x25\x93\x77\xf4\x99\x15\xae\xdd\x74\x9d\x2b\x38\xc4\xad\x88\x2f\xf8\xad\x25\x27\x22\x65\xaa\xc7\x69\xf6\x6f\x6f\xf0\xf0\x78\xdb\xb6\x91\xe0\x70\x7e\x0e\x86\x63\xfb\xa6\xad\x6b\xa3\xd4\x49\xc4\xaa\x2c\x18\x86\x6f\xdf\x39\x78\x0e\x99\x71\x0c\x8e\xaa\x92\x84\x29\xe5\xa0\x92\x1f\xbe\xaf\x65\x7f\xd6\x06\xdc\x75\x05\xb7\x53\x00\xc6\xe0\x80\x03\x63\x8b\xe1\xab\xc8\xb9\xeb\x78\xce\x68\xe4\x6b\x99\xaf\xdc\x26\x0e\x41\x00\x77\x74\xab\x40\x67\x0c\x52\x56\x32\x9e\x32\x9e\x6c\x61\x29\x69\x99\x81\xa8\x34\xe4\x1c\x12\x51\x54\x2b\xae\x3c\x60\x34\xc9\x70\xf4\x82\xe0\xcc\x52\x41\xe6\xcb\x4c\x83\x58\x40\xae\xd5\x20\x08\x60\x51\x49\x9d\x31\xa5\xa1\xa4\x92\x71\xed\x01\xe5\x29\x48\xd4\x2b\x15\xe4\x1a\xa8\x82\xfb\xbf\x7c\xf0\xbb\x04\x74\x56\x3f\xa0\xd1\xfb\xd7\xa5\x6b\xac\x77\x49\xe0\x22\x65\x7f\x35\x2d\x13\xc3\xf4\x2a\xf4\x0c\xe1\x23\x33\x86\x63\xb8\x08\x3d\xc4\xf2\x81\x96\x10\xc3\xfb\xd0\x03\x29\xd6\xcd\xe6\x5d\xd8\xe5\xab\x10\x12\x13\xb6\x33\x61\x65\x29\x4b\x6f\xc0\x39\xbb\x78\x9f\x5c\x5f\x5f\x38\x1e\x2c\x68\x5e\x34\xa4\xc5\xf4\xe2\xea\xea\xbd\xe3\x81\xce\x57\x2c\xfd\x22\x2a\x6d\xa8\x8b\xe7\xeb\x70\xe1\x78\x90\x50\x9e\xb0\xc2\xca\x5e\x53\xfc\x39\xf5\xed\xc0\x9a\x49\x59\x69\x40\xee\x6a\x34\x6c\xbc\xf0\x11\xab\xf2\x17\x42\xfe\x81\x26\x59\x37\xeb\x90\xdc\x66\xd2\x1c\x7b\x40\x8a\x9f\xa7\x8f\x10\x83\xc1\x5d\x9b\x31\x1e\x04\xf0\xb3\xa8\x78\xca\x52\xcf\xa4\x02\xaf\x2c\xcc\xd5\x57\xf1\xac\x40\x89\x15\xcb\xc4\x1a\x16\x42\xae\x80\x42\xb2\x4d\x0a\xe6\x0f\x00\xf7\xe0\xa2\xdf\xb9\x51\x06\x39\x44\x07\x68\x9a\x42\xba\x85\x7c\x3c\x6e\x31\x34\x6c\x96\x2e\x4f\x81\x45\x72\x2b\xd8\xc2\x45\x9a\xaf\x05\xc2\xfd\x44\x75\xe6\xaf\xe8\xc6\x3d\xe4\x78\x7d\xc9\x85\x14\xab\x47\x18\xc3\xd4\x78\xd5\x7a\x57\xb7\x91\x2b\x85\xc9\x4e\x6d\xb2\x87\xcb\x87\xc7\xff\x29\x84\x36\xc9\x10\x1f\x05\x13\x95\x80\xd1\xf9\x90\x88\x02\xc1\xba\xdd\xe6\xed\x0d\x6f\xb1\x31\x4c\x1b\xa9\x52\xa8\x7e\x12\x5a\x77\x37\x37\x58\x5e\xf0\x7f\xe0\x76\x75\x38\xb6\x15\x67\x4e\x87\x9e\x95\xdc\xde\xf4\xb5\x4f\x60\x3a\x6a\x4f\xd9\x62\x1d\xdb\xda\x6c\x8e\x99\x53\x75\x97\x6a\xf4\x61\x6d\xcb\x1c\xd5\xd8\x7e\xff\x8e\xe5\xf6\x44\xd6\xf6\x41\x9b\x05\x9f\x96\x65\xb1\x75\x79\x55\x14\x4d\x34\x7f\x1f\xc5\xbe\x78\x31\x6d\x18\xf9\x7e\x25\x9c\xbc\xa1\xfb\xd5\x80\x08\x30\xb5\x10\x9b\xf0\x75\xa9\xf6\x40\x8b\x3e\x51\x0b\x93\xd0\xe6\xc4\x66\x0a\xb1\x39\xe6\xe3\xb3\x65\xef\x9c\x07\xdb\x3d\x63\x6b\x19\x16\x73\x00\xb3\xde\xf1\x19\x8e\x5e\xe1\x6f\x3c\xd8\xda\xe5\xef\x89\x17\x38\xda\xbb\x61\xdd\x4c\x7a\x83\xd4\x52\x6c\x49\x7e\xff\xb9\x51\x52\x9d\x41\x1a\x93\x4f\xc3\xdd\x66\x5a\x7b\xc3\xdd\x76\x5a\xc3\x2f\xb8\xd9\xe7\x03\x4d\xb6\x9c\xe1\x6e\x33\x83\xc9\x31\x63\xd6\x30\xec\x9a\x58\xdd\xcd\xdf\x22\x2f\x8a\x98\x70\xc1\x19\xc1\x77\x9d\x78\x61\x31\x39\xc3\xe1\x72\x4d\x09\xac\xa8\x7c\x61\x72\xc2\x78\x1a\x93\x4a\x16\xee\x19\x95\x52\xac\x47\x64\x1e\x05\x08\xac\xbd\xb9\x87\xbb\xc6\xd1\xf3\xf3\x06\x7c\xa4\xd9\x46\xc3\x26\x26\xc3\x9d\x6b\x80\x6e\x66\x23\x83\x92\xc0\xd6\x10\xb7\x48\xdc\x36\x44\x98\xc0\x45\x4d\x60\x21\xb8\x9e\xa8\xfc\x37\x16\x93\xe9\x94\x00\x6a\x98\x50\x9e\x64\x42\xc6\x64\x95\xa7\x69\xc1\x88\xc5\x7a\x76\x41\xf1\x47\xe6\xc3\x5d\xef\xf2\x37\x08\x46\xf8\xb4\x62\x1b\x3d\x7f\xaa\xbf\x79\x52\xd9\x5a\xe3\xf6\xfe\xec\xf7\xf7\xc9\x5a\x3b\xee\xf1\x12\xe2\x83\x3e\xed\xb2\xcc\xe9\x8a\x41\x6c\x6a\xc6\xc7\x75\xdb\x3d\x73\x98\x85\xf0\x53\x8f\xde\xbc\x43\x43\x0f\xa6\x3f\x62\x07\x38\xff\xfe\xe7\xbf\x1c\xb8\xe9\x04\xfe\x5b\x31\x2c\x41\xe9\x6d\xc1\x62\x92\x54\x52\x09\x79\x03\xa5\xc8\xb9\x66\x92\x80\xe0\x49\x91\x27\x2f\x31\xb1\x87\x95\x16\x92\xf9\xa9\x70\x1d\x95\x89\xf5\x1f\xc5\xf3\xaf\x4c\xd3\xbc\x70\x3c\x70\x0e\xa2\x66\x9d\x19\xd5\xce\x68\xff\x12\xc4\x77\x19\xbe\x2e\x0f\xe3\xbb\x07\x39\xaa\x87\x3b\xb3\x29\xa8\xd2\x5f\x64\xc5\xbf\x28\x4d\x75\xa5\xe0\x27\x70\x60\x02\x48\x05\x59\x71\x73\xfd\x9f\x94\xbb\x01\xc7\xc1\x3c\x19\x1b\x9d\x4d\xc9\x12\x5b\x33\xa5\xbf\x69\x2b\xa5\xf4\xb7\x35\x69\x46\x13\x16\xce\xbe\x5f\x6b\x62\xa7\x4f\x4b\x6d\xba\xaf\x26\x20\x37\x31\xb9\x6a\x4b\x65\x9d\xe5\x9a\x1d\xd6\x3b\xec\xab\x7c\xb8\x6b\xee\xe6\x87\x53\x28\xcd\x88\x76\xce\x9e\x2f\xf1\xe7\xd4\x6d\x6f\x4c\x2c\x94\x19\x01\x1b\x85\x34\x57\xf8\x30\x4d\xd1\x7d\x2b\x93\x52\x95\x51\x29\xe9\x36\x26\x97\xde\x3b\x82\x29\x76\x9c\x7a\x1e\x05\xe8\x62\x3f\xca\xfb\x2e\x29\x0f\xa7\x51\xbf\x57\xca\x13\x03\x06\xc6\x70\x79\xd4\x32\xef\x4e\xb7\xcc\x51\x0e\x4d\xfa\x6c\x8b\x58\x1c\x51\xb0\xfc\xf6\xf3\xa3\x7b\xd4\x75\xf5\x17\xa9\xd7\x65\x97\x89\xf5\x37\x59\xc8\xda\x0c\x6c\x56\x05\x57\x31\xc9\xb4\x2e\x6f\x82\x60\xbd\x5e\xfb\xeb\x77\xbe\x90\xcb\x60\x16\x86\x61\xa0\x5e\x97\xdd\x37\x47\xca\x16\xaa\x17\x90\x66\xdc\x40\x9e\xc6\xc4\x0c\x1a\x02\xaf\x39\x5b\xff\x2c\x36\x31\x09\x21\x84\x29\xfe\x11\x90\x6c\xf1\xb7\x98\xd8\xd5\xdf\x63\x72\xd9\xce\x29\x53\x19\x26\xfb\xcd\xfe\xa3\xc5\x76\x45\x40\xc8\x9c\x71\x1d\x13\x5a\x69\xd1\x2b\xf4\xfe\x80\x0d\xbd\x10\xee\xa6\xa1\x77\x09\x77\xa1\x37\x0d\xe1\xb7\xfd\xbc\xb1\xd3\xf0\x68\xe8\xe1\xd7\x4c\x63\xa7\xa5\x44\x41\xdf\x9f\xe1\x0e\x07\xfd\xfe\x6b\xa6\x29\x17\xbb\x8d\x30\x0c\xf3\x01\xc0\xd3\xa0\x1e\xfc\x67\x00\xce\xe8\x98\xe8\x5a\x10\x00\x00")
 
 func webuiJsUtilsJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -557,7 +557,7 @@ func webuiJsUtilsJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "webui/js/utils.js", size: 308, mode: os.FileMode(420), modTime: time.Unix(1664269001, 0)}
+	info := bindataFileInfo{name: "webui/js/utils.js", size: 4186, mode: os.FileMode(420), modTime: time.Unix(1792401377, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
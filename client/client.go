@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ajvb/kala/api"
 	"github.com/ajvb/kala/job"
@@ -112,6 +115,25 @@ func (kc *KalaClient) CreateJob(body *job.Job) (string, error) {
 	return id.Id, err
 }
 
+// ValidateJob checks a job the way CreateJob would, without creating it. It
+// returns everything wrong with the job, which is empty if it is valid.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+// 		body := &job.Job{
+//			Schedule: "R2/2015-06-04T19:25:16.828696-07:00/PT10S",
+//			Name:	  "test_job",
+//			Command:  "bash -c 'date'",
+//		}
+//		problems, err := c.ValidateJob(body)
+func (kc *KalaClient) ValidateJob(body *job.Job) ([]string, error) {
+	resp := &api.ValidateJobResponse{}
+	_, err := kc.do(methodPost, kc.url(jobPath)+"?dry_run=true", http.StatusOK, body, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Errors, nil
+}
+
 // GetJob is used to retrieve a Job from Kala by its ID.
 // Example:
 // 		c := New("http://127.0.0.1:8000")
@@ -170,9 +192,77 @@ func (kc *KalaClient) DeleteAllJobs() (bool, error) {
 //		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
 //		stats, err := c.GetJobStats(id)
 func (kc *KalaClient) GetJobStats(id string) ([]*job.JobStat, error) {
+	var all []*job.JobStat
+	q := job.StatsQuery{Limit: job.MaxStatsLimit, Ascending: true}
+	for {
+		stats, next, err := kc.QueryJobStats(id, q)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, stats...)
+		if next == "" {
+			return all, nil
+		}
+		q.Cursor = next
+	}
+}
+
+// QueryJobStats is used to retrieve a page of the stats of a Job that match
+// the query, and the cursor of the next page, which is empty if there are no
+// more.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		stats, next, err := c.QueryJobStats(id, job.StatsQuery{Statuses: []job.RunStatus{job.RunFailed}})
+func (kc *KalaClient) QueryJobStats(id string, q job.StatsQuery) ([]*job.JobStat, string, error) {
 	js := &api.ListJobStatsResponse{}
-	_, err := kc.do(methodGet, kc.url(jobPath, "stats", id), http.StatusOK, nil, js)
-	return js.JobStats, err
+	_, err := kc.do(methodGet, kc.url(jobPath, "stats", id)+statsQueryString(q), http.StatusOK, nil, js)
+	return js.JobStats, js.NextCursor, err
+}
+
+// ListRuns is used to retrieve a page of the stats of runs of all Jobs that
+// match the query, and the cursor of the next page, which is empty if there
+// are no more.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		runs, next, err := c.ListRuns(job.StatsQuery{From: time.Now().Add(-time.Hour), Statuses: []job.RunStatus{job.RunFailed}})
+func (kc *KalaClient) ListRuns(q job.StatsQuery) ([]*job.JobStat, string, error) {
+	resp := &api.ListRunsResponse{}
+	_, err := kc.do(methodGet, kc.url("runs")+statsQueryString(q), http.StatusOK, nil, resp)
+	return resp.Runs, resp.NextCursor, err
+}
+
+// statsQueryString encodes the query as the query parameters the stats
+// endpoints take.
+func statsQueryString(q job.StatsQuery) string {
+	v := url.Values{}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339Nano))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339Nano))
+	}
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			statuses[i] = string(status)
+		}
+		v.Set("status", strings.Join(statuses, ","))
+	}
+	if q.Limit != 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Cursor != "" {
+		v.Set("cursor", q.Cursor)
+	}
+	// The order is always given, so that the query is paged through as it
+	// says rather than listing all of the stats of a job.
+	if q.Ascending {
+		v.Set("order", "asc")
+	} else {
+		v.Set("order", "desc")
+	}
+	return "?" + v.Encode()
 }
 
 // StartJob is used to manually start a Job by its ID.
@@ -181,7 +271,17 @@ func (kc *KalaClient) GetJobStats(id string) ([]*job.JobStat, error) {
 //		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
 //		ok, err := c.StartJob(id)
 func (kc *KalaClient) StartJob(id string) (bool, error) {
-	_, err := kc.do(methodPost, kc.url(jobPath, "start", id), http.StatusNoContent, nil, nil)
+	return kc.StartJobWithParams(id, nil)
+}
+
+// StartJobWithParams is used to manually start a Job by its ID, passing
+// parameters to the run.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		ok, err := c.StartJobWithParams(id, map[string]interface{}{"date": "2026-10-01"})
+func (kc *KalaClient) StartJobWithParams(id string, params map[string]interface{}) (bool, error) {
+	_, err := kc.StartJobRun(id, params)
 	if err != nil {
 		if err == ErrGenericError {
 			return false, nil
@@ -191,6 +291,69 @@ func (kc *KalaClient) StartJob(id string) (bool, error) {
 	return true, nil
 }
 
+// StartJobRun is used to manually start a Job by its ID, passing parameters
+// to the run. It returns the run, which can be followed with GetJobRun.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		run, err := c.StartJobRun(id, nil)
+func (kc *KalaClient) StartJobRun(id string, params map[string]interface{}) (*job.JobRun, error) {
+	var payload interface{}
+	if params != nil {
+		payload = params
+	}
+	jr := &api.JobRunResponse{}
+	_, err := kc.do(methodPost, kc.url(jobPath, "start", id), http.StatusAccepted, payload, jr)
+	return jr.Run, err
+}
+
+// GetJobRun retrieves a run of a Job, which may still be in progress.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		run, err := c.GetJobRun(id, runId)
+func (kc *KalaClient) GetJobRun(id, runId string) (*job.JobRun, error) {
+	jr := &api.JobRunResponse{}
+	_, err := kc.do(methodGet, kc.url(jobPath, id, "runs", runId), http.StatusOK, nil, jr)
+	return jr.Run, err
+}
+
+// CancelJobRun is used to cancel a run of a Job that is in progress.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		run, err := c.CancelJobRun(id, runId)
+func (kc *KalaClient) CancelJobRun(id, runId string) (*job.JobRun, error) {
+	jr := &api.JobRunResponse{}
+	_, err := kc.do(methodPost, kc.url(jobPath, id, "runs", runId, "cancel"), http.StatusAccepted, nil, jr)
+	return jr.Run, err
+}
+
+// BackfillJob is used to run a Job once for each point of its schedule from
+// from up to, but not including, to, with up to parallelism runs at a time.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		to := time.Now()
+//		b, err := c.BackfillJob(id, to.AddDate(0, 0, -7), to, 2)
+func (kc *KalaClient) BackfillJob(id string, from, to time.Time, parallelism int) (*job.Backfill, error) {
+	req := &api.BackfillRequest{From: from, To: to, Parallelism: parallelism}
+	br := &api.BackfillResponse{}
+	_, err := kc.do(methodPost, kc.url(jobPath, id, "backfill"), http.StatusAccepted, req, br)
+	return br.Backfill, err
+}
+
+// GetBackfill retrieves a backfill of a Job and its runs.
+// Example:
+// 		c := New("http://127.0.0.1:8000")
+//		id := "93b65499-b211-49ce-57e0-19e735cc5abd"
+//		b, err := c.GetBackfill(id, backfillId)
+func (kc *KalaClient) GetBackfill(id, backfillId string) (*job.Backfill, error) {
+	br := &api.BackfillResponse{}
+	_, err := kc.do(methodGet, kc.url(jobPath, id, "backfill", backfillId), http.StatusOK, nil, br)
+	return br.Backfill, err
+}
+
 // GetKalaStats retrieves system-level metrics about Kala
 // Example:
 // 		c := New("http://127.0.0.1:8000")
@@ -94,6 +94,27 @@ func TestCreateJobError(t *testing.T) {
 	cleanUp()
 }
 
+func TestValidateJob(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+
+	problems, err := kc.ValidateJob(NewJobMap())
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	j := NewJobMap()
+	j.Command = ""
+	j.ParentJobs = []string{"missing"}
+	problems, err = kc.ValidateJob(j)
+	assert.NoError(t, err)
+	assert.Len(t, problems, 2)
+
+	jobs, err := kc.GetAllJobs()
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
 func TestGetJobError(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
@@ -231,6 +252,47 @@ func TestGetJobStatsError(t *testing.T) {
 	assert.Nil(t, stats)
 }
 
+func TestListRuns(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+	j := NewJobMap()
+
+	id, err := kc.CreateJob(j)
+	assert.NoError(t, err)
+	from := time.Now()
+	for i := 0; i < 2; i++ {
+		run, err := kc.StartJobRun(id, nil)
+		assert.NoError(t, err)
+		for run.Status == job.RunRunning {
+			time.Sleep(100 * time.Millisecond)
+			run, err = kc.GetJobRun(id, run.Id)
+			assert.NoError(t, err)
+		}
+	}
+
+	runs, next, err := kc.ListRuns(job.StatsQuery{From: from, Limit: 1, Statuses: []job.RunStatus{job.RunSucceeded}})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	assert.NotEmpty(t, next)
+
+	runs, next, err = kc.ListRuns(job.StatsQuery{From: from, Limit: 1, Statuses: []job.RunStatus{job.RunSucceeded}, Cursor: next})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	assert.Empty(t, next)
+
+	stats, err := kc.GetJobStats(id)
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.True(t, stats[0].RanAt.Before(stats[1].RanAt))
+	}
+
+	_, _, err = kc.ListRuns(job.StatsQuery{Limit: job.MaxStatsLimit + 1})
+	assert.Error(t, err)
+
+	cleanUp()
+}
+
 func TestStartJob(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
@@ -258,6 +320,120 @@ func TestStartJob(t *testing.T) {
 	cleanUp()
 }
 
+func TestStartJobWithParams(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+	j := NewJobMap()
+
+	id, err := kc.CreateJob(j)
+	assert.NoError(t, err)
+
+	params := map[string]interface{}{"date": "2026-10-01"}
+	ok, err := kc.StartJobWithParams(id, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Wait let the job run
+	time.Sleep(time.Second * 1)
+
+	stats, err := kc.GetJobStats(id)
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, params, stats[0].Params)
+	}
+
+	cleanUp()
+}
+
+func TestGetJobRun(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+	j := NewJobMap()
+
+	id, err := kc.CreateJob(j)
+	assert.NoError(t, err)
+
+	run, err := kc.StartJobRun(id, nil)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, run) {
+		return
+	}
+
+	// Wait let the job run
+	time.Sleep(time.Second * 1)
+
+	respRun, err := kc.GetJobRun(id, run.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, job.RunSucceeded, respRun.Status)
+
+	_, err = kc.GetJobRun(id, "not-an-actual-run")
+	assert.Equal(t, ErrGenericError, err)
+
+	cleanUp()
+}
+
+func TestCancelJobRun(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+	j := NewJobMap()
+	j.Command = "sleep 30"
+
+	id, err := kc.CreateJob(j)
+	assert.NoError(t, err)
+
+	run, err := kc.StartJobRun(id, nil)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, run) {
+		return
+	}
+
+	_, err = kc.CancelJobRun(id, run.Id)
+	assert.NoError(t, err)
+
+	// Wait for the run to stop
+	time.Sleep(time.Second * 1)
+
+	respRun, err := kc.GetJobRun(id, run.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, job.RunCancelled, respRun.Status)
+
+	cleanUp()
+}
+
+func TestBackfillJob(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+	kc := New(ts.URL)
+	j := NewJobMap()
+	j.Schedule = fmt.Sprintf("R/%s/PT1H", time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	id, err := kc.CreateJob(j)
+	assert.NoError(t, err)
+
+	b, err := kc.BackfillJob(id, time.Now().Add(-2*time.Hour), time.Now(), 2)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, b) {
+		return
+	}
+	assert.Len(t, b.LogicalTimes, 2)
+
+	// Wait let the jobs run
+	time.Sleep(time.Second * 1)
+
+	respBackfill, err := kc.GetBackfill(id, b.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, job.RunSucceeded, respBackfill.Status)
+	assert.Len(t, respBackfill.Runs, 2)
+
+	_, err = kc.BackfillJob(id, time.Now(), time.Now().Add(-time.Hour), 1)
+	assert.Equal(t, ErrGenericError, err)
+
+	cleanUp()
+}
+
 func TestStartJobError(t *testing.T) {
 	ts := NewTestServer()
 	defer ts.Close()
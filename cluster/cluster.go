@@ -0,0 +1,224 @@
+package cluster
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNoRegistry = errors.New("The job database does not support cluster membership")
+)
+
+// Member is a kala node taking part in a cluster.
+type Member struct {
+	Id string `json:"id"`
+
+	// Address other nodes use to reach this node's API, in 'host:port' format.
+	Address string `json:"address"`
+
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Registry tracks cluster membership in the store shared by all nodes.
+// Job databases that can be shared between nodes implement it.
+type Registry interface {
+	// Heartbeat registers the member, or refreshes its LastSeen time.
+	Heartbeat(m *Member) error
+	// Members returns every registered member, including expired ones.
+	Members() ([]*Member, error)
+	// Leave removes the member from the registry.
+	Leave(id string) error
+}
+
+// Cache is the part of the job cache a Cluster needs to keep it in step
+// with the shared job database.
+type Cache interface {
+	Sync() error
+	Reload(id string) error
+	Owned() []string
+}
+
+// Cluster distributes jobs across the nodes sharing a job database. Each
+// job is owned by exactly one live node, chosen by consistent hashing on the
+// job id; every node schedules every job, but only the owner runs it.
+type Cluster struct {
+	self     Member
+	registry Registry
+	cache    Cache
+	interval time.Duration
+
+	lock    sync.RWMutex
+	ring    *Ring
+	members map[string]*Member
+	stop    chan struct{}
+}
+
+// New creates a Cluster for the local node. Members that haven't sent a
+// heartbeat for three intervals are considered gone.
+func New(self Member, registry Registry, cache Cache, interval time.Duration) *Cluster {
+	return &Cluster{
+		self:     self,
+		registry: registry,
+		cache:    cache,
+		interval: interval,
+		ring:     NewRing(DefaultReplicas),
+		members:  map[string]*Member{},
+		stop:     make(chan struct{}),
+	}
+}
+
+// Join registers the local node and builds the initial ring. It should be
+// called before the cache is started so that jobs are only run by their owner.
+func (c *Cluster) Join() error {
+	if err := c.refresh(); err != nil {
+		return err
+	}
+	log.Infof("Node %s joined cluster at %s", c.self.Id, c.self.Address)
+	return nil
+}
+
+// Start heartbeats and rebalances every interval until Leave is called.
+func (c *Cluster) Start() {
+	go func() {
+		wait := time.NewTicker(c.interval)
+		defer wait.Stop()
+		for {
+			select {
+			case <-wait.C:
+				if err := c.refresh(); err != nil {
+					log.Errorf("Error occurred refreshing cluster membership. Err: %s", err)
+				}
+				if err := c.cache.Sync(); err != nil {
+					log.Errorf("Error occurred syncing jobs from the db. Err: %s", err)
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Leave stops heartbeating and removes the local node from the registry, so
+// the remaining nodes take over its jobs without waiting for it to expire.
+func (c *Cluster) Leave() error {
+	close(c.stop)
+	return c.registry.Leave(c.self.Id)
+}
+
+// Owns reports whether the local node should run the job.
+func (c *Cluster) Owns(id string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	owner := c.ring.Get(id)
+	return owner == "" || owner == c.self.Id
+}
+
+// OwnerAddress returns the API address of the node that owns the job, and
+// whether that node is this one.
+func (c *Cluster) OwnerAddress(id string) (string, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	owner := c.ring.Get(id)
+	if owner == "" || owner == c.self.Id {
+		return c.self.Address, true
+	}
+	return c.members[owner].Address, false
+}
+
+// Members returns the live members of the cluster, sorted by id.
+func (c *Cluster) Members() []*Member {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	members := make([]*Member, 0, len(c.members))
+	for _, m := range c.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Id < members[j].Id })
+	return members
+}
+
+// refresh sends a heartbeat, reads the current membership and, if it has
+// changed, rebuilds the ring and reloads any jobs this node has taken over.
+func (c *Cluster) refresh() error {
+	// Members hands out the member of this node too, so it is updated as a
+	// copy rather than in place.
+	c.lock.Lock()
+	c.self.LastSeen = time.Now()
+	self := c.self
+	c.lock.Unlock()
+	if err := c.registry.Heartbeat(&self); err != nil {
+		return err
+	}
+
+	all, err := c.registry.Members()
+	if err != nil {
+		return err
+	}
+
+	expiry := time.Now().Add(-3 * c.interval) //nolint:gomnd
+	live := map[string]*Member{self.Id: &self}
+	for _, m := range all {
+		if m.Id != self.Id && m.LastSeen.After(expiry) {
+			live[m.Id] = m
+		}
+	}
+
+	c.lock.Lock()
+	if sameMembers(c.members, live) {
+		c.members = live
+		c.lock.Unlock()
+		return nil
+	}
+
+	ids := make([]string, 0, len(live))
+	for id := range live {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	previous := c.ring
+	c.ring = NewRing(DefaultReplicas)
+	c.ring.Add(ids...)
+	c.members = live
+	c.lock.Unlock()
+
+	log.Infof("Cluster membership changed, rebalancing jobs across: %s", strings.Join(ids, ", "))
+
+	c.rebalance(previous)
+	return nil
+}
+
+// rebalance reloads the jobs that moved to this node, so that it runs them
+// with the metadata their previous owner persisted. A job that can't be
+// reloaded doesn't keep the others from being taken over.
+func (c *Cluster) rebalance(previous *Ring) {
+	for _, id := range c.cache.Owned() {
+		if prev := previous.Get(id); prev == "" || prev == c.self.Id {
+			continue
+		}
+		log.Infof("Job %s moved to node %s", id, c.self.Id)
+		if err := c.cache.Reload(id); err != nil {
+			log.Errorf("Error occurred reloading job %s. Err: %s", id, err)
+		}
+	}
+}
+
+func sameMembers(a, b map[string]*Member) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package cluster
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRegistry struct {
+	members map[string]Member
+	lock    sync.Mutex
+}
+
+func newMemoryRegistry() *memoryRegistry {
+	return &memoryRegistry{members: map[string]Member{}}
+}
+
+func (r *memoryRegistry) Heartbeat(m *Member) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.members[m.Id] = *m
+	return nil
+}
+
+func (r *memoryRegistry) Members() ([]*Member, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	members := []*Member{}
+	for _, m := range r.members {
+		m := m
+		members = append(members, &m)
+	}
+	return members, nil
+}
+
+func (r *memoryRegistry) Leave(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.members, id)
+	return nil
+}
+
+type mockCache struct {
+	ids      []string
+	owner    func(id string) bool
+	reloaded []string
+	// Ids of the jobs that fail to reload.
+	failing map[string]bool
+}
+
+func (c *mockCache) Sync() error { return nil }
+
+func (c *mockCache) Reload(id string) error {
+	c.reloaded = append(c.reloaded, id)
+	if c.failing[id] {
+		return fmt.Errorf("job %s couldn't be loaded", id)
+	}
+	return nil
+}
+
+func (c *mockCache) Owned() []string {
+	owned := []string{}
+	for _, id := range c.ids {
+		if c.owner(id) {
+			owned = append(owned, id)
+		}
+	}
+	return owned
+}
+
+func newTestCluster(id string, registry Registry, ids []string) (*Cluster, *mockCache) {
+	cache := &mockCache{ids: ids}
+	c := New(Member{Id: id, Address: id + ":8000"}, registry, cache, time.Minute)
+	cache.owner = c.Owns
+	return c, cache
+}
+
+func jobIds(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("job-%d", i)
+	}
+	return ids
+}
+
+func TestClusterSingleNodeOwnsEverything(t *testing.T) {
+	a, _ := newTestCluster("a", newMemoryRegistry(), nil)
+	assert.NoError(t, a.Join())
+
+	assert.True(t, a.Owns("job"))
+	addr, local := a.OwnerAddress("job")
+	assert.True(t, local)
+	assert.Equal(t, "a:8000", addr)
+}
+
+func TestClusterShardsJobs(t *testing.T) {
+	registry := newMemoryRegistry()
+	ids := jobIds(100)
+	a, _ := newTestCluster("a", registry, ids)
+	b, _ := newTestCluster("b", registry, ids)
+
+	assert.NoError(t, a.Join())
+	assert.NoError(t, b.Join())
+	assert.NoError(t, a.refresh())
+
+	assert.Len(t, a.Members(), 2)
+	ownedByA := 0
+	for _, id := range ids {
+		assert.NotEqual(t, a.Owns(id), b.Owns(id), "job %s should have exactly one owner", id)
+		if a.Owns(id) {
+			ownedByA++
+			addr, local := b.OwnerAddress(id)
+			assert.False(t, local)
+			assert.Equal(t, "a:8000", addr)
+		}
+	}
+	assert.True(t, ownedByA > 0 && ownedByA < len(ids))
+}
+
+func TestClusterRebalancesWhenNodeLeaves(t *testing.T) {
+	registry := newMemoryRegistry()
+	ids := jobIds(100)
+	a, cacheA := newTestCluster("a", registry, ids)
+	b, _ := newTestCluster("b", registry, ids)
+
+	assert.NoError(t, a.Join())
+	assert.NoError(t, b.Join())
+	assert.NoError(t, a.refresh())
+
+	movedFromB := []string{}
+	for _, id := range ids {
+		if b.Owns(id) {
+			movedFromB = append(movedFromB, id)
+		}
+	}
+
+	assert.NoError(t, b.Leave())
+	assert.NoError(t, a.refresh())
+
+	for _, id := range ids {
+		assert.True(t, a.Owns(id))
+	}
+	assert.ElementsMatch(t, movedFromB, cacheA.reloaded)
+}
+
+func TestClusterRebalanceCarriesOnPastFailedReloads(t *testing.T) {
+	registry := newMemoryRegistry()
+	ids := jobIds(100)
+	a, cacheA := newTestCluster("a", registry, ids)
+	b, _ := newTestCluster("b", registry, ids)
+
+	assert.NoError(t, a.Join())
+	assert.NoError(t, b.Join())
+	assert.NoError(t, a.refresh())
+
+	movedFromB := []string{}
+	for _, id := range ids {
+		if b.Owns(id) {
+			movedFromB = append(movedFromB, id)
+		}
+	}
+	cacheA.failing = map[string]bool{movedFromB[0]: true}
+
+	assert.NoError(t, b.Leave())
+	assert.NoError(t, a.refresh())
+	assert.ElementsMatch(t, movedFromB, cacheA.reloaded)
+}
+
+func TestClusterExpiresSilentNodes(t *testing.T) {
+	registry := newMemoryRegistry()
+	a, _ := newTestCluster("a", registry, nil)
+	assert.NoError(t, a.Join())
+
+	assert.NoError(t, registry.Heartbeat(&Member{Id: "b", Address: "b:8000", LastSeen: time.Now().Add(-time.Hour)}))
+	assert.NoError(t, a.refresh())
+
+	assert.Len(t, a.Members(), 1)
+	assert.True(t, a.Owns("job"))
+}
+
+func TestClusterMembersAreSnapshots(t *testing.T) {
+	c, _ := newTestCluster("node-a", newMemoryRegistry(), jobIds(1))
+	assert.NoError(t, c.Join())
+
+	members := c.Members()
+	seen := members[0].LastSeen
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, c.refresh())
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = c.Members()[0].LastSeen
+	}
+	<-done
+
+	// Refreshing doesn't change members handed out before.
+	assert.Equal(t, seen, members[0].LastSeen)
+	assert.True(t, c.Members()[0].LastSeen.After(seen))
+}
@@ -0,0 +1,57 @@
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// DefaultReplicas is the number of points each node is given on the ring.
+// More points give a more even spread of jobs at the cost of a larger ring.
+const DefaultReplicas = 64
+
+// Ring is a consistent hash ring mapping job ids onto node ids. Adding or
+// removing a node only moves the jobs that hashed to that node's points.
+type Ring struct {
+	replicas int
+	hashes   []uint32
+	nodes    map[uint32]string
+}
+
+// NewRing creates an empty ring that places each node on it replicas times.
+func NewRing(replicas int) *Ring {
+	if replicas < 1 {
+		replicas = DefaultReplicas
+	}
+	return &Ring{
+		replicas: replicas,
+		nodes:    map[uint32]string{},
+	}
+}
+
+// Add places the given node ids on the ring.
+func (r *Ring) Add(ids ...string) {
+	for _, id := range ids {
+		for i := 0; i < r.replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + id))
+			r.hashes = append(r.hashes, h)
+			r.nodes[h] = id
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// Get returns the id of the node responsible for key, or an empty string if
+// the ring is empty.
+func (r *Ring) Get(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.nodes[r.hashes[i]]
+}
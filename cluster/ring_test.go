@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingEmpty(t *testing.T) {
+	r := NewRing(DefaultReplicas)
+	assert.Equal(t, "", r.Get("job"))
+}
+
+func TestRingDistribution(t *testing.T) {
+	r := NewRing(DefaultReplicas)
+	r.Add("a", "b", "c")
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		counts[r.Get(fmt.Sprintf("job-%d", i))]++
+	}
+
+	assert.Len(t, counts, 3)
+	for node, count := range counts {
+		assert.True(t, count > 500, "node %s only got %d jobs", node, count)
+	}
+}
+
+func TestRingStableWhenNodeLeaves(t *testing.T) {
+	before := NewRing(DefaultReplicas)
+	before.Add("a", "b", "c")
+	after := NewRing(DefaultReplicas)
+	after.Add("a", "b")
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("job-%d", i)
+		if owner := before.Get(key); owner != "c" {
+			assert.Equal(t, owner, after.Get(key), "job %s moved although its node is still up", key)
+		}
+	}
+}
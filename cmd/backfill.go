@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ajvb/kala/client"
+	"github.com/ajvb/kala/job"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill [job id]",
+	Short: "backfill a job over a past time range",
+	Long: `runs a job on a kala server once for each point of its schedule from --from up to,
+but not including, --to. Each run gets the point it is for as its logical time,
+which templates get as {{ .LogicalTime }} and commands as KALA_LOGICAL_TIME.
+Times are given as RFC 3339, e.g. 2026-10-01T00:00:00Z, or as dates in UTC.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatal("Must include the id of the job to backfill")
+		}
+		from, err := parseBackfillTime(viper.GetString("from"))
+		if err != nil {
+			log.Fatalf("Invalid --from: %s", err)
+		}
+		to, err := parseBackfillTime(viper.GetString("to"))
+		if err != nil {
+			log.Fatalf("Invalid --to: %s", err)
+		}
+
+		kc := client.New(viper.GetString("endpoint"))
+		b, err := kc.BackfillJob(args[0], from, to, viper.GetInt("parallelism"))
+		if err != nil {
+			log.Fatalf("Could not start the backfill: %s", err)
+		}
+		fmt.Printf("Started backfill %s of %d runs.\n", b.Id, len(b.LogicalTimes))
+		if !viper.GetBool("wait") {
+			return
+		}
+
+		for b.Status == job.RunRunning {
+			time.Sleep(time.Second)
+			if b, err = kc.GetBackfill(args[0], b.Id); err != nil {
+				log.Fatalf("Could not follow the backfill: %s", err)
+			}
+		}
+		for _, run := range b.Runs {
+			fmt.Printf("%s\t%s\n", run.LogicalTime.Format(time.RFC3339), run.Status)
+		}
+		fmt.Printf("Backfill %s.\n", b.Status)
+		if b.Status != job.RunSucceeded {
+			os.Exit(1)
+		}
+	},
+}
+
+func parseBackfillTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func init() {
+	RootCmd.AddCommand(backfillCmd)
+	backfillCmd.Flags().String("from", "", "Start of the range to backfill, included.")
+	backfillCmd.Flags().String("to", "", "End of the range to backfill, not included.")
+	backfillCmd.Flags().Int("parallelism", 1, "How many runs can happen at the same time.")
+	backfillCmd.Flags().Bool("wait", false, "Wait for the backfill to finish, and print how each run went.")
+	backfillCmd.Flags().String("endpoint", "http://127.0.0.1:8000", "Address of the kala server running the job.")
+}
@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/mail"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ajvb/kala/api"
+	"github.com/ajvb/kala/cluster"
 	"github.com/ajvb/kala/job"
 	"github.com/ajvb/kala/job/storage/boltdb"
 	"github.com/ajvb/kala/job/storage/consul"
@@ -18,9 +24,16 @@ import (
 	"github.com/ajvb/kala/job/storage/redis"
 
 	redislib "github.com/garyburd/redigo/redis"
+	uuid "github.com/nu7hatch/gouuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"gopkg.in/mgo.v2"
 )
 
@@ -32,6 +45,13 @@ var serveCmd = &cobra.Command{
 		if viper.GetBool("verbose") {
 			log.SetLevel(log.DebugLevel)
 		}
+		switch viper.GetString("log-format") {
+		case "text":
+		case "json":
+			log.SetFormatter(&log.JSONFormatter{})
+		default:
+			log.Fatalf("Unknown log format %q, expected 'text' or 'json'.", viper.GetString("log-format"))
+		}
 
 		var parsedPort string
 		port := viper.GetString("port")
@@ -155,16 +175,214 @@ var serveCmd = &cobra.Command{
 			log.Fatal("With transactional persistence off, you will need to set persist-every to greater than zero.")
 		}
 
+		// Join the cluster before starting the cache, so that jobs are only ever run by their owner.
+		var c *cluster.Cluster
+		if viper.GetBool("cluster") {
+			registry, ok := db.(cluster.Registry)
+			if !ok {
+				log.Fatalf("%s: '%s'", cluster.ErrNoRegistry, viper.GetString("jobdb"))
+			}
+			if !cache.PersistOnWrite {
+				log.Fatal("Clustering requires transactional persistence; remove no-tx-persist.")
+			}
+
+			self := cluster.Member{
+				Id:      viper.GetString("cluster-node-id"),
+				Address: viper.GetString("cluster-advertise"),
+			}
+			if self.Id == "" {
+				u4, err := uuid.NewV4()
+				if err != nil {
+					log.Fatal(err)
+				}
+				self.Id = u4.String()
+			}
+			if self.Address == "" {
+				self.Address = connectionString
+			}
+
+			heartbeat := time.Duration(viper.GetInt("cluster-heartbeat")) * time.Second
+			c = cluster.New(self, registry, cache, heartbeat)
+			if err := c.Join(); err != nil {
+				log.Fatal(err)
+			}
+			cache.Sharder = c
+			c.Start()
+		}
+
+		// Stats retention for jobs without one of their own
+		cache.DefaultRetention = defaultRetention()
+
+		// Webhooks for the events of all jobs, and of the jobs with webhooks
+		// of their own
+		cache.Notifiers = append(cache.Notifiers, webhookNotifier())
+
+		// Emails to the owners of failing jobs
+		if viper.GetString("smtp-addr") != "" {
+			cache.Notifiers = append(cache.Notifiers, emailNotifier())
+		}
+
 		// Startup cache
 		cache.Start(time.Duration(persistEvery)*time.Second, time.Duration(viper.GetInt("jobstat-ttl"))*time.Minute)
 
+		// Send spans of job runs and API requests to an OTLP collector
+		stopTracing := setupTracing()
+
 		// Launch API server
 		log.Infof("Starting server on port %s", connectionString)
 		srv := api.MakeServer(connectionString, cache, viper.GetString("default-owner"), viper.GetBool("profile"))
-		log.Fatal(srv.ListenAndServe())
+		go func() {
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}()
+
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+		s := <-ch
+		log.Infof("Process got signal: %s", s)
+		log.Infof("Shutting down....")
+
+		shutdown(srv, cache, c, db, time.Duration(viper.GetInt("shutdown-timeout"))*time.Second)
+		stopTracing()
 	},
 }
 
+// shutdown stops kala gracefully: API writes are refused while running jobs
+// are given the timeout to finish, then everything is persisted and closed.
+func shutdown(srv *http.Server, cache *job.LockFreeJobCache, c *cluster.Cluster, db job.JobDB, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Drain running jobs and persist all jobs to database
+	if err := cache.Shutdown(ctx); err != nil {
+		log.Errorln(err)
+	}
+
+	// Hand our jobs over to the rest of the cluster
+	if c != nil {
+		if err := c.Leave(); err != nil {
+			log.Errorln(err)
+		}
+	}
+
+	srvCtx, srvCancel := context.WithTimeout(context.Background(), 5*time.Second) //nolint:gomnd
+	defer srvCancel()
+	if err := srv.Shutdown(srvCtx); err != nil {
+		log.Errorln(err)
+	}
+
+	// Close the database
+	if err := db.Close(); err != nil {
+		log.Errorln(err)
+	}
+}
+
+// defaultRetention is the retention of job stats that the jobstat flags set,
+// or nil if they leave all stats to be kept.
+func defaultRetention() *job.Retention {
+	r := &job.Retention{
+		MaxRuns:       viper.GetInt("jobstat-max-runs"),
+		MaxFailedRuns: viper.GetInt("jobstat-max-failed-runs"),
+	}
+	if ttl := viper.GetInt("jobstat-ttl"); ttl > 0 {
+		r.MaxAge = fmt.Sprintf("PT%dM", ttl)
+	}
+	if ttl := viper.GetInt("jobstat-failed-ttl"); ttl > 0 {
+		r.MaxFailedAge = fmt.Sprintf("PT%dM", ttl)
+	}
+	if r.MaxRuns < 0 || r.MaxFailedRuns < 0 {
+		log.Fatal("jobstat-max-runs and jobstat-max-failed-runs can't be negative.")
+	}
+	if *r == (job.Retention{}) {
+		return nil
+	}
+	return r
+}
+
+// webhookNotifier returns the notifier of the webhooks that the webhook flags
+// set, which also sends to the webhooks of each job.
+func webhookNotifier() *job.WebhookNotifier {
+	n := &job.WebhookNotifier{
+		Retries:    viper.GetInt("webhook-retries"),
+		RetryDelay: time.Second,
+	}
+	events := []job.EventType{}
+	for _, e := range viper.GetStringSlice("webhook-events") {
+		events = append(events, job.EventType(e))
+	}
+	for _, u := range viper.GetStringSlice("webhook-url") {
+		w := &job.Webhook{Url: u, Events: events, Jobs: viper.GetStringSlice("webhook-jobs"), Secret: viper.GetString("webhook-secret")}
+		if err := w.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		n.Hooks = append(n.Hooks, w)
+	}
+	return n
+}
+
+// emailNotifier returns the notifier that emails job owners, as the smtp
+// flags set it up.
+func emailNotifier() *job.EmailNotifier {
+	n := &job.EmailNotifier{
+		Addr:           viper.GetString("smtp-addr"),
+		Username:       viper.GetString("smtp-username"),
+		Password:       viper.GetString("smtp-password"),
+		From:           viper.GetString("smtp-from"),
+		NotifyRecovery: viper.GetBool("smtp-notify-recovery"),
+		MinInterval:    time.Duration(viper.GetInt("smtp-min-interval")) * time.Minute,
+	}
+	if _, err := mail.ParseAddress(n.From); err != nil {
+		log.Fatalf("smtp-from needs to be the address emails are sent from: %s", err)
+	}
+	if path := viper.GetString("smtp-template"); path != "" {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		n.Template, err = job.ParseEmailTemplate(string(text))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return n
+}
+
+// setupTracing sends spans of job runs and API requests to the OTLP collector
+// that the otlp flags point at, if any. It returns a function that sends the
+// spans left, for calling on shutdown.
+func setupTracing() func() {
+	endpoint := viper.GetString("otlp-endpoint")
+	if endpoint == "" {
+		return func() {}
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if viper.GetBool("otlp-insecure") {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("kala")))
+	if err != nil {
+		log.Fatal(err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.Infof("Sending traces to %s", endpoint)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) //nolint:gomnd
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			log.Errorln(err)
+		}
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(serveCmd)
 	serveCmd.Flags().StringP("port", "p", ":8000", "Port for Kala to run on.")
@@ -181,8 +399,31 @@ func init() {
 	serveCmd.Flags().String("jobdb-tls-keypath", "", "Path to tls client key file for the job database.")
 	serveCmd.Flags().String("jobdb-tls-servername", "", "Server name to verify cert for the job database.")
 	serveCmd.Flags().BoolP("verbose", "v", false, "Set for verbose logging.")
+	serveCmd.Flags().String("log-format", "text", "Format of the logs, either 'text' or 'json'.")
 	serveCmd.Flags().IntP("persist-every", "e", 60*60, "Interval in seconds between persisting all jobs to db") //nolint:gomnd
 	serveCmd.Flags().Int("jobstat-ttl", -1, "Sets the jobstat-ttl in minutes. The default -1 value indicates JobStat entries will be kept forever")
+	serveCmd.Flags().Int("jobstat-max-runs", 0, "Most JobStat entries kept per job, the latest ones. The default 0 value keeps them all")
+	serveCmd.Flags().Int("jobstat-failed-ttl", -1, "Minutes JobStat entries of failed runs are kept, instead of jobstat-ttl")
+	serveCmd.Flags().Int("jobstat-max-failed-runs", 0, "Most JobStat entries of failed runs kept per job, instead of jobstat-max-runs")
 	serveCmd.Flags().Bool("profile", false, "Activate pprof handlers")
 	serveCmd.Flags().Bool("no-tx-persist", false, "Only persist to db periodically, not transactionally.")
+	serveCmd.Flags().Int("shutdown-timeout", 60, "Seconds to wait for running jobs to finish when shutting down, before interrupting them.") //nolint:gomnd
+	serveCmd.Flags().Bool("cluster", false, "Share jobs with the other kala nodes using the same job database. Supported by 'redis' and 'consul'.")
+	serveCmd.Flags().String("cluster-node-id", "", "Unique id of this node within the cluster, default is a random uuid.")
+	serveCmd.Flags().String("cluster-advertise", "", "Address other nodes use to reach this node's API, in 'host:port' format. Default is the listen address.")
+	serveCmd.Flags().Int("cluster-heartbeat", 5, "Interval in seconds between cluster heartbeats. Nodes are considered gone after three missed heartbeats.") //nolint:gomnd
+	serveCmd.Flags().StringSlice("webhook-url", nil, "URL to POST the events of all jobs to. Can be given more than once.")
+	serveCmd.Flags().StringSlice("webhook-events", nil, "Events sent to the webhook urls, e.g. job.failed,job.disabled. Default is all of them.")
+	serveCmd.Flags().StringSlice("webhook-jobs", nil, "Ids of the jobs whose events are sent to the webhook urls. Default is all of them.")
+	serveCmd.Flags().String("webhook-secret", "", "Secret to sign the requests to the webhook urls with.")
+	serveCmd.Flags().Int("webhook-retries", 3, "Times a failed webhook delivery is retried, waiting twice as long each time.") //nolint:gomnd
+	serveCmd.Flags().String("smtp-addr", "", "Address of the SMTP server to email job owners through, in 'host:port' format. Default is no emails.")
+	serveCmd.Flags().String("smtp-username", "", "Username for the SMTP server, if it needs one.")
+	serveCmd.Flags().String("smtp-password", "", "Password for the SMTP server.")
+	serveCmd.Flags().String("smtp-from", "", "Address that emails to job owners are sent from.")
+	serveCmd.Flags().Bool("smtp-notify-recovery", false, "Also email job owners when a job succeeds after failing.")
+	serveCmd.Flags().Int("smtp-min-interval", 15, "Minutes between emails about the failures of a job. The failures in between are counted in the next email.") //nolint:gomnd
+	serveCmd.Flags().String("smtp-template", "", "File with the template of emails, defining a \"subject\" and a \"body\" template. Default is job.DefaultEmailTemplate.")
+	serveCmd.Flags().String("otlp-endpoint", "", "Address of the OTLP/HTTP collector to send traces to, in 'host:port' format, e.g. localhost:4318. Default is no tracing.")
+	serveCmd.Flags().Bool("otlp-insecure", false, "Send traces to the OTLP collector over plain HTTP instead of HTTPS.")
 }
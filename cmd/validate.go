@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ajvb/kala/client"
+	"github.com/ajvb/kala/job"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [job.json ...]",
+	Short: "validate job definitions",
+	Long: `checks job definitions in JSON files, or stdin if none are given, without creating them.
+With --endpoint the jobs are checked by a running kala server, which also checks
+the jobs and calendars they refer to.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+
+		valid := true
+		for _, path := range args {
+			problems, err := validateJobFile(path, viper.GetString("endpoint"))
+			if err != nil {
+				log.Fatalf("Could not validate %s: %s", path, err)
+			}
+			for _, p := range problems {
+				fmt.Printf("%s: %s\n", path, p)
+			}
+			valid = valid && len(problems) == 0
+		}
+
+		if !valid {
+			os.Exit(1)
+		}
+		fmt.Println("Jobs are valid!")
+	},
+}
+
+func validateJobFile(path, endpoint string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	j := &job.Job{}
+	if err := json.NewDecoder(r).Decode(j); err != nil {
+		return nil, err
+	}
+
+	if endpoint != "" {
+		return client.New(endpoint).ValidateJob(j)
+	}
+	problems := []string{}
+	for _, err := range j.Validate(nil) {
+		problems = append(problems, err.Error())
+	}
+	return problems, nil
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().String("endpoint", "", "Address of a kala server to validate the jobs with, e.g. http://127.0.0.1:8000.")
+}
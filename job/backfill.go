@@ -0,0 +1,282 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mixer/clock"
+	uuid "github.com/nu7hatch/gouuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxBackfillRuns is the most runs a single backfill can be made of.
+	MaxBackfillRuns = 1000
+	// MaxBackfills is how many backfills are kept for looking up.
+	MaxBackfills = 100
+)
+
+var (
+	ErrBackfillDoesntExist  = errors.New("The backfill you requested does not exist")
+	ErrBackfillNoSchedule   = errors.New("Job cannot be backfilled, as it has no schedule")
+	ErrInvalidBackfillRange = errors.New("Invalid backfill range. It must end after it starts")
+	ErrEmptyBackfill        = errors.New("Invalid backfill range. The job isn't scheduled to run in it")
+	ErrTooManyBackfillRuns  = fmt.Errorf("Invalid backfill range. It must cover at most %d runs", MaxBackfillRuns)
+)
+
+// Backfill runs a job once for each point of its schedule in a time range,
+// e.g. to make up for runs that failed during an outage. Each run gets the
+// point it is for as its logical time.
+type Backfill struct {
+	Id    string    `json:"id"`
+	JobId string    `json:"job_id"`
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	// How many of the runs can happen at the same time.
+	Parallelism int `json:"parallelism"`
+
+	// Running until all of the runs have finished, then succeeded if they
+	// all succeeded, and failed otherwise.
+	Status     RunStatus `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	// The logical times of the runs, in order.
+	LogicalTimes []time.Time `json:"logical_times"`
+	// The runs started so far, in the same order.
+	Runs []*JobRun `json:"runs"`
+
+	clk  clock.Clock
+	lock sync.RWMutex
+	done chan struct{}
+}
+
+// backfillTracker is implemented by caches that keep backfills for looking up.
+type backfillTracker interface {
+	addBackfill(b *Backfill)
+	getBackfill(id string) (*Backfill, bool)
+}
+
+// Occurrences returns the points of the job's schedule from from up to, but
+// not including, to. Schedules that repeat forever are taken to have also
+// repeated before they started, so that jobs can be backfilled over a range
+// from before they were created. Points excluded by the job's calendars are
+// left out, and jitter isn't applied.
+func (j *Job) Occurrences(from, to time.Time) ([]time.Time, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	if j.Schedule == "" || j.scheduleTime.IsZero() {
+		return nil, ErrBackfillNoSchedule
+	}
+	if !to.After(from) {
+		return nil, ErrInvalidBackfillRange
+	}
+
+	points := []time.Time{}
+	walked := 0
+	// visit adds the point unless the job's calendars exclude it. Excluded
+	// points aren't counted as runs, but there can't be any number of them
+	// either.
+	visit := func(runPoint time.Time) error {
+		walked++
+		if walked > MaxBackfillRuns+maxCalendarSkips {
+			return ErrTooManyBackfillRuns
+		}
+		if calendar, _ := j.excludedBy(runPoint); calendar == "" && !runPoint.Before(from) {
+			if len(points) == MaxBackfillRuns {
+				return ErrTooManyBackfillRuns
+			}
+			points = append(points, runPoint)
+		}
+		return nil
+	}
+
+	// Start from the last point at or before from, as it may be any number of
+	// intervals away.
+	start := int64(0)
+	if j.timesToRepeat != 0 && j.hasInterval() {
+		if n := j.scheduleIndex(from); n > 0 || !j.hasFixedRepetitions() {
+			start = n
+		}
+	}
+	if start < 0 && !j.hasFixedInterval() {
+		// Stepping forward from a point before the start doesn't lead back
+		// to it for intervals in days or months, so those points are stepped
+		// back to from the start instead.
+		earlier, err := j.pointsBeforeStart(from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, runPoint := range earlier {
+			if err := visit(runPoint); err != nil {
+				return nil, err
+			}
+		}
+		start = 0
+	}
+
+	runPoint := j.schedulePoint(start)
+	for i := start; runPoint.Before(to); i++ {
+		if j.hasFixedRepetitions() && i > j.timesToRepeat {
+			break
+		}
+		if err := visit(runPoint); err != nil {
+			return nil, err
+		}
+		if j.timesToRepeat == 0 {
+			break
+		}
+		next := j.delayDuration.Add(runPoint)
+		if !next.After(runPoint) {
+			break
+		}
+		runPoint = next
+	}
+	return points, nil
+}
+
+// pointsBeforeStart returns the points of the job's schedule before it starts
+// that are in the range from from up to to, earliest first.
+func (j *Job) pointsBeforeStart(from, to time.Time) ([]time.Time, error) {
+	points := []time.Time{}
+	for runPoint := j.delayDuration.Sub(j.scheduleTime); !runPoint.Before(from); runPoint = j.delayDuration.Sub(runPoint) {
+		if !runPoint.Before(to) {
+			continue
+		}
+		if len(points) == MaxBackfillRuns+maxCalendarSkips {
+			return nil, ErrTooManyBackfillRuns
+		}
+		points = append(points, runPoint)
+	}
+	for i, k := 0, len(points)-1; i < k; i, k = i+1, k-1 {
+		points[i], points[k] = points[k], points[i]
+	}
+	return points, nil
+}
+
+// Backfill runs the job in the background once for each point of its schedule
+// from from up to, but not including, to, with up to parallelism runs at a
+// time. It returns the backfill straight away so that it can be followed. The
+// runs don't affect when the job next runs on its schedule.
+func (j *Job) Backfill(cache JobCache, from, to time.Time, parallelism int) (*Backfill, error) {
+	points, err := j.Occurrences(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, ErrEmptyBackfill
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	u4, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	b := &Backfill{
+		Id:           u4.String(),
+		JobId:        j.Id,
+		From:         from,
+		To:           to,
+		Parallelism:  parallelism,
+		Status:       RunRunning,
+		StartedAt:    j.clk.Time().Now(),
+		LogicalTimes: points,
+		Runs:         []*JobRun{},
+		clk:          j.clk.Time(),
+		done:         make(chan struct{}),
+	}
+	if t, ok := cache.(backfillTracker); ok {
+		t.addBackfill(b)
+	}
+	j.logger().WithField("backfill_id", b.Id).Infof("Job started backfill of %d runs.", len(points))
+
+	go b.run(cache, j)
+	return b, nil
+}
+
+// run starts the runs of the backfill in order, up to Parallelism at a time,
+// and waits for them to finish. It stops starting runs if kala is shutting
+// down.
+func (b *Backfill) run(cache JobCache, j *Job) {
+	slots := make(chan struct{}, b.Parallelism)
+	var wg sync.WaitGroup
+
+	for _, t := range b.LogicalTimes {
+		slots <- struct{}{}
+		run, err := j.start(cache, runOptions{backfillId: b.Id, logicalTime: t, source: SourceBackfill})
+		if err != nil {
+			j.logger().WithField("backfill_id", b.Id).WithError(err).Errorf("Backfill couldn't start the run for %s.", t)
+			break
+		}
+		b.lock.Lock()
+		b.Runs = append(b.Runs, run)
+		b.lock.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-run.Done()
+			<-slots
+		}()
+
+		run.lock.RLock()
+		skipped := run.Status == RunSkipped
+		run.lock.RUnlock()
+		if skipped {
+			break
+		}
+	}
+	wg.Wait()
+	b.finish()
+}
+
+func (b *Backfill) finish() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.Status = RunSucceeded
+	if len(b.Runs) < len(b.LogicalTimes) {
+		b.Status = RunFailed
+	}
+	for _, run := range b.Runs {
+		run.lock.RLock()
+		if run.Status != RunSucceeded {
+			b.Status = RunFailed
+		}
+		run.lock.RUnlock()
+	}
+	b.FinishedAt = b.clk.Now()
+	close(b.done)
+	log.WithFields(log.Fields{LogFieldJobId: b.JobId, "backfill_id": b.Id}).WithFields(logDuration(b.FinishedAt.Sub(b.StartedAt))).Infof("Backfill %s.", b.Status)
+}
+
+// GetBackfill looks up a backfill of the job by id.
+func (j *Job) GetBackfill(cache JobCache, id string) (*Backfill, error) {
+	if t, ok := cache.(backfillTracker); ok {
+		if b, ok := t.getBackfill(id); ok && b.JobId == j.Id {
+			return b, nil
+		}
+	}
+	return nil, ErrBackfillDoesntExist
+}
+
+// Done returns a channel that is closed once all of the runs of the backfill
+// have finished.
+func (b *Backfill) Done() <-chan struct{} {
+	return b.done
+}
+
+// MarshalJSON locks the backfill while it is marshaled.
+func (b *Backfill) MarshalJSON() ([]byte, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	type alias Backfill
+	return json.Marshal((*alias)(b))
+}
@@ -0,0 +1,215 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dailyFromTomorrow returns a schedule running daily at midnight UTC from
+// tomorrow on, and a function giving the day n days from tomorrow.
+func dailyFromTomorrow(repeat string) (string, func(n int) time.Time) {
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return repeat + "/" + tomorrow.Format(time.RFC3339) + "/P1D", func(n int) time.Time {
+		return tomorrow.AddDate(0, 0, n)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	cache := NewMockCache()
+	schedule, day := dailyFromTomorrow("R")
+	j := GetMockJob()
+	j.Schedule = schedule
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	// Before the schedule started, and after.
+	for _, from := range []int{-10, 3} {
+		points, err := j.Occurrences(day(from), day(from+7).Add(-time.Hour))
+		assert.NoError(t, err)
+		if assert.Len(t, points, 7) {
+			assert.True(t, day(from).Equal(points[0]))
+			assert.True(t, day(from+6).Equal(points[6]))
+		}
+	}
+
+	_, err := j.Occurrences(day(3), day(3))
+	assert.ErrorIs(t, err, ErrInvalidBackfillRange)
+	_, err = j.Occurrences(day(1), day(1).AddDate(5, 0, 0))
+	assert.ErrorIs(t, err, ErrTooManyBackfillRuns)
+
+	schedule, _ = dailyFromTomorrow("R2")
+	fixed := GetMockJob()
+	fixed.Schedule = schedule
+	assert.NoError(t, fixed.Init(cache))
+	defer fixed.StopTimer()
+	points, err := fixed.Occurrences(day(-10), day(10))
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+
+	_, err = GetMockJob().Occurrences(day(1), day(10))
+	assert.ErrorIs(t, err, ErrBackfillNoSchedule)
+}
+
+func TestOccurrencesAtMonthEnds(t *testing.T) {
+	cache := NewMockCache()
+	start := time.Date(2030, time.January, 31, 10, 0, 0, 0, time.UTC)
+	j := GetMockRecurringJobWithSchedule(start, "P1M")
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2030, month, day, 10, 0, 0, 0, time.UTC)
+	}
+
+	// The points are the ones the job runs at.
+	points, err := j.Occurrences(start, day(time.May, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{start, day(time.March, 3), day(time.April, 3)}, points)
+	for i, runAt := range j.NextRuns(3) {
+		assert.WithinDuration(t, points[i], runAt, time.Millisecond)
+	}
+
+	points, err = j.Occurrences(day(time.June, 1), day(time.September, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{day(time.June, 3), day(time.July, 3), day(time.August, 3)}, points)
+
+	// Before the start, they are stepped back to from it.
+	points, err = j.Occurrences(start.AddDate(0, -3, 0), day(time.February, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2029, time.November, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2029, time.December, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2029, time.December, 31, 10, 0, 0, 0, time.UTC),
+		start,
+	}, points)
+}
+
+func TestOccurrencesFarFromScheduleStart(t *testing.T) {
+	cache := NewMockCache()
+	start := time.Now().UTC().Truncate(time.Minute).Add(time.Hour)
+	j := GetMockRecurringJobWithSchedule(start, "PT1M")
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	// Years away from the start of the schedule, before it and after.
+	for _, from := range []time.Time{start.AddDate(-20, 0, 0), start.AddDate(20, 0, 0)} {
+		points, err := j.Occurrences(from.Add(-30*time.Second), from.Add(150*time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{from, from.Add(time.Minute), from.Add(2 * time.Minute)}, points)
+	}
+
+	_, err := j.Occurrences(start.AddDate(-20, 0, 0), start)
+	assert.ErrorIs(t, err, ErrTooManyBackfillRuns)
+}
+
+func TestBackfill(t *testing.T) {
+	cache := NewMockCache()
+	schedule, day := dailyFromTomorrow("R")
+	j := GetMockJob()
+	j.Schedule = schedule
+	j.Retries = 0
+	j.TemplateDelimiters = "{{ }}"
+	j.Command = `bash -c 'test "$(printenv KALA_LOGICAL_TIME)" = {{ .LogicalTime.Format "2006-01-02T15:04:05Z07:00" }}'`
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+	j.lock.RLock()
+	nextRunAt := j.NextRunAt
+	j.lock.RUnlock()
+
+	from := day(-7)
+	b, err := j.Backfill(cache, from, from.AddDate(0, 0, 3), 2)
+	assert.NoError(t, err)
+	select {
+	case <-b.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Backfill didn't finish")
+	}
+
+	assert.Equal(t, RunSucceeded, b.Status)
+	if assert.Len(t, b.Runs, 3) {
+		for i, run := range b.Runs {
+			assert.True(t, from.AddDate(0, 0, i).Equal(run.LogicalTime))
+			assert.Equal(t, RunSucceeded, run.Status)
+			assert.Equal(t, b.Id, run.Stat.BackfillId)
+			assert.Equal(t, SourceBackfill, run.Stat.TriggerSource)
+		}
+	}
+
+	found, err := j.GetBackfill(cache, b.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, b, found)
+	_, err = j.GetBackfill(cache, "not-a-backfill")
+	assert.ErrorIs(t, err, ErrBackfillDoesntExist)
+
+	j.lock.RLock()
+	assert.Len(t, j.Stats, 3)
+	assert.Equal(t, nextRunAt, j.NextRunAt)
+	j.lock.RUnlock()
+}
+
+func TestParallelBackfillCounts(t *testing.T) {
+	cache := NewMockCache()
+	schedule, day := dailyFromTomorrow("R")
+	j := GetMockJob()
+	j.Schedule = schedule
+	j.Retries = 0
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	backfill := func(command string) {
+		j.lock.Lock()
+		j.Command = command
+		j.lock.Unlock()
+		b, err := j.Backfill(cache, day(-10), day(0), 5)
+		assert.NoError(t, err)
+		select {
+		case <-b.Done():
+		case <-time.After(10 * time.Second):
+			t.Fatal("Backfill didn't finish")
+		}
+	}
+	backfill("bash -c 'sleep 0.2'")
+	backfill("bash -c 'sleep 0.2; false'")
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	statuses := map[RunStatus]int{}
+	for _, stat := range j.Stats {
+		statuses[stat.status()]++
+	}
+	assert.Equal(t, map[RunStatus]int{RunSucceeded: 10, RunFailed: 10}, statuses)
+	// Backfills don't count as runs of the job.
+	assert.Equal(t, Metadata{}, j.Metadata)
+}
+
+func TestBackfillDoesntUseUpRepetitions(t *testing.T) {
+	cache := NewMockCache()
+	start := time.Now().Truncate(time.Second).Add(2 * time.Second)
+	j := GetMockJob()
+	j.Schedule = "R3/" + start.Format(time.RFC3339) + "/PT1S"
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	b, err := j.Backfill(cache, start, start.Add(time.Hour), 4)
+	assert.NoError(t, err)
+	select {
+	case <-b.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Backfill didn't finish")
+	}
+	j.lock.RLock()
+	assert.Len(t, j.Stats, 4)
+	assert.Equal(t, Metadata{}, j.Metadata)
+	j.lock.RUnlock()
+	assert.Len(t, j.NextRuns(10), 4)
+
+	// R3 is the first run and three repetitions, which all still happen.
+	waitForJob(j)
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Len(t, j.Stats, 8)
+	assert.Equal(t, uint(4), j.Metadata.NumberOfFinishedRuns)
+	assert.Equal(t, uint(4), j.Metadata.SuccessCount)
+}
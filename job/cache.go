@@ -1,14 +1,15 @@
 package job
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/signal"
+	"sort"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
+	"github.com/ajvb/kala/utils/iso8601"
 	"github.com/cornelk/hashmap"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,6 +28,43 @@ type JobCache interface {
 	Disable(j *Job) error
 }
 
+// Sharder assigns jobs to nodes when kala runs as a cluster.
+type Sharder interface {
+	// Owns reports whether the local node should run the job.
+	Owns(id string) bool
+	// OwnerAddress returns the API address of the node that owns the job,
+	// and whether that node is the local one.
+	OwnerAddress(id string) (string, bool)
+}
+
+// sharded is implemented by caches that only run the jobs they own.
+type sharded interface {
+	Owns(id string) bool
+}
+
+func ownsJob(cache JobCache, id string) bool {
+	if s, ok := cache.(sharded); ok {
+		return s.Owns(id)
+	}
+	return true
+}
+
+// leaser is implemented by caches that can take run leases from their db.
+type leaser interface {
+	Leaser
+	SupportsLeases() bool
+}
+
+// runTracker is implemented by caches that keep track of in-flight runs, so
+// that they can be drained on shutdown.
+type runTracker interface {
+	// startRun registers a run, returning the context it should run with.
+	// It returns false if no new runs are being accepted.
+	startRun() (context.Context, bool)
+	finishRun()
+	stopping() bool
+}
+
 type JobsMap struct {
 	Jobs map[string]*Job
 	Lock sync.RWMutex
@@ -45,12 +83,19 @@ type MemoryJobCache struct {
 	jobs           *JobsMap
 	jobDB          JobDB
 	PersistOnWrite bool
+	// Applies to the stats of jobs without a retention of their own.
+	DefaultRetention *Retention
+	// Told about the events of the jobs.
+	Notifiers []Notifier
+
+	*runGroup
 }
 
 func NewMemoryJobCache(jobDB JobDB) *MemoryJobCache {
 	return &MemoryJobCache{
-		jobs:  NewJobsMap(),
-		jobDB: jobDB,
+		jobs:     NewJobsMap(),
+		jobDB:    jobDB,
+		runGroup: newRunGroup(),
 	}
 }
 
@@ -79,25 +124,22 @@ func (c *MemoryJobCache) Start(persistWaitTime time.Duration) {
 		go c.PersistEvery(persistWaitTime)
 	}
 
-	// Process-level defer for shutting down the db.
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	go func() {
-		s := <-ch
-		log.Infof("Process got signal: %s", s)
-		log.Infof("Shutting down....")
+	// Run retention every minute to clean up old job stats entries
+	go c.RetainEvery(1 * time.Minute)
+}
 
-		// Persist all jobs to database
-		err = c.Persist()
-		if err != nil {
-			log.Errorln(err)
-		}
+// Shutdown stops the cache gracefully, as LockFreeJobCache.Shutdown does. It
+// is up to the caller to call it, e.g. on a signal, and to close the db.
+func (c *MemoryJobCache) Shutdown(ctx context.Context) error {
+	c.drain(ctx)
 
-		// Close the database
-		c.jobDB.Close()
+	c.jobs.Lock.RLock()
+	for _, j := range c.jobs.Jobs {
+		j.StopTimer()
+	}
+	c.jobs.Lock.RUnlock()
 
-		os.Exit(0)
-	}()
+	return c.Persist()
 }
 
 func (c *MemoryJobCache) Get(id string) (*Job, error) {
@@ -166,6 +208,8 @@ func (c *MemoryJobCache) Delete(id string) error {
 		log.Errorln(j.DeleteFromDependentJobs(c)) // todo: review
 	}()
 
+	forgetJobMetrics(j)
+	forgetJob(c, id)
 	delete(c.jobs.Jobs, id)
 
 	return err
@@ -193,6 +237,44 @@ func (c *MemoryJobCache) Persist() error {
 	return nil
 }
 
+// Retain removes the stats that retention doesn't keep, and saves the jobs that
+// had any removed to the db, which is then compacted if it can be.
+func (c *MemoryJobCache) Retain() error {
+	now := time.Now()
+	failed := []error{}
+	c.jobs.Lock.RLock()
+	for _, j := range c.jobs.Jobs {
+		if err := retainJobStats(j, c.DefaultRetention, now, c.jobDB); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	c.jobs.Lock.RUnlock()
+	if err := compactDB(c.jobDB, c.DefaultRetention, now); err != nil {
+		failed = append(failed, err)
+	}
+	return retentionError(failed)
+}
+
+func (c *MemoryJobCache) RetainEvery(retentionWaitTime time.Duration) {
+	wait := time.NewTicker(retentionWaitTime).C
+	var err error
+	for {
+		<-wait
+		err = c.Retain()
+		if err != nil {
+			log.Errorf("Error occurred during invoking retention. Err: %s", err)
+		}
+	}
+}
+
+func (c *MemoryJobCache) defaultRetention() *Retention {
+	return c.DefaultRetention
+}
+
+func (c *MemoryJobCache) notifiers() []Notifier {
+	return c.Notifiers
+}
+
 func (c *MemoryJobCache) PersistEvery(persistWaitTime time.Duration) {
 	wait := time.NewTicker(persistWaitTime).C
 	var err error
@@ -206,44 +288,69 @@ func (c *MemoryJobCache) PersistEvery(persistWaitTime time.Duration) {
 }
 
 type LockFreeJobCache struct {
-	jobs            *hashmap.HashMap
-	jobDB           JobDB
-	retentionPeriod time.Duration
-	PersistOnWrite  bool
+	jobs           *hashmap.HashMap
+	jobDB          JobDB
+	PersistOnWrite bool
+	// Applies to the stats of jobs without a retention of their own.
+	DefaultRetention *Retention
+	// Told about the events of the jobs.
+	Notifiers []Notifier
+	// Sharder, if set, limits the jobs this cache runs and persists to the
+	// ones owned by the local node.
+	Sharder Sharder
 	Clock
+
+	*runGroup
+
+	calendars     map[string]*Calendar
+	calendarsLock sync.RWMutex
+
+	// Workflow runs by id, and their ids oldest first.
+	workflowRuns     map[string]*WorkflowRun
+	workflowRunIds   []string
+	workflowRunsLock sync.RWMutex
+
+	// Runs started with Job.Start by id, and their ids oldest first.
+	jobRuns     map[string]*JobRun
+	jobRunIds   []string
+	jobRunsLock sync.RWMutex
+
+	// Backfills by id, and their ids oldest first.
+	backfills     map[string]*Backfill
+	backfillIds   []string
+	backfillsLock sync.RWMutex
 }
 
 func NewLockFreeJobCache(jobDB JobDB) *LockFreeJobCache {
 	return &LockFreeJobCache{
-		jobs:            hashmap.New(8), //nolint:gomnd
-		jobDB:           jobDB,
-		retentionPeriod: -1,
+		jobs:         hashmap.New(8), //nolint:gomnd
+		jobDB:        jobDB,
+		runGroup:     newRunGroup(),
+		calendars:    map[string]*Calendar{},
+		workflowRuns: map[string]*WorkflowRun{},
+		jobRuns:      map[string]*JobRun{},
+		backfills:    map[string]*Backfill{},
 	}
 }
 
+// Start loads the jobs from the db and schedules them. Stats older than
+// jobstatTtl, if it is positive, are removed from jobs without a retention of
+// their own, unless DefaultRetention is set.
 func (c *LockFreeJobCache) Start(persistWaitTime time.Duration, jobstatTtl time.Duration) {
 	if persistWaitTime == 0 {
 		c.PersistOnWrite = true
 	}
 
 	// Prep cache
+	if err := c.loadCalendars(); err != nil {
+		log.Fatal(err)
+	}
 	allJobs, err := c.jobDB.GetAll()
 	if err != nil {
 		log.Fatal(err)
 	}
 	for _, j := range allJobs {
-		if j.Schedule == "" {
-			log.Infof("Job %s:%s skipped.", j.Name, j.Id)
-			continue
-		}
-		if j.ShouldStartWaiting() {
-			j.StartWaiting(c, false)
-		}
-		log.Infof("Job %s:%s added to cache.", j.Name, j.Id)
-		err := c.Set(j)
-		if err != nil {
-			log.Errorln(err)
-		}
+		c.schedule(j)
 	}
 	// Occasionally, save items in cache to db.
 	if persistWaitTime > 0 {
@@ -251,27 +358,432 @@ func (c *LockFreeJobCache) Start(persistWaitTime time.Duration, jobstatTtl time.
 	}
 
 	// Run retention every minute to clean up old job stats entries
-	if jobstatTtl > 0 {
-		c.retentionPeriod = jobstatTtl
-		go c.RetainEvery(1 * time.Minute)
+	if jobstatTtl > 0 && c.DefaultRetention == nil {
+		ttl := &iso8601.Duration{Seconds: int(jobstatTtl / time.Second)}
+		c.DefaultRetention = &Retention{MaxAge: ttl.String()}
 	}
+	go c.RetainEvery(1 * time.Minute)
+}
 
-	// Process-level defer for shutting down the db.
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	go func() {
-		s := <-ch
-		log.Infof("Process got signal: %s", s)
-		log.Infof("Shutting down....")
+// Shutdown stops the cache gracefully. No new runs are started, and running
+// jobs are given until ctx is done to finish. Runs still going after that are
+// interrupted, and recorded as such. Finally all job timers are stopped and all
+// jobs are persisted; the db is left open for the caller to close.
+func (c *LockFreeJobCache) Shutdown(ctx context.Context) error {
+	c.drain(ctx)
 
-		// Persist all jobs to database
-		log.Errorln(c.Persist())
+	// Running jobs hold their lock, so timers can only be stopped once they're done.
+	for el := range c.jobs.Iter() {
+		el.Value.(*Job).StopTimer()
+	}
+
+	return c.Persist()
+}
 
-		// Close the database
-		c.jobDB.Close()
+// Stopping reports whether the cache is shutting down.
+func (c *LockFreeJobCache) Stopping() bool {
+	return c.stopping()
+}
 
-		os.Exit(0)
+// runGroup keeps track of the runs in progress of a cache, so that they can
+// be drained when it shuts down.
+type runGroup struct {
+	// Runs in progress. First, so that it is aligned for atomic access.
+	count int64
+
+	runs       sync.WaitGroup
+	lock       sync.RWMutex
+	isStopping bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func newRunGroup() *runGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &runGroup{ctx: ctx, cancel: cancel}
+}
+
+// drain stops new runs from starting, and gives the running ones until ctx is
+// done to finish. Runs still going after that are interrupted.
+func (g *runGroup) drain(ctx context.Context) {
+	// Timers that fire from here on do nothing.
+	g.lock.Lock()
+	g.isStopping = true
+	g.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.runs.Wait()
+		close(done)
 	}()
+
+	select {
+	case <-done:
+		log.Infof("All running jobs finished.")
+	case <-ctx.Done():
+		log.Warnf("Jobs still running at shutdown deadline; interrupting them.")
+		g.cancel()
+		<-done
+	}
+}
+
+func (g *runGroup) stopping() bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.isStopping
+}
+
+func (g *runGroup) startRun() (context.Context, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if g.isStopping {
+		return nil, false
+	}
+	g.runs.Add(1)
+	atomic.AddInt64(&g.count, 1)
+	return g.ctx, true
+}
+
+func (g *runGroup) finishRun() {
+	atomic.AddInt64(&g.count, -1)
+	g.runs.Done()
+}
+
+func (g *runGroup) runsInProgress() int {
+	return int(atomic.LoadInt64(&g.count))
+}
+
+// runsPending counts the runs of backfills in progress that haven't started
+// yet. Other runs start as soon as they are due.
+func (c *LockFreeJobCache) runsPending() int {
+	c.backfillsLock.RLock()
+	defer c.backfillsLock.RUnlock()
+
+	pending := 0
+	for _, b := range c.backfills {
+		b.lock.RLock()
+		if b.Status == RunRunning {
+			pending += len(b.LogicalTimes) - len(b.Runs)
+		}
+		b.lock.RUnlock()
+	}
+	return pending
+}
+
+// schedule adds a job loaded from the db to the cache and starts its timer.
+func (c *LockFreeJobCache) schedule(j *Job) {
+	if j.Schedule == "" {
+		j.logger().Info("Job skipped.")
+		return
+	}
+	if c.TimeSet() {
+		j.SetClock(c.Time())
+	}
+	if j.ShouldStartWaiting() {
+		j.StartWaiting(c, false)
+	}
+	j.logger().Info("Job added to cache.")
+	err := c.Set(j)
+	if err != nil {
+		log.Errorln(err)
+	}
+}
+
+// Owns reports whether this node is responsible for running the job.
+// Without a Sharder every job is owned locally.
+func (c *LockFreeJobCache) Owns(id string) bool {
+	if c.Sharder == nil {
+		return true
+	}
+	return c.Sharder.Owns(id)
+}
+
+// OwnerAddress returns the API address of the node that owns the job, and
+// whether that node is this one.
+func (c *LockFreeJobCache) OwnerAddress(id string) (string, bool) {
+	if c.Sharder == nil {
+		return "", true
+	}
+	return c.Sharder.OwnerAddress(id)
+}
+
+// Owned returns the ids of the cached jobs this node is responsible for.
+func (c *LockFreeJobCache) Owned() []string {
+	ids := []string{}
+	for el := range c.jobs.Iter() {
+		id := el.Key.(string)
+		if c.Owns(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Sync brings the cache in line with the db, which other nodes in a cluster
+// also write to. Jobs created elsewhere are added and scheduled, and jobs
+// deleted elsewhere are dropped.
+func (c *LockFreeJobCache) Sync() error {
+	if err := c.loadCalendars(); err != nil {
+		return err
+	}
+
+	stored, err := c.jobDB.GetAll()
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]struct{}, len(stored))
+	for _, j := range stored {
+		ids[j.Id] = struct{}{}
+		cached, err := c.Get(j.Id)
+		if err != nil {
+			c.schedule(j)
+			continue
+		}
+		if cached == j || !cached.definitionChanged(j) {
+			continue
+		}
+		j.logger().Info("Job was changed in the db, reloading it.")
+		c.replace(cached, j)
+	}
+
+	for el := range c.jobs.Iter() {
+		id := el.Key.(string)
+		if _, ok := ids[id]; ok {
+			continue
+		}
+		log.WithField(LogFieldJobId, id).Info("Job was deleted from the db, dropping it from the cache.")
+		el.Value.(*Job).StopTimer()
+		c.jobs.Del(id)
+	}
+	return nil
+}
+
+// Reload replaces the cached copy of a job with the one in the db and
+// restarts its timer.
+func (c *LockFreeJobCache) Reload(id string) error {
+	j, err := c.jobDB.Get(id)
+	if err != nil {
+		return err
+	}
+	j.Id = id
+	if err := j.InitDelayDuration(false); err != nil {
+		return err
+	}
+
+	old, _ := c.Get(id)
+	c.replace(old, j)
+	return nil
+}
+
+// replace swaps the cached copy of a job, if any, for another copy of it.
+func (c *LockFreeJobCache) replace(old, j *Job) {
+	if old != nil {
+		old.StopTimer()
+	}
+	c.schedule(j)
+}
+
+// loadCalendars replaces the cached calendars with the ones in the db.
+func (c *LockFreeJobCache) loadCalendars() error {
+	db, ok := c.jobDB.(CalendarDB)
+	if !ok {
+		return nil
+	}
+	stored, err := db.GetAllCalendars()
+	if err != nil {
+		return err
+	}
+
+	calendars := make(map[string]*Calendar, len(stored))
+	for _, cal := range stored {
+		if err := cal.Init(); err != nil {
+			log.Errorf("Calendar %s could not be loaded: %s", cal.Name, err)
+			continue
+		}
+		calendars[cal.Name] = cal
+	}
+
+	c.calendarsLock.Lock()
+	c.calendars = calendars
+	c.calendarsLock.Unlock()
+	return nil
+}
+
+func (c *LockFreeJobCache) GetCalendar(name string) (*Calendar, error) {
+	c.calendarsLock.RLock()
+	defer c.calendarsLock.RUnlock()
+
+	cal, ok := c.calendars[name]
+	if !ok {
+		if _, ok := c.jobDB.(CalendarDB); !ok {
+			return nil, ErrCalendarsUnsupported
+		}
+		return nil, ErrCalendarDoesntExist
+	}
+	return cal, nil
+}
+
+// GetAllCalendars returns the calendars, sorted by name.
+func (c *LockFreeJobCache) GetAllCalendars() []*Calendar {
+	c.calendarsLock.RLock()
+	defer c.calendarsLock.RUnlock()
+
+	calendars := make([]*Calendar, 0, len(c.calendars))
+	for _, cal := range c.calendars {
+		calendars = append(calendars, cal)
+	}
+	sort.Slice(calendars, func(i, k int) bool {
+		return calendars[i].Name < calendars[k].Name
+	})
+	return calendars
+}
+
+// SetCalendar adds or replaces a calendar, and reschedules the jobs using it.
+func (c *LockFreeJobCache) SetCalendar(cal *Calendar) error {
+	if err := cal.Init(); err != nil {
+		return err
+	}
+	db, ok := c.jobDB.(CalendarDB)
+	if !ok {
+		return ErrCalendarsUnsupported
+	}
+	if err := db.SaveCalendar(cal); err != nil {
+		return err
+	}
+
+	c.calendarsLock.Lock()
+	c.calendars[cal.Name] = cal
+	c.calendarsLock.Unlock()
+
+	for el := range c.jobs.Iter() {
+		j := el.Value.(*Job)
+		if j.usesCalendar(cal.Name) {
+			j.reschedule(c)
+		}
+	}
+	return nil
+}
+
+// DeleteCalendar deletes a calendar that no job uses any more.
+func (c *LockFreeJobCache) DeleteCalendar(name string) error {
+	if _, err := c.GetCalendar(name); err != nil {
+		return err
+	}
+	for el := range c.jobs.Iter() {
+		if el.Value.(*Job).usesCalendar(name) {
+			return ErrCalendarInUse
+		}
+	}
+	db, ok := c.jobDB.(CalendarDB)
+	if !ok {
+		return ErrCalendarsUnsupported
+	}
+	if err := db.DeleteCalendar(name); err != nil {
+		return err
+	}
+
+	c.calendarsLock.Lock()
+	delete(c.calendars, name)
+	c.calendarsLock.Unlock()
+	return nil
+}
+
+// SupportsLeases reports whether the db can hand out run leases.
+func (c *LockFreeJobCache) SupportsLeases() bool {
+	_, ok := c.jobDB.(Leaser)
+	return ok
+}
+
+// addWorkflowRun keeps a workflow run for looking up, dropping the oldest one
+// kept if there are too many.
+func (c *LockFreeJobCache) addWorkflowRun(wf *WorkflowRun) {
+	c.workflowRunsLock.Lock()
+	defer c.workflowRunsLock.Unlock()
+
+	c.workflowRuns[wf.Id] = wf
+	c.workflowRunIds = append(c.workflowRunIds, wf.Id)
+	if len(c.workflowRunIds) > MaxWorkflowRuns {
+		delete(c.workflowRuns, c.workflowRunIds[0])
+		c.workflowRunIds = c.workflowRunIds[1:]
+	}
+}
+
+func (c *LockFreeJobCache) GetWorkflowRun(id string) (*WorkflowRun, error) {
+	c.workflowRunsLock.RLock()
+	defer c.workflowRunsLock.RUnlock()
+
+	wf, ok := c.workflowRuns[id]
+	if !ok {
+		return nil, ErrWorkflowRunDoesntExist
+	}
+	return wf, nil
+}
+
+// GetAllWorkflowRuns returns the workflow runs kept, newest first.
+func (c *LockFreeJobCache) GetAllWorkflowRuns() []*WorkflowRun {
+	c.workflowRunsLock.RLock()
+	runs := make([]*WorkflowRun, 0, len(c.workflowRuns))
+	for _, wf := range c.workflowRuns {
+		runs = append(runs, wf)
+	}
+	c.workflowRunsLock.RUnlock()
+
+	sortWorkflowRuns(runs)
+	return runs
+}
+
+// addJobRun keeps a started run for looking up, dropping the oldest one kept if
+// there are too many.
+func (c *LockFreeJobCache) addJobRun(r *JobRun) {
+	c.jobRunsLock.Lock()
+	defer c.jobRunsLock.Unlock()
+
+	c.jobRuns[r.Id] = r
+	c.jobRunIds = append(c.jobRunIds, r.Id)
+	if len(c.jobRunIds) > MaxJobRuns {
+		delete(c.jobRuns, c.jobRunIds[0])
+		c.jobRunIds = c.jobRunIds[1:]
+	}
+}
+
+func (c *LockFreeJobCache) getJobRun(id string) (*JobRun, bool) {
+	c.jobRunsLock.RLock()
+	defer c.jobRunsLock.RUnlock()
+
+	r, ok := c.jobRuns[id]
+	return r, ok
+}
+
+// addBackfill keeps a backfill for looking up, dropping the oldest one kept if
+// there are too many.
+func (c *LockFreeJobCache) addBackfill(b *Backfill) {
+	c.backfillsLock.Lock()
+	defer c.backfillsLock.Unlock()
+
+	c.backfills[b.Id] = b
+	c.backfillIds = append(c.backfillIds, b.Id)
+	if len(c.backfillIds) > MaxBackfills {
+		delete(c.backfills, c.backfillIds[0])
+		c.backfillIds = c.backfillIds[1:]
+	}
+}
+
+func (c *LockFreeJobCache) getBackfill(id string) (*Backfill, bool) {
+	c.backfillsLock.RLock()
+	defer c.backfillsLock.RUnlock()
+
+	b, ok := c.backfills[id]
+	return b, ok
+}
+
+// AcquireLease takes an exclusive lease from the db.
+func (c *LockFreeJobCache) AcquireLease(key string, ttl time.Duration) (bool, error) {
+	l, ok := c.jobDB.(Leaser)
+	if !ok {
+		return false, ErrLeasesUnsupported
+	}
+	return l.AcquireLease(key, ttl)
 }
 
 func (c *LockFreeJobCache) Get(id string) (*Job, error) {
@@ -337,7 +849,9 @@ func (c *LockFreeJobCache) Delete(id string) error {
 	go func() {
 		log.Errorln(j.DeleteFromDependentJobs(c)) // todo: review
 	}()
-	log.Infof("Deleting %s", id)
+	j.logger().Info("Job deleted.")
+	forgetJobMetrics(j)
+	forgetJob(c, id)
 	c.jobs.Del(id)
 	return err
 }
@@ -355,6 +869,10 @@ func (c *LockFreeJobCache) Disable(j *Job) error {
 func (c *LockFreeJobCache) Persist() error {
 	jm := c.GetAll()
 	for _, j := range jm.Jobs {
+		// Only the owner has up to date run metadata for a job.
+		if !c.Owns(j.Id) {
+			continue
+		}
 		j.lock.RLock()
 		err := c.jobDB.Save(j)
 		if err != nil {
@@ -378,37 +896,62 @@ func (c *LockFreeJobCache) PersistEvery(persistWaitTime time.Duration) {
 	}
 }
 
-func (c *LockFreeJobCache) locateJobStatsIndexForRetention(stats []*JobStat) (marker int) {
-	now := time.Now()
-	expiresAt := now.Add(-c.retentionPeriod)
-	pos := -1
-	for i, el := range stats {
-		diff := el.RanAt.Sub(expiresAt)
-		if diff < 0 {
-			pos = i
+// Retain removes the stats that retention doesn't keep, and saves the jobs
+// owned by this node that had any removed to the db, which is then compacted
+// if it can be.
+func (c *LockFreeJobCache) Retain() error {
+	now := c.Time().Now()
+	failed := []error{}
+	for el := range c.jobs.Iter() {
+		j := el.Value.(*Job)
+		db := c.jobDB
+		// Only the owner has up to date stats for a job.
+		if !c.Owns(j.Id) {
+			db = nil
 		}
+		if err := retainJobStats(j, c.DefaultRetention, now, db); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if err := compactDB(c.jobDB, c.DefaultRetention, now); err != nil {
+		failed = append(failed, err)
 	}
-	return pos
+	return retentionError(failed)
 }
 
-func (c *LockFreeJobCache) Retain() error {
-	for el := range c.jobs.Iter() {
-		job := el.Value.(*Job)
-		c.compactJobStats(job)
+func (c *LockFreeJobCache) defaultRetention() *Retention {
+	return c.DefaultRetention
+}
+
+func (c *LockFreeJobCache) notifiers() []Notifier {
+	return c.Notifiers
+}
+
+// retentionError returns the error for a retention sweep that had the given
+// errors, saving jobs or compacting the db, or nil if there were none. It
+// wraps the first of them.
+func retentionError(failed []error) error {
+	if len(failed) == 0 {
+		return nil
 	}
-	return nil
+	return fmt.Errorf("Stats retention had %d error(s), the first: %w", len(failed), failed[0])
 }
 
-func (c *LockFreeJobCache) compactJobStats(job *Job) {
-	job.lock.Lock()
-	defer job.lock.Unlock()
-	pos := c.locateJobStatsIndexForRetention(job.Stats)
-	if pos >= 0 {
-		log.Infof("JobStats TTL: removing %d items", pos+1)
-		tmp := make([]*JobStat, len(job.Stats)-pos-1)
-		copy(tmp, job.Stats[pos+1:])
-		job.Stats = tmp
+// retainJobStats applies retention to the job's stats, and saves the job to
+// db, if it isn't nil, when any were removed, so that they are removed from
+// the db too.
+func retainJobStats(j *Job, defaults *Retention, now time.Time, db JobDB) error {
+	if !j.RetainStats(defaults, now) || db == nil {
+		return nil
 	}
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if err := db.Save(j); err != nil {
+		j.logger().WithError(err).Error("Job's stats were removed, but it couldn't be saved.")
+		return err
+	}
+	return nil
 }
 
 func (c *LockFreeJobCache) RetainEvery(retentionWaitTime time.Duration) {
@@ -1,6 +1,7 @@
 package job
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -118,3 +119,305 @@ func TestCacheStartCanResumeJobAtNextScheduledPoint(t *testing.T) {
 	j.lock.RUnlock()
 
 }
+
+func startWithMissedRuns(t *testing.T, configure func(j *Job)) *Job {
+	t.Helper()
+
+	cache := NewMockCache()
+	mockDb := &MockDBGetAll{}
+	cache.jobDB = mockDb
+
+	// Last ran five hours ago, so five hourly runs were missed.
+	now := time.Now()
+	j := GetMockRecurringJobWithSchedule(now.Add(-10*time.Hour-30*time.Minute), "PT1H")
+	j.Id = "0"
+	j.Metadata.LastAttemptedRun = now.Add(-5*time.Hour - 30*time.Minute)
+	configure(j)
+	assert.NoError(t, j.InitDelayDuration(false))
+	mockDb.response = []*Job{j}
+
+	cache.Start(0, -1)
+	time.Sleep(time.Second)
+	return j
+}
+
+func TestCacheStartCatchesUpWithMissedRuns(t *testing.T) {
+	j := startWithMissedRuns(t, func(j *Job) {
+		j.MisfirePolicy = MisfireRunAll
+		j.MisfireLimit = 3
+	})
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, 3, int(j.Metadata.SuccessCount))
+	for _, stat := range j.Stats {
+		assert.True(t, stat.CatchUp)
+	}
+	assert.True(t, j.NextRunAt.After(time.Now().Add(50*time.Minute)))
+}
+
+func TestCacheStartRunsOnceForMissedRuns(t *testing.T) {
+	j := startWithMissedRuns(t, func(j *Job) {
+		j.MisfirePolicy = MisfireRunOnce
+	})
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, 1, int(j.Metadata.SuccessCount))
+	assert.True(t, j.Stats[0].CatchUp)
+}
+
+func TestCacheStartSkipsMissedRuns(t *testing.T) {
+	j := startWithMissedRuns(t, func(j *Job) {
+		j.MisfirePolicy = MisfireSkip
+	})
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, 0, int(j.Metadata.SuccessCount))
+	// Waits for the next hourly run point, half an hour away.
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), j.NextRunAt, time.Minute)
+}
+
+func TestCacheStartSkipsMissedRunsPastThreshold(t *testing.T) {
+	j := startWithMissedRuns(t, func(j *Job) {
+		j.MisfirePolicy = MisfireRunAll
+		j.MisfireThreshold = "PT2H"
+	})
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, 2, int(j.Metadata.SuccessCount))
+}
+
+func TestCacheStartSkipsWhenOnlyRunIsPastThreshold(t *testing.T) {
+	j := startWithMissedRuns(t, func(j *Job) {
+		j.MisfireThreshold = "PT10M"
+	})
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, 0, int(j.Metadata.SuccessCount))
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), j.NextRunAt, time.Minute)
+}
+
+type mockSharder struct {
+	owned map[string]bool
+}
+
+func (s *mockSharder) Owns(id string) bool {
+	return s.owned[id]
+}
+
+func (s *mockSharder) OwnerAddress(id string) (string, bool) {
+	return "elsewhere:8000", s.owned[id]
+}
+
+func TestCacheSyncAddsAndDropsJobs(t *testing.T) {
+	db := NewMemoryDB()
+	cache := NewLockFreeJobCache(db)
+
+	kept := GetMockJobWithGenericSchedule(time.Now())
+	kept.Id = "kept"
+	assert.NoError(t, kept.InitDelayDuration(false))
+	deleted := GetMockJobWithGenericSchedule(time.Now())
+	deleted.Id = "deleted"
+	assert.NoError(t, deleted.InitDelayDuration(false))
+	assert.NoError(t, db.Save(kept))
+	assert.NoError(t, cache.Set(kept))
+	assert.NoError(t, cache.Set(deleted))
+
+	// Created by another node.
+	added := GetMockJobWithGenericSchedule(time.Now())
+	added.Id = "added"
+	assert.NoError(t, added.InitDelayDuration(false))
+	assert.NoError(t, db.Save(added))
+
+	assert.NoError(t, cache.Sync())
+
+	_, err := cache.Get("kept")
+	assert.NoError(t, err)
+	_, err = cache.Get("added")
+	assert.NoError(t, err)
+	_, err = cache.Get("deleted")
+	assert.Equal(t, ErrJobDoesntExist, err)
+}
+
+func TestCacheSyncReloadsChangedJobs(t *testing.T) {
+	db := NewMemoryDB()
+	cache := NewLockFreeJobCache(db)
+	scheduleTime := time.Now().Add(time.Hour)
+
+	for _, id := range []string{"edited", "ran"} {
+		j := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+		j.Id = id
+		j.DependentJobs = []string{}
+		assert.NoError(t, j.InitDelayDuration(false))
+		assert.NoError(t, cache.Set(j))
+		defer j.StopTimer()
+	}
+
+	// Edited through another node.
+	edited := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+	edited.Id = "edited"
+	edited.Command = "bash -c 'echo edited'"
+	assert.NoError(t, edited.InitDelayDuration(false))
+	assert.NoError(t, db.Save(edited))
+
+	// Only its state differs, as loaded from the db.
+	ran := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+	ran.Id = "ran"
+	ran.Metadata.SuccessCount = 3
+	ran.Stats = []*JobStat{{JobId: "ran"}}
+	assert.NoError(t, ran.InitDelayDuration(false))
+	assert.NoError(t, db.Save(ran))
+
+	assert.NoError(t, cache.Sync())
+
+	j, err := cache.Get("edited")
+	assert.NoError(t, err)
+	assert.Same(t, edited, j)
+	defer edited.StopTimer()
+	j, err = cache.Get("ran")
+	assert.NoError(t, err)
+	assert.NotSame(t, ran, j)
+}
+
+func TestCacheSyncReloadsDisabledJobs(t *testing.T) {
+	db := NewMemoryDB()
+	cache := NewLockFreeJobCache(db)
+	scheduleTime := time.Now().Add(time.Hour)
+
+	j := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+	j.Id = "disabled"
+	assert.NoError(t, j.InitDelayDuration(false))
+	assert.NoError(t, cache.Set(j))
+	defer j.StopTimer()
+
+	// Disabled, and then enabled again, through another node.
+	for _, disabled := range []bool{true, false} {
+		stored := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+		stored.Id = "disabled"
+		stored.Disabled = disabled
+		assert.NoError(t, stored.InitDelayDuration(false))
+		assert.NoError(t, db.Save(stored))
+
+		assert.NoError(t, cache.Sync())
+
+		cached, err := cache.Get("disabled")
+		assert.NoError(t, err)
+		assert.Same(t, stored, cached)
+		defer stored.StopTimer()
+		stored.lock.RLock()
+		assert.Equal(t, disabled, stored.Disabled)
+		stored.lock.RUnlock()
+	}
+}
+
+func TestCacheOnlyRunsOwnedJobs(t *testing.T) {
+	cache := NewLockFreeJobCache(NewMemoryDB())
+	cache.Sharder = &mockSharder{owned: map[string]bool{}}
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Second), "PT1S")
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+
+	time.Sleep(time.Second * 2)
+
+	j.lock.RLock()
+	assert.Equal(t, 0, int(j.Metadata.SuccessCount))
+	assert.False(t, j.Metadata.LastAttemptedRun.IsZero())
+	j.lock.RUnlock()
+	assert.Empty(t, cache.Owned())
+}
+
+func TestCacheShutdownWaitsForRunningJobs(t *testing.T) {
+	cache := NewLockFreeJobCache(NewMemoryDB())
+
+	j := GetMockJob()
+	j.Command = "sleep 1"
+	j.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, j.Init(cache))
+
+	go j.Run(cache)
+	briefPause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, cache.Shutdown(ctx))
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Len(t, j.Stats, 1)
+	assert.True(t, j.Stats[0].Success)
+	assert.False(t, j.Stats[0].Interrupted)
+}
+
+func TestMemoryCacheShutdownWaitsForRunningJobs(t *testing.T) {
+	db := NewMemoryDB()
+	cache := NewMemoryJobCache(db)
+
+	j := GetMockJob()
+	j.Command = "sleep 1"
+	j.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, j.Init(cache))
+
+	go j.Run(cache)
+	briefPause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, cache.Shutdown(ctx))
+
+	stored, err := db.Get(j.Id)
+	assert.NoError(t, err)
+	if assert.Len(t, stored.Stats, 1) {
+		assert.True(t, stored.Stats[0].Success)
+	}
+
+	// No new runs are started once shut down.
+	j.Run(cache)
+	j.lock.RLock()
+	assert.Len(t, j.Stats, 1)
+	j.lock.RUnlock()
+}
+
+func TestCacheShutdownInterruptsJobsPastDeadline(t *testing.T) {
+	cache := NewLockFreeJobCache(NewMemoryDB())
+
+	onFailure := GetMockJob()
+	onFailure.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, onFailure.Init(cache))
+
+	j := GetMockJob()
+	j.Command = "sleep 10"
+	j.Schedule = onFailure.Schedule
+	j.OnFailureJob = onFailure.Id
+	assert.NoError(t, j.Init(cache))
+
+	go j.Run(cache)
+	briefPause()
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, cache.Shutdown(ctx))
+	assert.WithinDuration(t, start, time.Now(), 2*time.Second)
+
+	j.lock.RLock()
+	assert.Len(t, j.Stats, 1)
+	assert.False(t, j.Stats[0].Success)
+	assert.True(t, j.Stats[0].Interrupted)
+	assert.Equal(t, uint(0), j.Stats[0].NumberOfRetries)
+	j.lock.RUnlock()
+
+	onFailure.lock.RLock()
+	assert.Empty(t, onFailure.Stats)
+	onFailure.lock.RUnlock()
+
+	// No new runs are started once shut down.
+	j.Run(cache)
+	j.lock.RLock()
+	assert.Len(t, j.Stats, 1)
+	j.lock.RUnlock()
+}
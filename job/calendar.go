@@ -0,0 +1,322 @@
+package job
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ajvb/kala/utils/iso8601"
+)
+
+const (
+	calendarDateLayout = "2006-01-02"
+	calendarTimeLayout = "15:04"
+
+	// The most run points skipped in one go before giving up, in case a
+	// calendar excludes (nearly) everything.
+	maxCalendarSkips = 1000
+
+	// MaxSkippedRuns is how many skipped runs are kept on a job.
+	MaxSkippedRuns = 100
+)
+
+var (
+	ErrCalendarDoesntExist  = errors.New("The calendar you requested does not exist")
+	ErrCalendarInUse        = errors.New("The calendar is still used by a job")
+	ErrCalendarsUnsupported = errors.New("The job database does not support calendars")
+	ErrInvalidCalendar      = errors.New("Invalid Calendar. Calendars must have a name")
+)
+
+// Calendar is a named set of periods in which the jobs that use it must not
+// run, such as maintenance windows and holidays.
+type Calendar struct {
+	Name string `json:"name"`
+
+	// IANA time zone that Dates and Weekly are in, e.g. "Europe/Paris".
+	// Defaults to UTC.
+	Location string `json:"location"`
+
+	// Whole days excluded, formatted as 2006-01-02.
+	Dates []string `json:"dates"`
+
+	// Windows excluded every week.
+	Weekly []WeeklyWindow `json:"weekly"`
+
+	// One-off periods excluded, e.g. imported from an iCalendar file.
+	Periods []Period `json:"periods"`
+
+	loc   *time.Location
+	dates map[string]struct{}
+}
+
+// WeeklyWindow is a window of time on a given day of the week. A window that
+// ends before it starts runs past midnight into the next day.
+type WeeklyWindow struct {
+	// Day of the week, e.g. "saturday".
+	Day string `json:"day"`
+	// Start and end times of the window, e.g. "22:00".
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	day           time.Weekday
+	start, end    time.Duration
+	wrapsMidnight bool
+}
+
+// Period is a span of time, including Start but not End.
+type Period struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Summary string    `json:"summary,omitempty"`
+}
+
+// SkippedRun is a scheduled run that didn't happen because it fell in a
+// period excluded by one of the job's calendars.
+type SkippedRun struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Calendar    string    `json:"calendar"`
+}
+
+// CalendarCache is implemented by job caches that hold calendars.
+type CalendarCache interface {
+	GetCalendar(name string) (*Calendar, error)
+	GetAllCalendars() []*Calendar
+	SetCalendar(c *Calendar) error
+	DeleteCalendar(name string) error
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Init validates the calendar and parses its fields. It needs to be called
+// before the calendar is used.
+func (c *Calendar) Init() error {
+	if c.Name == "" || strings.Contains(c.Name, "/") {
+		return ErrInvalidCalendar
+	}
+
+	var err error
+	c.loc = time.UTC
+	if c.Location != "" {
+		c.loc, err = time.LoadLocation(c.Location)
+		if err != nil {
+			return fmt.Errorf("Calendar %s has an unknown location: %w", c.Name, err)
+		}
+	}
+
+	c.dates = make(map[string]struct{}, len(c.Dates))
+	for _, d := range c.Dates {
+		if _, err := time.Parse(calendarDateLayout, d); err != nil {
+			return fmt.Errorf("Calendar %s has a date not formatted like 2006-01-02: %s", c.Name, d)
+		}
+		c.dates[d] = struct{}{}
+	}
+
+	for i := range c.Weekly {
+		if err := c.Weekly[i].init(); err != nil {
+			return fmt.Errorf("Calendar %s: %w", c.Name, err)
+		}
+	}
+
+	for _, p := range c.Periods {
+		if !p.End.After(p.Start) {
+			return fmt.Errorf("Calendar %s has a period that doesn't end after it starts: %s", c.Name, p.Start)
+		}
+	}
+	return nil
+}
+
+func (w *WeeklyWindow) init() error {
+	var ok bool
+	w.day, ok = weekdays[strings.ToLower(w.Day)]
+	if !ok {
+		return fmt.Errorf("Weekly window has an unknown day: %s", w.Day)
+	}
+
+	start, err := time.Parse(calendarTimeLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("Weekly window start not formatted like 15:04: %s", w.Start)
+	}
+	end, err := time.Parse(calendarTimeLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("Weekly window end not formatted like 15:04: %s", w.End)
+	}
+	w.start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	w.end = time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+	w.wrapsMidnight = w.end <= w.start
+	return nil
+}
+
+// Excludes reports whether t falls in a period excluded by the calendar and,
+// if so, when that period ends.
+func (c *Calendar) Excludes(t time.Time) (bool, time.Time) {
+	local := t.In(c.loc)
+	year, month, day := local.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, c.loc)
+
+	if _, ok := c.dates[local.Format(calendarDateLayout)]; ok {
+		return true, time.Date(year, month, day+1, 0, 0, 0, 0, c.loc)
+	}
+
+	for _, w := range c.Weekly {
+		// The window may have started today, or yesterday if it wraps midnight.
+		for _, start := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+			if start.Weekday() != w.day {
+				continue
+			}
+			from := start.Add(w.start)
+			until := start.Add(w.end)
+			if w.wrapsMidnight {
+				until = start.AddDate(0, 0, 1).Add(w.end)
+			}
+			if !t.Before(from) && t.Before(until) {
+				return true, until
+			}
+		}
+	}
+
+	for _, p := range c.Periods {
+		if !t.Before(p.Start) && t.Before(p.End) {
+			return true, p.End
+		}
+	}
+	return false, time.Time{}
+}
+
+// ImportICalendar replaces the calendar's periods with the events read from
+// an iCalendar file.
+func (c *Calendar) ImportICalendar(r io.Reader) error {
+	loc := time.UTC
+	if c.Location != "" {
+		var err error
+		loc, err = time.LoadLocation(c.Location)
+		if err != nil {
+			return fmt.Errorf("Calendar %s has an unknown location: %w", c.Name, err)
+		}
+	}
+
+	periods, err := ParseICalendar(r, loc)
+	if err != nil {
+		return err
+	}
+	c.Periods = periods
+	return c.Init()
+}
+
+// ParseICalendar reads the events of an iCalendar (RFC 5545) file as periods.
+// All-day events, and times without a time zone, are taken to be in loc.
+// Recurrence rules are not expanded; only the first occurrence of a recurring
+// event is read.
+func ParseICalendar(r io.Reader, loc *time.Location) ([]Period, error) {
+	lines, err := unfoldICalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := []Period{}
+	var (
+		inEvent  bool
+		period   Period
+		duration *iso8601.Duration
+		allDay   bool
+	)
+	for _, line := range lines {
+		name, params, value := splitICalendarLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, period, duration, allDay = true, Period{}, nil, false
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			if period.Start.IsZero() {
+				return nil, errors.New("iCalendar event without a DTSTART")
+			}
+			if period.End.IsZero() {
+				switch {
+				case duration != nil:
+					period.End = duration.Add(period.Start)
+				case allDay:
+					period.End = period.Start.AddDate(0, 0, 1)
+				default:
+					// An event without an end is only an instant; nothing to exclude.
+					continue
+				}
+			}
+			periods = append(periods, period)
+		case !inEvent:
+			// Only events are read.
+		case name == "DTSTART":
+			period.Start, allDay, err = parseICalendarTime(params, value, loc)
+		case name == "DTEND":
+			period.End, _, err = parseICalendarTime(params, value, loc)
+		case name == "DURATION":
+			duration, err = iso8601.FromString(value)
+		case name == "SUMMARY":
+			period.Summary = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing iCalendar %s: %w", name, err)
+		}
+	}
+	return periods, nil
+}
+
+// unfoldICalendar joins continuation lines, which start with a space or tab.
+func unfoldICalendar(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICalendarLine splits a content line like "DTSTART;TZID=Europe/Paris:20240101T090000".
+func splitICalendarLine(line string) (string, map[string]string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	parts := strings.Split(line[:colon], ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+func parseICalendarTime(params map[string]string, value string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	if tzid, ok := params["TZID"]; ok {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
@@ -0,0 +1,192 @@
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mixer/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarExcludesDates(t *testing.T) {
+	c := &Calendar{Name: "holidays", Location: "Europe/Paris", Dates: []string{"2026-12-25"}}
+	assert.NoError(t, c.Init())
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	excluded, until := c.Excludes(time.Date(2026, 12, 25, 9, 0, 0, 0, paris))
+	assert.True(t, excluded)
+	assert.Equal(t, time.Date(2026, 12, 26, 0, 0, 0, 0, paris), until)
+
+	// 23:30 UTC on the 24th is already Christmas in Paris.
+	excluded, _ = c.Excludes(time.Date(2026, 12, 24, 23, 30, 0, 0, time.UTC))
+	assert.True(t, excluded)
+
+	excluded, _ = c.Excludes(time.Date(2026, 12, 26, 9, 0, 0, 0, paris))
+	assert.False(t, excluded)
+}
+
+func TestCalendarExcludesWeeklyWindows(t *testing.T) {
+	c := &Calendar{Name: "maintenance", Weekly: []WeeklyWindow{
+		{Day: "Saturday", Start: "22:00", End: "02:00"},
+		{Day: "wednesday", Start: "12:00", End: "13:00"},
+	}}
+	assert.NoError(t, c.Init())
+
+	// Saturday 2026-10-24.
+	excluded, until := c.Excludes(time.Date(2026, 10, 24, 23, 0, 0, 0, time.UTC))
+	assert.True(t, excluded)
+	assert.Equal(t, time.Date(2026, 10, 25, 2, 0, 0, 0, time.UTC), until)
+
+	excluded, until = c.Excludes(time.Date(2026, 10, 25, 1, 0, 0, 0, time.UTC))
+	assert.True(t, excluded)
+	assert.Equal(t, time.Date(2026, 10, 25, 2, 0, 0, 0, time.UTC), until)
+
+	excluded, _ = c.Excludes(time.Date(2026, 10, 25, 2, 0, 0, 0, time.UTC))
+	assert.False(t, excluded)
+
+	excluded, _ = c.Excludes(time.Date(2026, 10, 21, 12, 30, 0, 0, time.UTC))
+	assert.True(t, excluded)
+	excluded, _ = c.Excludes(time.Date(2026, 10, 22, 12, 30, 0, 0, time.UTC))
+	assert.False(t, excluded)
+}
+
+func TestCalendarExcludesPeriods(t *testing.T) {
+	start := time.Date(2026, 11, 2, 8, 0, 0, 0, time.UTC)
+	c := &Calendar{Name: "freeze", Periods: []Period{{Start: start, End: start.Add(time.Hour)}}}
+	assert.NoError(t, c.Init())
+
+	excluded, until := c.Excludes(start)
+	assert.True(t, excluded)
+	assert.Equal(t, start.Add(time.Hour), until)
+
+	excluded, _ = c.Excludes(start.Add(time.Hour))
+	assert.False(t, excluded)
+}
+
+func TestCalendarInitRejectsBadInput(t *testing.T) {
+	calendars := []*Calendar{
+		{},
+		{Name: "a/b"},
+		{Name: "x", Location: "Nowhere/Special"},
+		{Name: "x", Dates: []string{"25/12/2026"}},
+		{Name: "x", Weekly: []WeeklyWindow{{Day: "someday", Start: "10:00", End: "11:00"}}},
+		{Name: "x", Weekly: []WeeklyWindow{{Day: "monday", Start: "10am", End: "11:00"}}},
+		{Name: "x", Periods: []Period{{Start: time.Now(), End: time.Now().Add(-time.Hour)}}},
+	}
+	for _, c := range calendars {
+		assert.Error(t, c.Init(), "calendar %+v", c)
+	}
+}
+
+func TestParseICalendar(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261225",
+		"SUMMARY:Christmas",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20261102T080000Z",
+		"DTEND:20261102T090000Z",
+		"SUMMARY:Release",
+		"  freeze",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=America/New_York:20261103T090000",
+		"DURATION:PT30M",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	periods, err := ParseICalendar(strings.NewReader(ics), time.UTC)
+	assert.NoError(t, err)
+	if assert.Len(t, periods, 3) {
+		assert.Equal(t, Period{
+			Start:   time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC),
+			Summary: "Christmas",
+		}, periods[0])
+		assert.Equal(t, "Release freeze", periods[1].Summary)
+		assert.True(t, periods[1].End.Equal(time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)))
+		assert.True(t, periods[2].Start.Equal(time.Date(2026, 11, 3, 14, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 30*time.Minute, periods[2].End.Sub(periods[2].Start))
+	}
+
+	_, err = ParseICalendar(strings.NewReader("BEGIN:VEVENT\r\nDTSTART:tomorrow\r\nEND:VEVENT"), time.UTC)
+	assert.Error(t, err)
+}
+
+func newCalendarCache(t *testing.T, calendars ...*Calendar) *LockFreeJobCache {
+	t.Helper()
+	cache := NewLockFreeJobCache(NewMemoryDB())
+	cache.Clock.SetClock(clock.NewMockClock(time.Now()))
+	for _, c := range calendars {
+		assert.NoError(t, cache.SetCalendar(c))
+	}
+	return cache
+}
+
+func TestJobInitRequiresCalendarsToExist(t *testing.T) {
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Calendars = []string{"holidays"}
+	assert.ErrorIs(t, j.Init(NewMockCache()), ErrCalendarsUnsupported)
+
+	j = GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Calendars = []string{"holidays"}
+	assert.ErrorIs(t, j.Init(newCalendarCache(t)), ErrCalendarDoesntExist)
+}
+
+func TestStartWaitingSkipsExcludedRuns(t *testing.T) {
+	next := time.Now().Add(time.Hour)
+	cache := newCalendarCache(t, &Calendar{
+		Name:    "freeze",
+		Periods: []Period{{Start: next.Add(-time.Minute), End: next.Add(90 * time.Minute)}},
+	})
+
+	j := GetMockRecurringJobWithSchedule(next, "PT1H")
+	j.Calendars = []string{"freeze"}
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	// The runs in one and two hours are skipped.
+	assert.WithinDuration(t, j.scheduleTime.Add(2*time.Hour), j.NextRunAt, time.Second)
+	if assert.Len(t, j.SkippedRuns, 2) {
+		assert.Equal(t, "freeze", j.SkippedRuns[0].Calendar)
+		assert.WithinDuration(t, j.scheduleTime, j.SkippedRuns[0].ScheduledAt, time.Second)
+	}
+}
+
+func TestSetCalendarReschedulesJobs(t *testing.T) {
+	cache := newCalendarCache(t, &Calendar{Name: "freeze"})
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Calendars = []string{"freeze"}
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	next := j.NextRunAt
+	assert.NoError(t, cache.SetCalendar(&Calendar{
+		Name:    "freeze",
+		Periods: []Period{{Start: next.Add(-time.Minute), End: next.Add(time.Minute)}},
+	}))
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.WithinDuration(t, next.Add(time.Hour), j.NextRunAt, time.Second)
+}
+
+func TestDeleteCalendar(t *testing.T) {
+	cache := newCalendarCache(t, &Calendar{Name: "freeze"}, &Calendar{Name: "unused"})
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Calendars = []string{"freeze"}
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	assert.ErrorIs(t, cache.DeleteCalendar("freeze"), ErrCalendarInUse)
+	assert.NoError(t, cache.DeleteCalendar("unused"))
+	assert.ErrorIs(t, cache.DeleteCalendar("unused"), ErrCalendarDoesntExist)
+	assert.Len(t, cache.GetAllCalendars(), 1)
+}
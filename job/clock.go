@@ -2,6 +2,7 @@ package job
 
 import (
 	"sync"
+	"time"
 
 	// This library abstracts the time functionality of the OS so that it can be controlled during unit tests.
 	// It was selected over thejerf/abtime because abtime is geared towards precision timing rather than scheduling.
@@ -27,7 +28,7 @@ func (clk *Clock) Time() clock.Clock {
 	if clk.Clock == nil {
 		clk.lock.RUnlock()
 		clk.lock.Lock()
-		clk.Clock = clock.C
+		clk.Clock = systemClock{}
 		clk.lock.Unlock()
 		clk.lock.RLock()
 	}
@@ -46,3 +47,34 @@ type Clocker interface {
 	Time() clock.Clock
 	TimeSet() bool
 }
+
+// systemClock is clock.C, except that its timers and tickers hold on to the
+// ones from the time package rather than copying them, which the runtime
+// doesn't allow: stopping a copied timer corrupts memory or hangs.
+type systemClock struct {
+	clock.DefaultClock
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return systemTimer{time.AfterFunc(d, f)}
+}
+
+func (systemClock) NewTimer(d time.Duration) clock.Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) clock.Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) Chan() <-chan time.Time {
+	return t.C
+}
+
+type systemTicker struct{ *time.Ticker }
+
+func (t systemTicker) Chan() <-chan time.Time {
+	return t.C
+}
@@ -2,9 +2,11 @@ package job
 
 import (
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/mixer/clock"
+	"github.com/stretchr/testify/assert"
 )
 
 // Special hybrid clock that allows you to make time "play" in addition to moving it around manually.
@@ -62,3 +64,31 @@ func (hc *HybridClock) SetTime(t time.Time) {
 		hc.Play()
 	}
 }
+
+func TestDefaultClockTimers(t *testing.T) {
+	clk := (&Clock{}).Time()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fired := make(chan struct{}, 1)
+		timer := clk.AfterFunc(time.Hour, func() { fired <- struct{}{} })
+		assert.True(t, timer.Stop())
+		timer.Reset(time.Millisecond)
+		<-fired
+
+		timer = clk.NewTimer(time.Hour)
+		assert.True(t, timer.Stop())
+		timer.Reset(time.Millisecond)
+		<-timer.Chan()
+
+		ticker := clk.NewTicker(time.Millisecond)
+		<-ticker.Chan()
+		<-ticker.Chan()
+		ticker.Stop()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The timers of the default clock hung")
+	}
+}
@@ -1,7 +1,9 @@
 package job
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -13,6 +15,14 @@ func (id ErrJobNotFound) Error() string {
 	return fmt.Sprintf("Job with id of %s not found.", string(id))
 }
 
+var (
+	ErrLeasesUnsupported = errors.New("The job database does not support run leases, which at_most_once requires")
+
+	// RunLeaseTTL is how long a run lease is held for. It only needs to outlast
+	// the window in which another node could attempt the same scheduled run.
+	RunLeaseTTL = time.Hour
+)
+
 type JobDB interface {
 	GetAll() ([]*Job, error)
 	Get(id string) (*Job, error)
@@ -21,6 +31,28 @@ type JobDB interface {
 	Close() error
 }
 
+// Leaser is implemented by JobDBs that can hand out exclusive, expiring leases.
+type Leaser interface {
+	// AcquireLease takes the lease for key if nobody holds it, returning
+	// false if it is already held.
+	AcquireLease(key string, ttl time.Duration) (bool, error)
+}
+
+// Compactor is implemented by JobDBs that can apply retention to the jobs they
+// store, and give back the space of the data removed from them.
+type Compactor interface {
+	// Compact removes the stats that retention doesn't keep from the stored
+	// jobs, with defaults as the policy of jobs that have none of their own.
+	Compact(defaults *Retention, now time.Time) error
+}
+
+// CalendarDB is implemented by JobDBs that can store calendars.
+type CalendarDB interface {
+	GetAllCalendars() ([]*Calendar, error)
+	SaveCalendar(c *Calendar) error
+	DeleteCalendar(name string) error
+}
+
 func (j *Job) Delete(cache JobCache) error {
 	var err error
 	errOne := cache.Delete(j.Id)
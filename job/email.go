@@ -0,0 +1,244 @@
+package job
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrInvalidEmailTemplate = errors.New("Invalid email template. It needs to define a \"subject\" and a \"body\" template")
+
+// Emails about failures show at most this many of the last lines of output.
+const emailOutputLines = 20
+
+// DefaultEmailTemplate is the template of the emails sent to owners, unless
+// the notifier has one of its own.
+const DefaultEmailTemplate = `{{define "subject"}}[kala] Job {{.Event.JobName}} {{if .Recovery}}recovered{{else}}{{.Event.Status}}{{end}}{{end}}
+{{- define "body"}}
+{{- if .Recovery -}}
+Job {{.Event.JobName}} ({{.Event.JobId}}) succeeded at {{.Event.Time.Format "2006-01-02 15:04:05 MST"}}, after failing.
+{{- else -}}
+Job {{.Event.JobName}} ({{.Event.JobId}}) {{.Event.Status}} at {{.Event.Time.Format "2006-01-02 15:04:05 MST"}}, after {{.Event.Attempts}} attempt(s).
+
+Run: {{.Event.RunId}}
+Error: {{.Event.Error}}
+{{- if .Output}}
+
+Output (last lines):
+{{.Output}}
+{{- end}}
+{{- if .Suppressed}}
+
+{{.Suppressed}} more failure(s) of the job weren't emailed, as at most one email is sent every {{.MinInterval}}.
+{{- end}}
+{{- end}}
+{{end}}`
+
+// ParseEmailTemplate parses the template of the emails sent by an
+// EmailNotifier, which must define a "subject" and a "body" template. They are
+// executed with an EmailData.
+func ParseEmailTemplate(text string) (*template.Template, error) {
+	t, err := template.New("email").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if t.Lookup("subject") == nil || t.Lookup("body") == nil {
+		return nil, ErrInvalidEmailTemplate
+	}
+	return t, nil
+}
+
+var defaultEmailTemplate = template.Must(ParseEmailTemplate(DefaultEmailTemplate))
+
+// EmailData is what the templates of emails are executed with.
+type EmailData struct {
+	Event *Event
+	// Set when the email is about a job that succeeded after failing.
+	Recovery bool
+	// The last lines of what the last attempt output.
+	Output string
+	// Failures that weren't emailed since the last email, as it was too soon.
+	Suppressed int
+	// How long the notifier waits between emails about a job.
+	MinInterval time.Duration
+}
+
+// EmailNotifier emails the owners of jobs when their runs fail, and optionally
+// when they succeed again after failing. Jobs whose owner isn't an email
+// address are left out.
+type EmailNotifier struct {
+	// Address of the SMTP server, in "host:port" format.
+	Addr string
+	// Credentials for the server, if it needs them.
+	Username, Password string
+	// Sender of the emails, which can have a name, e.g.
+	// "Kala <kala@example.com>".
+	From string
+
+	// Also email owners when a job succeeds after a failure they were emailed
+	// about.
+	NotifyRecovery bool
+
+	// Failures of a job less than this long after the last email about it
+	// aren't emailed, but are counted in the next email.
+	MinInterval time.Duration
+
+	// Template of the emails. DefaultEmailTemplate is used if nil.
+	Template *template.Template
+
+	lock sync.Mutex
+	jobs map[string]*emailedJob
+
+	sends sync.WaitGroup
+}
+
+// emailedJob is what the notifier keeps track of for a job.
+type emailedJob struct {
+	lastSent time.Time
+	// Failures not emailed since lastSent.
+	suppressed int
+	// Set when the owner was emailed about a failure, and the job hasn't
+	// succeeded since.
+	failing bool
+}
+
+func (n *EmailNotifier) Notify(e *Event) {
+	if e.Type != EventJobFailed && e.Type != EventJobSucceeded {
+		return
+	}
+	to, err := mail.ParseAddress(e.Owner)
+	if err != nil {
+		return
+	}
+
+	data := n.track(e)
+	if data == nil {
+		return
+	}
+	n.sends.Add(1)
+	go func() {
+		defer n.sends.Done()
+		if err := n.send(to, data); err != nil {
+			log.WithField(LogFieldJobId, e.JobId).WithError(err).Errorf("Email about %s to %s failed.", e.Type, e.Owner)
+		}
+	}()
+}
+
+// Wait waits for the emails being sent.
+func (n *EmailNotifier) Wait() {
+	n.sends.Wait()
+}
+
+// track records the event, and returns what to email about it, or nil if
+// nothing should be.
+func (n *EmailNotifier) track(e *Event) *EmailData {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.jobs == nil {
+		n.jobs = map[string]*emailedJob{}
+	}
+	job := n.jobs[e.JobId]
+	if job == nil {
+		job = &emailedJob{}
+		n.jobs[e.JobId] = job
+	}
+
+	if e.Type == EventJobSucceeded {
+		recovered := job.failing
+		job.failing = false
+		job.suppressed = 0
+		// Jobs that are doing fine are only kept track of while they are
+		// still rate limited.
+		if e.Time.Sub(job.lastSent) >= n.MinInterval {
+			delete(n.jobs, e.JobId)
+		}
+		if !recovered || !n.NotifyRecovery {
+			return nil
+		}
+		return &EmailData{Event: e, Recovery: true, MinInterval: n.MinInterval}
+	}
+
+	if !job.lastSent.IsZero() && e.Time.Sub(job.lastSent) < n.MinInterval {
+		job.suppressed++
+		return nil
+	}
+	data := &EmailData{
+		Event:       e,
+		Output:      lastLines(e.Output, emailOutputLines),
+		Suppressed:  job.suppressed,
+		MinInterval: n.MinInterval,
+	}
+	job.lastSent = e.Time
+	job.suppressed = 0
+	job.failing = true
+	return data
+}
+
+// forgetJob lets go of what is kept track of for a deleted job.
+func (n *EmailNotifier) forgetJob(id string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.jobs, id)
+}
+
+func (n *EmailNotifier) send(to *mail.Address, data *EmailData) error {
+	from, err := mail.ParseAddress(n.From)
+	if err != nil {
+		return err
+	}
+	t := n.Template
+	if t == nil {
+		t = defaultEmailTemplate
+	}
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return err
+	}
+	if err := t.ExecuteTemplate(&body, "body", data); err != nil {
+		return err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(subject.String())))
+	fmt.Fprintf(&msg, "Date: %s\r\n", data.Event.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body.String(), "\r\n", "\n"), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	return smtp.SendMail(n.Addr, auth, from.Address, []string{to.Address}, msg.Bytes())
+}
+
+// headerValue turns s into a single line, so that it can't add headers.
+func headerValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
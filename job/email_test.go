@@ -0,0 +1,248 @@
+package job
+
+import (
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sentEmail is an email a fake SMTP server got.
+type sentEmail struct {
+	to      []string
+	header  mail.Header
+	body    string
+	subject string
+}
+
+// newSMTPServer starts a fake SMTP server that keeps the emails it gets. It
+// returns the server's address, and a function that returns its emails.
+func newSMTPServer(t *testing.T) (string, func() []sentEmail) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var lock sync.Mutex
+	emails := []sentEmail{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				c := textproto.NewConn(conn)
+				_ = c.PrintfLine("220 localhost fake SMTP")
+				to := []string{}
+				for {
+					line, err := c.ReadLine()
+					if err != nil {
+						return
+					}
+					verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+					switch verb {
+					case "RCPT":
+						to = append(to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+						_ = c.PrintfLine("250 OK")
+					case "DATA":
+						_ = c.PrintfLine("354 Go ahead")
+						msg, err := mail.ReadMessage(c.DotReader())
+						if err != nil {
+							return
+						}
+						body, _ := io.ReadAll(msg.Body)
+						subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+						lock.Lock()
+						emails = append(emails, sentEmail{to: to, header: msg.Header, body: string(body), subject: subject})
+						lock.Unlock()
+						to = []string{}
+						_ = c.PrintfLine("250 OK")
+					case "QUIT":
+						_ = c.PrintfLine("221 Bye")
+						return
+					default:
+						_ = c.PrintfLine("250 OK")
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), func() []sentEmail {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]sentEmail(nil), emails...)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	addr, emails := newSMTPServer(t)
+	n := &EmailNotifier{Addr: addr, From: "kala@example.com", NotifyRecovery: true}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "bash -c 'echo oops; false'"
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	n.Wait()
+	sent := emails()
+	if assert.Len(t, sent, 1) {
+		assert.Equal(t, []string{j.Owner}, sent[0].to)
+		from, err := sent[0].header.AddressList("From")
+		assert.NoError(t, err)
+		assert.Equal(t, []*mail.Address{{Address: "kala@example.com"}}, from)
+		assert.Equal(t, "[kala] Job "+j.Name+" failed", sent[0].subject)
+		assert.Contains(t, sent[0].body, j.Id)
+		assert.Contains(t, sent[0].body, "exit status 1")
+		assert.Contains(t, sent[0].body, "Output (last lines):\noops")
+	}
+
+	// Succeeding again after the failure is emailed too.
+	j.lock.Lock()
+	j.Command = "bash -c 'echo fine'"
+	j.lock.Unlock()
+	j.Run(cache)
+	n.Wait()
+	sent = emails()
+	if assert.Len(t, sent, 2) {
+		assert.Equal(t, "[kala] Job "+j.Name+" recovered", sent[1].subject)
+	}
+
+	// Later successes aren't.
+	j.Run(cache)
+	n.Wait()
+	assert.Len(t, emails(), 2)
+}
+
+func TestEmailNotifierRateLimit(t *testing.T) {
+	addr, emails := newSMTPServer(t)
+	n := &EmailNotifier{Addr: addr, From: "kala@example.com", NotifyRecovery: true, MinInterval: time.Hour}
+
+	now := time.Now()
+	failed := func(id string, at time.Duration) {
+		n.Notify(&Event{Type: EventJobFailed, JobId: id, JobName: id, Owner: "owner@example.com", Status: RunFailed, Time: now.Add(at)})
+		n.Wait()
+	}
+	failed("a", 0)
+	failed("a", time.Minute)
+	failed("a", 2*time.Minute)
+	failed("b", 3*time.Minute)
+	assert.Len(t, emails(), 2)
+
+	// The next email counts the failures that weren't emailed.
+	failed("a", time.Hour)
+	sent := emails()
+	if assert.Len(t, sent, 3) {
+		assert.Contains(t, sent[2].body, "2 more failure(s)")
+	}
+
+	// Recovery isn't rate limited, but only follows a failure that was
+	// emailed.
+	n.Notify(&Event{Type: EventJobSucceeded, JobId: "a", Owner: "owner@example.com", Time: now.Add(time.Hour + time.Minute)})
+	n.Wait()
+	assert.Len(t, emails(), 4)
+	failed("a", time.Hour+2*time.Minute)
+	n.Notify(&Event{Type: EventJobSucceeded, JobId: "a", Owner: "owner@example.com", Time: now.Add(time.Hour + 3*time.Minute)})
+	n.Wait()
+	assert.Len(t, emails(), 4)
+}
+
+func TestEmailNotifierSkipsOwnersWithoutEmail(t *testing.T) {
+	addr, emails := newSMTPServer(t)
+	n := &EmailNotifier{Addr: addr, From: "kala@example.com"}
+	n.Notify(&Event{Type: EventJobFailed, JobId: "a", Owner: "", Time: time.Now()})
+	n.Notify(&Event{Type: EventJobFailed, JobId: "b", Owner: "some team", Time: time.Now()})
+	n.Notify(&Event{Type: EventJobStarted, JobId: "c", Owner: "owner@example.com", Time: time.Now()})
+	n.Wait()
+	assert.Empty(t, emails())
+}
+
+func TestEmailNotifierOwnerWithName(t *testing.T) {
+	addr, emails := newSMTPServer(t)
+	n := &EmailNotifier{Addr: addr, From: "Kala <kala@example.com>"}
+	n.Notify(&Event{Type: EventJobFailed, JobId: "a", Owner: "Jane Doe <jane@example.com>", Time: time.Now()})
+	n.Wait()
+
+	sent := emails()
+	if assert.Len(t, sent, 1) {
+		assert.Equal(t, []string{"jane@example.com"}, sent[0].to)
+		to, err := sent[0].header.AddressList("To")
+		assert.NoError(t, err)
+		assert.Equal(t, []*mail.Address{{Name: "Jane Doe", Address: "jane@example.com"}}, to)
+		from, err := sent[0].header.AddressList("From")
+		assert.NoError(t, err)
+		assert.Equal(t, []*mail.Address{{Name: "Kala", Address: "kala@example.com"}}, from)
+	}
+}
+
+func TestEmailNotifierForgetsJobs(t *testing.T) {
+	addr, _ := newSMTPServer(t)
+	n := &EmailNotifier{Addr: addr, From: "kala@example.com", MinInterval: time.Hour}
+	tracked := func() int {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+		return len(n.jobs)
+	}
+
+	// Jobs that succeed aren't kept track of, unless they are rate limited.
+	now := time.Now()
+	n.Notify(&Event{Type: EventJobSucceeded, JobId: "a", Owner: "owner@example.com", Time: now})
+	assert.Equal(t, 0, tracked())
+	n.Notify(&Event{Type: EventJobFailed, JobId: "a", Owner: "owner@example.com", Time: now})
+	n.Notify(&Event{Type: EventJobSucceeded, JobId: "a", Owner: "owner@example.com", Time: now.Add(time.Minute)})
+	assert.Equal(t, 1, tracked())
+	n.Notify(&Event{Type: EventJobSucceeded, JobId: "a", Owner: "owner@example.com", Time: now.Add(time.Hour)})
+	assert.Equal(t, 0, tracked())
+
+	// Nor are deleted jobs.
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "false"
+	j.Retries = 0
+	assert.NoError(t, j.Init(cache))
+	j.Run(cache)
+	assert.Equal(t, 1, tracked())
+	assert.NoError(t, cache.Delete(j.Id))
+	assert.Equal(t, 0, tracked())
+	n.Wait()
+}
+
+func TestEmailTemplate(t *testing.T) {
+	addr, emails := newSMTPServer(t)
+	tmpl, err := ParseEmailTemplate(`{{define "subject"}}{{.Event.JobName}}
+Bcc: someone@example.com{{end}}{{define "body"}}{{.Output}}{{end}}`)
+	assert.NoError(t, err)
+	n := &EmailNotifier{Addr: addr, From: "kala@example.com", Template: tmpl}
+
+	output := strings.Repeat("line\n", 30) + "last"
+	n.Notify(&Event{Type: EventJobFailed, JobId: "a", JobName: "nightly", Owner: "owner@example.com", Output: output, Time: time.Now()})
+	n.Wait()
+	sent := emails()
+	if assert.Len(t, sent, 1) {
+		// Subjects can't add headers.
+		assert.Equal(t, "nightly Bcc: someone@example.com", sent[0].subject)
+		assert.Empty(t, sent[0].header.Get("Bcc"))
+		// Only the end of the output is sent.
+		lines := strings.Split(strings.TrimSpace(sent[0].body), "\n")
+		assert.Len(t, lines, emailOutputLines)
+		assert.Equal(t, "last", lines[len(lines)-1])
+	}
+
+	_, err = ParseEmailTemplate(`{{define "body"}}{{end}}`)
+	assert.Equal(t, ErrInvalidEmailTemplate, err)
+	_, err = ParseEmailTemplate(`{{define "subject"}}{{end`)
+	assert.Error(t, err)
+}
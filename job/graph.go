@@ -0,0 +1,182 @@
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Graph is the dependency graph of the jobs in a cache: the jobs that have
+// parent or dependent jobs, and the edges between them.
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is a job of the dependency graph.
+type GraphNode struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Disabled bool   `json:"disabled"`
+
+	// How the job's last run went, as its stats have it, or empty if it
+	// hasn't run.
+	LastRunStatus RunStatus `json:"last_run_status"`
+	LastRunAt     time.Time `json:"last_run_at"`
+}
+
+// GraphEdge goes from a parent job to a job that depends on it.
+type GraphEdge struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Trigger Trigger `json:"trigger"`
+}
+
+// NewGraph builds the dependency graph of the jobs in the cache. On failure
+// jobs are edges triggered by failure.
+func NewGraph(cache JobCache) *Graph {
+	g := &Graph{Nodes: []*GraphNode{}, Edges: []*GraphEdge{}}
+	nodes := map[string]*GraphNode{}
+
+	jobs := cache.GetAll()
+	jobs.Lock.RLock()
+	for _, j := range jobs.Jobs {
+		j.lock.RLock()
+		node := &GraphNode{Id: j.Id, Name: j.Name, Disabled: j.Disabled}
+		if len(j.Stats) != 0 {
+			last := j.Stats[len(j.Stats)-1]
+			node.LastRunStatus = last.status()
+			node.LastRunAt = last.RanAt
+		}
+		nodes[j.Id] = node
+
+		for _, p := range j.ParentJobs {
+			edge := &GraphEdge{From: p, To: j.Id}
+			if t := j.Triggers[p]; t != nil {
+				edge.Trigger = *t
+			}
+			g.Edges = append(g.Edges, edge)
+		}
+		if j.OnFailureJob != "" && !containsId(j.DependentJobs, j.OnFailureJob) {
+			g.Edges = append(g.Edges, &GraphEdge{From: j.Id, To: j.OnFailureJob, Trigger: Trigger{On: TriggerOnFailure}})
+		}
+		j.lock.RUnlock()
+	}
+	jobs.Lock.RUnlock()
+
+	linked := map[string]bool{}
+	for _, e := range g.Edges {
+		linked[e.From], linked[e.To] = true, true
+	}
+	for id := range linked {
+		if node, ok := nodes[id]; ok {
+			g.Nodes = append(g.Nodes, node)
+		} else {
+			// Refers to a job that no longer exists.
+			g.Nodes = append(g.Nodes, &GraphNode{Id: id, Name: id})
+		}
+	}
+
+	sort.Slice(g.Nodes, func(a, b int) bool {
+		if g.Nodes[a].Name != g.Nodes[b].Name {
+			return g.Nodes[a].Name < g.Nodes[b].Name
+		}
+		return g.Nodes[a].Id < g.Nodes[b].Id
+	})
+	sort.Slice(g.Edges, func(a, b int) bool {
+		if g.Edges[a].From != g.Edges[b].From {
+			return g.Edges[a].From < g.Edges[b].From
+		}
+		return g.Edges[a].To < g.Edges[b].To
+	})
+	return g
+}
+
+// String describes the trigger, e.g. "failure" or "completion 1,3". It is
+// empty for the default of running on success.
+func (t Trigger) String() string {
+	on := t.On
+	if on == "" && len(t.Codes) != 0 {
+		on = TriggerOnCompletion
+	}
+	if on == TriggerOnSuccess && len(t.Codes) == 0 {
+		on = ""
+	}
+	codes := make([]string, len(t.Codes))
+	for i, c := range t.Codes {
+		codes[i] = strconv.Itoa(c)
+	}
+	return strings.TrimSpace(string(on) + " " + strings.Join(codes, ","))
+}
+
+var dotColors = map[RunStatus]string{
+	RunSucceeded: "green",
+	RunFailed:    "red",
+	RunTimedOut:  "orange",
+	RunCancelled: "gray",
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	quote := func(s string) string {
+		return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph kala {\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + quote(n.Name)}
+		if color, ok := dotColors[n.LastRunStatus]; ok {
+			attrs = append(attrs, "color="+color)
+		}
+		if n.Disabled {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", quote(n.Id), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s", quote(e.From), quote(e.To))
+		if label := e.Trigger.String(); label != "" {
+			fmt.Fprintf(&b, " [label=%s]", quote(label))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	quote := func(s string) string {
+		return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+	}
+
+	// Job ids aren't valid Mermaid ids, so nodes are numbered instead.
+	ids := make(map[string]string, len(g.Nodes))
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, n := range g.Nodes {
+		ids[n.Id] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(&b, "  %s[%s]\n", ids[n.Id], quote(n.Name))
+	}
+	for _, e := range g.Edges {
+		if label := e.Trigger.String(); label != "" {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], quote(label), ids[e.To])
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	for _, n := range g.Nodes {
+		if n.LastRunStatus != "" {
+			fmt.Fprintf(&b, "  class %s %s\n", ids[n.Id], n.LastRunStatus)
+		}
+	}
+	b.WriteString("  classDef succeeded stroke:green\n")
+	b.WriteString("  classDef failed stroke:red\n")
+	b.WriteString("  classDef timed_out stroke:orange\n")
+	b.WriteString("  classDef cancelled stroke:gray\n")
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGraph(t *testing.T) {
+	cache := NewMockCache()
+
+	cleanup := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	cleanup.Name = "cleanup"
+	assert.NoError(t, cleanup.Init(cache))
+	defer cleanup.StopTimer()
+	cleanup.Stats = []*JobStat{{Status: RunTimedOut}}
+
+	extract := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	extract.Name = "extract"
+	extract.OnFailureJob = cleanup.Id
+	assert.NoError(t, extract.Init(cache))
+	defer extract.StopTimer()
+	extract.Stats = []*JobStat{{Success: true}}
+
+	load := GetMockJob()
+	load.Name = `load "users"`
+	load.ParentJobs = []string{extract.Id}
+	load.Triggers = map[string]*Trigger{extract.Id: {Codes: []int{0, 3}}}
+	load.Disabled = true
+	assert.NoError(t, load.Init(cache))
+
+	unrelated := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, unrelated.Init(cache))
+	defer unrelated.StopTimer()
+
+	g := NewGraph(cache)
+	if !assert.Len(t, g.Nodes, 3) {
+		return
+	}
+	assert.Equal(t, "cleanup", g.Nodes[0].Name)
+	assert.Equal(t, RunTimedOut, g.Nodes[0].LastRunStatus)
+	assert.Equal(t, RunSucceeded, g.Nodes[1].LastRunStatus)
+	assert.Equal(t, RunStatus(""), g.Nodes[2].LastRunStatus)
+	assert.ElementsMatch(t, []*GraphEdge{
+		{From: extract.Id, To: cleanup.Id, Trigger: Trigger{On: TriggerOnFailure}},
+		{From: extract.Id, To: load.Id, Trigger: Trigger{Codes: []int{0, 3}}},
+	}, g.Edges)
+
+	dot := g.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph kala {\n"))
+	assert.Contains(t, dot, `"`+extract.Id+`" [label="extract", color=green];`)
+	assert.Contains(t, dot, `"`+cleanup.Id+`" [label="cleanup", color=orange];`)
+	assert.Contains(t, dot, `"`+load.Id+`" [label="load \"users\"", style=dashed];`)
+	assert.Contains(t, dot, `"`+extract.Id+`" -> "`+cleanup.Id+`" [label="failure"];`)
+	assert.Contains(t, dot, `"`+extract.Id+`" -> "`+load.Id+`" [label="completion 0,3"];`)
+
+	mermaid := g.Mermaid()
+	assert.True(t, strings.HasPrefix(mermaid, "graph LR\n"))
+	assert.Contains(t, mermaid, `n2["load #quot;users#quot;"]`)
+	assert.Contains(t, mermaid, `n1 -->|"failure"| n0`)
+	assert.Contains(t, mermaid, "class n1 succeeded")
+	assert.Contains(t, mermaid, "class n0 timed_out")
+}
+
+func TestTriggerString(t *testing.T) {
+	assert.Equal(t, "", Trigger{}.String())
+	assert.Equal(t, "", Trigger{On: TriggerOnSuccess}.String())
+	assert.Equal(t, "success 0", Trigger{On: TriggerOnSuccess, Codes: []int{0}}.String())
+	assert.Equal(t, "failure", Trigger{On: TriggerOnFailure}.String())
+	assert.Equal(t, "completion 1,2", Trigger{Codes: []int{1, 2}}.String())
+}
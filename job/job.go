@@ -2,11 +2,15 @@ package job
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +21,7 @@ import (
 
 	uuid "github.com/nu7hatch/gouuid"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const BASE_10 = 10
@@ -27,6 +32,8 @@ var (
 	ErrInvalidJob       = errors.New("Invalid Local Job. Job's must contain a Name and a Command field")
 	ErrInvalidRemoteJob = errors.New("Invalid Remote Job. Job's must contain a Name and a url field")
 	ErrInvalidJobType   = errors.New("Invalid Job type. Types supported: 0 for local and 1 for remote")
+
+	ErrInvalidMisfirePolicy = errors.New("Invalid misfire policy. Policies supported: run_once, run_all and skip")
 )
 
 type Job struct {
@@ -50,7 +57,16 @@ type Job struct {
 	// List of ids of jobs that this job is dependent upon.
 	ParentJobs []string `json:"parent_jobs"`
 
-	// Job that gets run after all retries have failed consecutively
+	// Whether the job runs once all of its parent jobs in a workflow run have
+	// succeeded ("all", the default), or as soon as one of them has ("any").
+	DependencyMode DependencyMode `json:"dependency_mode"`
+
+	// When the job runs after each of its parent jobs, by parent job id.
+	// Parent jobs without a trigger run the job when they succeed.
+	Triggers map[string]*Trigger `json:"triggers"`
+
+	// Job that gets run after all retries have failed consecutively. It is
+	// the same as that job having a trigger on failure of this one.
 	OnFailureJob string `json:"on_failure_job"`
 
 	// ISO 8601 String
@@ -72,6 +88,28 @@ type Job struct {
 	Epsilon         string `json:"epsilon"`
 	epsilonDuration *iso8601.Duration
 
+	// ISO 8601 Duration. Each scheduled run is delayed by a random amount of
+	// up to this, so that jobs scheduled for the same time don't all start at
+	// once. Should be shorter than the interval between runs.
+	Jitter         string `json:"jitter"`
+	jitterDuration *iso8601.Duration
+
+	// If set, the delay within the Jitter window is derived from the job's id
+	// instead of being random, so the job keeps the same offset from run to
+	// run and across restarts.
+	JitterSpread bool `json:"jitter_spread"`
+
+	// The jitter applied to the pending run.
+	jitterOffset time.Duration
+
+	// Names of calendars whose excluded periods the job must not run in.
+	// Scheduled runs that fall in one are skipped.
+	Calendars []string `json:"calendars"`
+	calendars []*Calendar
+
+	// The latest runs skipped because of the job's calendars.
+	SkippedRuns []*SkippedRun `json:"skipped_runs"`
+
 	jobTimer  clock.Timer
 	NextRunAt time.Time `json:"next_run_at"`
 
@@ -96,6 +134,32 @@ type Job struct {
 	// until the next scheduled run time comes along.
 	ResumeAtNextScheduledTime bool `json:"resume_at_next_scheduled_time"`
 
+	// What to do about scheduled runs that were missed while the job was
+	// disabled or kala was down: run the latest one, run all of them or skip
+	// them. If neither this nor MisfireThreshold is set, the behaviour
+	// described for ResumeAtNextScheduledTime applies.
+	MisfirePolicy MisfirePolicy `json:"misfire_policy"`
+
+	// The most missed runs caught up with under the "run_all" policy; only
+	// the latest ones are run. Zero means no limit.
+	MisfireLimit int `json:"misfire_limit"`
+
+	// ISO 8601 Duration. Missed runs more than this late are skipped rather
+	// than caught up with.
+	MisfireThreshold         string `json:"misfire_threshold"`
+	misfireThresholdDuration *iso8601.Duration
+
+	// Missed run points still to be caught up with, oldest first.
+	catchUps []time.Time
+	// The run point to wait for after skipping missed runs.
+	resumeAt time.Time
+
+	// If set, a lease keyed by the job id and scheduled time is taken from
+	// the job database before each scheduled run, so a run is never executed
+	// twice; for example after a failover. Requires a job database that
+	// supports leases.
+	AtMostOnce bool `json:"at_most_once"`
+
 	// Meta data about successful and failed runs.
 	Metadata Metadata `json:"metadata"`
 
@@ -108,6 +172,13 @@ type Job struct {
 	// Collection of Job Stats
 	Stats []*JobStat `json:"stats"`
 
+	// Which stats are kept. The cache's default retention applies if not set.
+	Retention *Retention `json:"retention,omitempty"`
+
+	// Webhooks the job's events are sent to, by the cache's webhook
+	// notifier, as well as the notifier's own.
+	Webhooks []*Webhook `json:"webhooks,omitempty"`
+
 	lock sync.RWMutex
 
 	// Says if a job has been executed right numbers of time
@@ -131,6 +202,14 @@ const (
 	RemoteJob
 )
 
+type MisfirePolicy string
+
+const (
+	MisfireRunOnce MisfirePolicy = "run_once"
+	MisfireRunAll  MisfirePolicy = "run_all"
+	MisfireSkip    MisfirePolicy = "skip"
+)
+
 // RemoteProperties Custom properties for the remote job type
 type RemoteProperties struct {
 	Url    string `json:"url"`
@@ -158,6 +237,26 @@ type Metadata struct {
 	NumberOfFinishedRuns uint      `json:"number_of_finished_runs"`
 }
 
+// merge applies what a run changed, from before to after, to the metadata.
+// Runs of a job can happen at the same time, e.g. when it is started through
+// the API while it runs on its schedule, so counts are added to rather than
+// overwritten.
+func (m *Metadata) merge(before, after Metadata) {
+	m.SuccessCount += after.SuccessCount - before.SuccessCount
+	m.ErrorCount += after.ErrorCount - before.ErrorCount
+	m.NumberOfFinishedRuns += after.NumberOfFinishedRuns - before.NumberOfFinishedRuns
+	m.LastSuccess = latest(m.LastSuccess, after.LastSuccess)
+	m.LastError = latest(m.LastError, after.LastError)
+	m.LastAttemptedRun = latest(m.LastAttemptedRun, after.LastAttemptedRun)
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
 // Bytes returns the byte representation of the Job.
 func (j Job) Bytes() ([]byte, error) { //nolint:govet // Copying the lock is okay here
 	buff := new(bytes.Buffer)
@@ -201,6 +300,20 @@ func (j *Job) Init(cache JobCache) error {
 		return err
 	}
 
+	if j.AtMostOnce {
+		if l, ok := cache.(leaser); !ok || !l.SupportsLeases() {
+			return ErrLeasesUnsupported
+		}
+	}
+
+	if err := j.validateCalendars(cache); err != nil {
+		return err
+	}
+
+	if err := j.checkDependencyCycle(cache); err != nil {
+		return err
+	}
+
 	u4, err := uuid.NewV4()
 	if err != nil {
 		log.Errorf("Error occurred when generating uuid: %s", err)
@@ -232,7 +345,7 @@ func (j *Job) Init(cache JobCache) error {
 	// TODO: Delete from cache after running.
 	if j.Schedule == "" {
 		// If schedule is empty, its a one-off job.
-		go j.Run(cache)
+		go j.run(cache, runOptions{source: SourceSchedule})
 		return nil
 	}
 
@@ -279,7 +392,7 @@ func (j *Job) InitDelayDuration(checkTime bool) error {
 	} else {
 		j.timesToRepeat, err = strconv.ParseInt(strings.Split(splitTime[0], "R")[1], BASE_10, 0)
 		if err != nil {
-			log.Errorf("Error converting timesToRepeat to an int: %s", err)
+			j.logger().Errorf("Error converting timesToRepeat to an int: %s", err)
 			return err
 		}
 	}
@@ -288,7 +401,7 @@ func (j *Job) InitDelayDuration(checkTime bool) error {
 	if err != nil {
 		j.scheduleTime, err = time.Parse(RFC3339WithoutTimezone, splitTime[1])
 		if err != nil {
-			log.Errorf("Error converting scheduleTime to a time.Time: %s", err)
+			j.logger().Errorf("Error converting scheduleTime to a time.Time: %s", err)
 			return err
 		}
 	}
@@ -298,22 +411,38 @@ func (j *Job) InitDelayDuration(checkTime bool) error {
 			return fmt.Errorf("Job %s:%s cannot be scheduled %s ago", j.Name, j.Id, diff.String())
 		}
 	}
-	log.Debugf("Job %s:%s scheduled", j.Name, j.Id)
-	log.Debugf("Starting %s will repeat for %d", j.scheduleTime, j.timesToRepeat)
+	j.logger().Debug("Job scheduled")
+	j.logger().Debugf("Starting %s will repeat for %d", j.scheduleTime, j.timesToRepeat)
 
 	if j.timesToRepeat != 0 {
 		j.delayDuration, err = iso8601.FromString(splitTime[2])
 		if err != nil {
-			log.Errorf("Error converting delayDuration to a iso8601.Duration: %s", err)
+			j.logger().Errorf("Error converting delayDuration to a iso8601.Duration: %s", err)
 			return err
 		}
-		log.Debugf("Delay duration is %s", j.delayDuration.RelativeTo(j.clk.Time().Now()))
+		j.logger().Debugf("Delay duration is %s", j.delayDuration.RelativeTo(j.clk.Time().Now()))
 	}
 
 	if j.Epsilon != "" {
 		j.epsilonDuration, err = iso8601.FromString(j.Epsilon)
 		if err != nil {
-			log.Errorf("Error converting j.Epsilon to iso8601.Duration: %s", err)
+			j.logger().Errorf("Error converting j.Epsilon to iso8601.Duration: %s", err)
+			return err
+		}
+	}
+
+	if j.Jitter != "" {
+		j.jitterDuration, err = iso8601.FromString(j.Jitter)
+		if err != nil {
+			j.logger().Errorf("Error converting j.Jitter to iso8601.Duration: %s", err)
+			return err
+		}
+	}
+
+	if j.MisfireThreshold != "" {
+		j.misfireThresholdDuration, err = iso8601.FromString(j.MisfireThreshold)
+		if err != nil {
+			j.logger().Errorf("Error converting j.MisfireThreshold to iso8601.Duration: %s", err)
 			return err
 		}
 	}
@@ -322,16 +451,51 @@ func (j *Job) InitDelayDuration(checkTime bool) error {
 
 // StartWaiting begins a timer for when it should execute the Jobs .Run() method.
 func (j *Job) StartWaiting(cache JobCache, justRan bool) {
-	waitDuration := j.GetWaitDuration()
-
+	if !justRan {
+		j.planMisfires()
+	}
 	j.lock.Lock()
 	defer j.lock.Unlock()
 
-	log.Infof("Job %s:%s repeating in %s", j.Name, j.Id, waitDuration)
+	j.calendars = j.resolveCalendars(cache)
+	waitDuration, jitter, skipped := j.nextRun()
+	j.jitterOffset = jitter
+	j.recordSkipped(skipped)
+	runPoint := j.clk.Time().Now().Add(waitDuration)
+	waitDuration += jitter
+
+	j.logger().Infof("Job repeating in %s", waitDuration)
 
 	j.NextRunAt = j.clk.Time().Now().Add(waitDuration)
 
-	jobRun := func() { j.Run(cache) }
+	scheduledAt := j.NextRunAt
+	if len(j.catchUps) > 0 {
+		scheduledAt = j.catchUps[0]
+		runPoint = scheduledAt
+	}
+	// Leases are keyed on the point of the schedule, which all nodes agree
+	// on, unlike when they run it after jitter.
+	leasePoint := j.nearestSchedulePoint(runPoint)
+	jobRun := func() {
+		if t, ok := cache.(runTracker); ok && t.stopping() {
+			return
+		}
+		catchUp := j.popCatchUp()
+		if !ownsJob(cache, j.Id) {
+			j.logger().Debug("Job is owned by another node, skipping run.")
+			j.skipRun(cache)
+			return
+		}
+		if j.inBlackout(cache, scheduledAt) {
+			j.skipRun(cache)
+			return
+		}
+		if !j.acquireRunLease(cache, leasePoint) {
+			j.skipRun(cache)
+			return
+		}
+		j.run(cache, runOptions{catchUp: catchUp, source: SourceSchedule, scheduledAt: scheduledAt})
+	}
 	j.jobTimer = j.clk.Time().AfterFunc(waitDuration, jobRun)
 
 	if justRan && j.ranChan != nil {
@@ -339,18 +503,129 @@ func (j *Job) StartWaiting(cache JobCache, justRan bool) {
 	}
 }
 
+// GetWaitDuration returns how long until the job's next run, jitter included.
+// Run points in periods excluded by the job's calendars are skipped over.
 func (j *Job) GetWaitDuration() time.Duration {
 	j.lock.RLock()
 	defer j.lock.RUnlock()
 
+	waitDuration, jitter, _ := j.nextRun()
+	return waitDuration + jitter
+}
+
+// NextRuns returns the times of up to n upcoming scheduled runs. They are
+// worked out by playing the job's timer forward on a copy of its schedule, so
+// jitter, calendars and missed runs still to be caught up with are taken into
+// account. Random jitter differs from one call to the next.
+func (j *Job) NextRuns(n int) []time.Time {
+	j.lock.RLock()
+	idle := j.Schedule == "" || j.Disabled || j.IsDone
+	// Stats are counted the same way as in ShouldStartWaiting.
+	runsLeft := -1
+	if j.hasFixedRepetitions() {
+		runsLeft = int(j.timesToRepeat) + 1 - j.runsSoFar()
+	}
+	clk := clock.NewMockClock(j.clk.Time().Now())
+	nextRunAt := j.NextRunAt
+	sim := &Job{
+		Id:                        j.Id,
+		scheduleTime:              j.scheduleTime,
+		delayDuration:             j.delayDuration,
+		timesToRepeat:             j.timesToRepeat,
+		jitterDuration:            j.jitterDuration,
+		JitterSpread:              j.JitterSpread,
+		jitterOffset:              j.jitterOffset,
+		calendars:                 j.calendars,
+		catchUps:                  append([]time.Time(nil), j.catchUps...),
+		resumeAt:                  j.resumeAt,
+		ResumeAtNextScheduledTime: j.ResumeAtNextScheduledTime,
+		Metadata:                  Metadata{LastAttemptedRun: j.Metadata.LastAttemptedRun},
+	}
+	j.lock.RUnlock()
+
+	runs := []time.Time{}
+	if idle {
+		return runs
+	}
+	sim.clk.SetClock(clk)
+	for len(runs) < n && runsLeft != 0 {
+		var runAt time.Time
+		jitter := sim.jitterOffset
+		if len(runs) == 0 && nextRunAt.After(clk.Now()) {
+			// The pending timer is already set for this.
+			runAt = nextRunAt
+		} else {
+			var waitDuration time.Duration
+			waitDuration, jitter, _ = sim.nextRun()
+			// Timers that are already due fire straight away.
+			runAt = clk.Now().Add(waitDuration + jitter)
+			if runAt.Before(clk.Now()) {
+				runAt = clk.Now()
+			}
+		}
+		runs = append(runs, runAt)
+		runsLeft--
+
+		// Record the run as run() would, just after the timer fires.
+		clk.SetTime(runAt.Add(time.Nanosecond))
+		sim.Metadata.LastAttemptedRun = runAt
+		sim.jitterOffset = jitter
+		sim.popCatchUp()
+	}
+	return runs
+}
+
+// nextRun returns how long until the job's next run point outside of the
+// periods excluded by its calendars, the jitter to add to it, and the run
+// points skipped to get there.
+func (j *Job) nextRun() (time.Duration, time.Duration, []*SkippedRun) {
+	waitDuration, jitter := j.waitDuration()
+	if len(j.calendars) == 0 {
+		return waitDuration, jitter, nil
+	}
+
+	now := j.clk.Time().Now()
+	runPoint := now.Add(waitDuration)
+	skipped := []*SkippedRun{}
+	for i := 0; i < maxCalendarSkips; i++ {
+		calendar, until := j.excludedBy(runPoint.Add(jitter))
+		if calendar == "" {
+			return runPoint.Sub(now), jitter, skipped
+		}
+
+		// Runs that are due straight away have no later run point to skip
+		// to, so they're held back until the excluded period is over.
+		if waitDuration <= 0 || j.timesToRepeat == 0 {
+			runPoint, jitter = until, 0
+			continue
+		}
+		skipped = append(skipped, &SkippedRun{ScheduledAt: runPoint.Add(jitter), Calendar: calendar})
+		runPoint = j.delayDuration.Add(runPoint)
+	}
+
+	j.logger().Errorf("Job has no run point outside of its calendars in the next %d, not skipping any further.", maxCalendarSkips)
+	return runPoint.Sub(now), jitter, skipped
+}
+
+// waitDuration returns how long until the job's next run point, and the jitter
+// to add to it. Jobs due to run straight away are not jittered.
+func (j *Job) waitDuration() (time.Duration, time.Duration) {
 	waitDuration := time.Duration(j.scheduleTime.UnixNano() - j.clk.Time().Now().UnixNano())
 
 	if waitDuration >= 0 {
-		return waitDuration
+		return waitDuration, j.nextJitter()
 	}
 
 	if j.timesToRepeat == 0 {
-		return 0
+		return 0, 0
+	}
+
+	if len(j.catchUps) > 0 {
+		return 0, 0
+	}
+
+	if !j.resumeAt.IsZero() {
+		return j.resumeAt.Sub(j.clk.Time().Now()), j.nextJitter()
 	}
 
 	if j.ResumeAtNextScheduledTime {
@@ -362,7 +637,7 @@ func (j *Job) GetWaitDuration() time.Duration {
 		// (This would be due to not calling InitDelayDuration on a job.)
 		// For now, we spot this and handle it as a special case.
 		if j.scheduleTime.IsZero() {
-			return 0
+			return 0, 0
 		}
 
 		newRunPoint := j.scheduleTime
@@ -370,25 +645,318 @@ func (j *Job) GetWaitDuration() time.Duration {
 			newRunPoint = j.delayDuration.Add(newRunPoint)
 		}
 
-		return newRunPoint.Sub(j.clk.Time().Now())
+		return newRunPoint.Sub(j.clk.Time().Now()), j.nextJitter()
 	}
 
 	if j.Metadata.LastAttemptedRun.IsZero() {
 		waitDuration = j.delayDuration.RelativeTo(j.clk.Time().Now())
 	} else {
-		lastRun := j.Metadata.LastAttemptedRun
+		// Take off the last run's jitter so it doesn't add up from run to run.
+		lastRun := j.Metadata.LastAttemptedRun.Add(-j.appliedJitter())
 		// Needs to be recalculated each time because of Months.
 		lastRun = j.delayDuration.Add(lastRun)
 		waitDuration = lastRun.Sub(j.clk.Time().Now())
 	}
 
-	return waitDuration
+	return waitDuration, j.nextJitter()
+}
+
+// hasInterval returns whether the job's schedule has an interval to repeat
+// at.
+func (j *Job) hasInterval() bool {
+	return j.delayDuration != nil && j.delayDuration.RelativeTo(j.scheduleTime) > 0
+}
+
+// hasFixedInterval returns whether the job's interval is a fixed length of
+// time, rather than a number of days, weeks, months or years, which vary.
+func (j *Job) hasFixedInterval() bool {
+	d := j.delayDuration
+	return d.Years == 0 && d.Months == 0 && d.Weeks == 0 && d.Days == 0
+}
+
+// schedulePoint returns the point of the job's schedule n intervals after it
+// starts, which is before it starts for negative n. Intervals in days or
+// months are stepped through one at a time, as the job's timer does, since
+// they don't add up: a month after January 31st is March 3rd, and a month
+// after that is April 3rd, not March 31st. Fixed intervals are multiplied.
+func (j *Job) schedulePoint(n int64) time.Time {
+	d := j.delayDuration
+	if d == nil || n == 0 {
+		return j.scheduleTime
+	}
+	if j.hasFixedInterval() {
+		return j.scheduleTime.Add(time.Duration(n) * d.RelativeTo(j.scheduleTime))
+	}
+	t := j.scheduleTime
+	for i := int64(0); i < n; i++ {
+		t = d.Add(t)
+	}
+	for i := int64(0); i > n; i-- {
+		t = d.Sub(t)
+	}
+	return t
+}
+
+// scheduleIndex returns the number of the last point of the job's schedule
+// at or before t, counting from when it starts. It is worked out rather than
+// walked to for fixed intervals, so it takes no longer for points far from the
+// start.
+func (j *Job) scheduleIndex(t time.Time) int64 {
+	d := j.delayDuration
+	if j.hasFixedInterval() {
+		n := int64(t.Sub(j.scheduleTime) / d.RelativeTo(j.scheduleTime))
+		// Division rounds towards the start, which is up before it.
+		if j.schedulePoint(n).After(t) {
+			n--
+		}
+		return n
+	}
+
+	n, point := int64(0), j.scheduleTime
+	for point.After(t) {
+		point = d.Sub(point)
+		n--
+	}
+	if n < 0 {
+		return n
+	}
+	for next := d.Add(point); !next.After(t); next = d.Add(next) {
+		n++
+	}
+	return n
+}
+
+// nearestSchedulePoint returns the point of the job's schedule closest to t,
+// or its start if it doesn't repeat. Jobs without a schedule get t.
+func (j *Job) nearestSchedulePoint(t time.Time) time.Time {
+	if j.scheduleTime.IsZero() {
+		return t
+	}
+	if !j.hasInterval() {
+		return j.scheduleTime
+	}
+	n := j.scheduleIndex(t)
+	before, after := j.schedulePoint(n), j.schedulePoint(n+1)
+	if after.Sub(t) < t.Sub(before) {
+		return after
+	}
+	return before
+}
+
+// nextJitter returns the jitter for the job's next run.
+func (j *Job) nextJitter() time.Duration {
+	if j.jitterDuration == nil {
+		return 0
+	}
+	window := j.jitterDuration.RelativeTo(j.clk.Time().Now())
+	if window <= 0 {
+		return 0
+	}
+
+	if j.JitterSpread {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(j.Id))
+		return time.Duration(h.Sum64() % uint64(window))
+	}
+	return time.Duration(rand.Int63n(int64(window))) //nolint:gosec // Jitter needs no secure randomness
+}
+
+// appliedJitter returns the jitter that was applied to the job's last run.
+// For random jitter this is unknown after a restart, in which case the
+// schedule moves by up to the jitter window once.
+func (j *Job) appliedJitter() time.Duration {
+	if j.JitterSpread {
+		return j.nextJitter()
+	}
+	return j.jitterOffset
+}
+
+// validateCalendars checks that the calendars the job refers to exist.
+func (j *Job) validateCalendars(cache JobCache) error {
+	if len(j.Calendars) == 0 {
+		return nil
+	}
+	cc, ok := cache.(CalendarCache)
+	if !ok {
+		return ErrCalendarsUnsupported
+	}
+	for _, name := range j.Calendars {
+		if _, err := cc.GetCalendar(name); err != nil {
+			return fmt.Errorf("%w: %s", err, name)
+		}
+	}
+	return nil
+}
+
+// resolveCalendars looks up the job's calendars in the cache.
+func (j *Job) resolveCalendars(cache JobCache) []*Calendar {
+	if len(j.Calendars) == 0 {
+		return nil
+	}
+	cc, ok := cache.(CalendarCache)
+	if !ok {
+		return nil
+	}
+	calendars := make([]*Calendar, 0, len(j.Calendars))
+	for _, name := range j.Calendars {
+		c, err := cc.GetCalendar(name)
+		if err != nil {
+			j.logger().Warnf("Job refers to calendar %s, which doesn't exist.", name)
+			continue
+		}
+		calendars = append(calendars, c)
+	}
+	return calendars
+}
+
+// excludedBy returns the name of the first of the job's calendars that
+// excludes t, and when the excluded period ends; or "" if none does.
+func (j *Job) excludedBy(t time.Time) (string, time.Time) {
+	for _, c := range j.calendars {
+		if excluded, until := c.Excludes(t); excluded {
+			return c.Name, until
+		}
+	}
+	return "", time.Time{}
+}
+
+func (j *Job) usesCalendar(name string) bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	for _, n := range j.Calendars {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Job) recordSkipped(skipped []*SkippedRun) {
+	for _, s := range skipped {
+		j.logger().Infof("Job run at %s skipped, as calendar %s excludes it.", s.ScheduledAt, s.Calendar)
+	}
+	j.SkippedRuns = append(j.SkippedRuns, skipped...)
+	if len(j.SkippedRuns) > MaxSkippedRuns {
+		j.SkippedRuns = j.SkippedRuns[len(j.SkippedRuns)-MaxSkippedRuns:]
+	}
+}
+
+// inBlackout reports whether one of the job's calendars excludes the current
+// time, recording the run as skipped if so. Calendars may have changed since
+// the run was scheduled, e.g. on another node of a cluster.
+func (j *Job) inBlackout(cache JobCache, scheduledAt time.Time) bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.calendars = j.resolveCalendars(cache)
+	calendar, _ := j.excludedBy(j.clk.Time().Now())
+	if calendar == "" {
+		return false
+	}
+	j.recordSkipped([]*SkippedRun{{ScheduledAt: scheduledAt, Calendar: calendar}})
+	return true
+}
+
+// reschedule restarts the job's timer, if it is waiting to run, so that
+// changes affecting its schedule take effect.
+func (j *Job) reschedule(cache JobCache) {
+	j.lock.RLock()
+	waiting := j.jobTimer != nil && j.ShouldStartWaiting()
+	j.lock.RUnlock()
+	if !waiting {
+		return
+	}
+
+	j.StopTimer()
+	j.StartWaiting(cache, false)
+}
+
+// planMisfires works out, according to the job's misfire policy, which of the
+// runs missed since the last one are to be caught up with. They are then run
+// one after the other before the job returns to its schedule.
+func (j *Job) planMisfires() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.catchUps = nil
+	j.resumeAt = time.Time{}
+
+	if j.MisfirePolicy == "" && j.misfireThresholdDuration == nil {
+		return
+	}
+	if j.ResumeAtNextScheduledTime || j.timesToRepeat == 0 || j.scheduleTime.IsZero() {
+		return
+	}
+
+	now := j.clk.Time().Now()
+	var cutoff time.Time
+	if j.misfireThresholdDuration != nil {
+		cutoff = now.Add(-j.misfireThresholdDuration.RelativeTo(now))
+	}
+
+	// How many of the latest missed runs to keep; -1 keeps them all.
+	keep := 1
+	switch j.MisfirePolicy {
+	case MisfireSkip:
+		keep = 0
+	case MisfireRunAll:
+		keep = j.MisfireLimit
+		if keep == 0 {
+			keep = -1
+		}
+	}
+
+	runPoint := j.scheduleTime
+	if !j.Metadata.LastAttemptedRun.IsZero() {
+		runPoint = j.delayDuration.Add(j.Metadata.LastAttemptedRun.Add(-j.appliedJitter()))
+	}
+	missed := 0
+	for runPoint.Before(now) {
+		missed++
+		if keep != 0 && !runPoint.Before(cutoff) {
+			j.catchUps = append(j.catchUps, runPoint)
+			if keep > 0 && len(j.catchUps) > keep {
+				j.catchUps = j.catchUps[1:]
+			}
+		}
+		runPoint = j.delayDuration.Add(runPoint)
+	}
+
+	if missed == 0 {
+		return
+	}
+	if len(j.catchUps) == 0 {
+		j.resumeAt = runPoint
+	}
+	j.logger().Infof("Job missed %d runs, catching up with %d.", missed, len(j.catchUps))
+}
+
+// popCatchUp consumes the next planned catch-up run, if any, returning whether
+// there was one.
+func (j *Job) popCatchUp() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.resumeAt = time.Time{}
+	if len(j.catchUps) == 0 {
+		return false
+	}
+	j.catchUps = j.catchUps[1:]
+	return true
 }
 
 // Disable stops the job from running by stopping its jobTimer. It also sets Job.Disabled to true,
 // which is reflected in the UI.
 func (j *Job) Disable(cache JobCache) error {
-	return cache.Disable(j)
+	if err := cache.Disable(j); err != nil {
+		return err
+	}
+	j.lock.RLock()
+	e := j.event(EventJobDisabled)
+	j.lock.RUnlock()
+	notify(cache, e)
+	return nil
 }
 
 func (j *Job) Enable(cache JobCache) error {
@@ -442,7 +1010,7 @@ func (j *Job) DeleteFromDependentJobs(cache JobCache) error {
 
 		// If there are no other parent jobs, delete this job.
 		if len(childJob.ParentJobs) == 1 {
-			log.Infof("Deleting child %s", id)
+			childJob.logger().Info("Deleting child job, as it has no other parent jobs.")
 			_ = cache.Delete(childJob.Id)
 			continue
 		}
@@ -473,53 +1041,183 @@ func (j *Job) RunOnFailureJob(cache JobCache) {
 	if j.OnFailureJob != "" {
 		onFailureJob, cacheErr := cache.Get(j.OnFailureJob)
 		if cacheErr == ErrJobDoesntExist {
-			log.Errorf("Error retrieving dependent job with id of %s", j.OnFailureJob)
+			j.logger().Errorf("Error retrieving on failure job with id of %s", j.OnFailureJob)
 		} else {
-			onFailureJob.Run(cache)
+			onFailureJob.run(cache, runOptions{source: SourceFailure})
 		}
 	}
 }
 
 func (j *Job) Run(cache JobCache) {
+	j.run(cache, runOptions{})
+}
+
+// RunWithParams runs the job once with the given parameters, which its
+// templates get as .Params and local jobs as KALA_PARAM_<NAME> environment
+// variables. The job itself is left as it is.
+func (j *Job) RunWithParams(cache JobCache, params map[string]interface{}) {
+	j.run(cache, runOptions{params: params})
+}
+
+// runOptions describe what a run is part of.
+type runOptions struct {
+	// Set when the run makes up for a missed one.
+	catchUp bool
+	// The workflow run the job is running as part of, if any. Runs not
+	// triggered by a parent job start a workflow run of their own.
+	workflow *WorkflowRun
+	// The parent job, and its run, that triggered the run.
+	triggeredBy, triggeredByRun string
+	// If set, the run has already been registered with the cache's run
+	// tracker, and runs with this context.
+	ctx context.Context
+	// Parameters the run was started with.
+	params map[string]interface{}
+	// The run, if it was started with Start. Other runs get one of their own.
+	jobRun *JobRun
+	// Set for runs of a backfill. They leave the job's schedule alone.
+	backfillId  string
+	logicalTime time.Time
+	// What started the run, manual if not set, and when it was due to start,
+	// when it was called if not set.
+	source      TriggerSource
+	scheduledAt time.Time
+	// The span of the parent job's run, for runs triggered by one.
+	parentSpan trace.SpanContext
+}
 
+func (j *Job) run(cache JobCache, opts runOptions) {
+	calledAt := j.clk.Time().Now()
 	_, err := cache.Get(j.Id)
 	if errors.Is(err, ErrJobDoesntExist) {
-		log.Infof("Job %s with id %s tried to run, but exited early because it has been deleted", j.Name, j.Id)
+		j.logger().Info("Job tried to run, but exited early because it has been deleted")
+		opts.workflow.jobFinished(cache, j.Id, WorkflowSkipped, nil)
+		opts.jobRun.finish(j.clk.Time().Now(), nil, ErrJobDeleted)
 		return
 	}
 
+	ctx := opts.ctx
+	if t, ok := cache.(runTracker); ok && ctx == nil {
+		var accepted bool
+		if ctx, accepted = t.startRun(); !accepted {
+			j.logger().Info("Job not started, as kala is shutting down.")
+			opts.workflow.jobFinished(cache, j.Id, WorkflowSkipped, nil)
+			return
+		}
+		defer t.finishRun()
+	}
+
+	if opts.jobRun == nil {
+		if opts.jobRun, err = newJobRun(j); err != nil {
+			log.Errorf("Error occurred when generating uuid: %s", err)
+		} else {
+			trackJobRun(cache, opts.jobRun)
+		}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := opts.jobRun.withCancel(ctx)
+	defer cancel()
+
+	if opts.workflow == nil {
+		opts.workflow = startWorkflowRun(cache, j)
+	}
+	if opts.source == "" {
+		opts.source = SourceManual
+	}
+	if opts.scheduledAt.IsZero() {
+		opts.scheduledAt = calledAt
+	}
+
 	j.lock.RLock()
-	jobRunner := &JobRunner{job: j, meta: j.Metadata}
+	ctx, span := startRunSpan(ctx, j, opts)
+	oldMeta := j.Metadata
+	jobRunner := &JobRunner{
+		job:            j,
+		meta:           j.Metadata,
+		ctx:            ctx,
+		catchUp:        opts.catchUp,
+		triggeredBy:    opts.triggeredBy,
+		triggeredByRun: opts.triggeredByRun,
+		params:         opts.params,
+		jobRun:         opts.jobRun,
+		backfillId:     opts.backfillId,
+		logicalTime:    opts.logicalTime,
+		source:         opts.source,
+		scheduledAt:    opts.scheduledAt,
+	}
+	if opts.workflow != nil {
+		jobRunner.workflowRunId = opts.workflow.Id
+		jobRunner.parents = opts.workflow.parentOutputs(j.Id)
+	}
 	j.lock.RUnlock()
 
 	newStat, newMeta, err := jobRunner.Run(cache)
-	if err != nil {
-		j.lock.RLock()
-		j.RunOnFailureJob(cache)
-		j.lock.RUnlock()
-	}
+	endRunSpan(span, newStat, err)
 
 	j.lock.Lock()
-	j.Metadata = newMeta
+	// Runs of backfills are only recorded in the job's stats, as they don't
+	// count towards its schedule.
+	if opts.backfillId == "" {
+		j.Metadata.merge(oldMeta, newMeta)
+	}
+	id, name := j.Id, j.Name
+	var finished *Event
 	if newStat != nil {
 		j.Stats = append(j.Stats, newStat)
+		finished = jobRunner.finishedEvent()
+		j.applyRetention(defaultRetentionOf(cache), j.clk.Time().Now())
 	}
 
 	// Kinda annoying and inefficient that it needs to be done this way.
 	// Some refactoring is probably in order.
 
 	j.lock.Unlock()
+	if newStat != nil {
+		recordRunMetrics(id, name, newStat)
+		notify(cache, finished)
+	}
 	j.lock.RLock()
 	if err := cache.Set(j); err != nil {
-		log.Errorf("Job %s with id %s ran, but the results couldn't be persisted: %v", j.Name, j.Id, err)
+		j.logger().Errorf("Job ran, but the results couldn't be persisted: %v", err)
 	}
 	j.lock.RUnlock()
+
+	opts.jobRun.finish(j.clk.Time().Now(), newStat, err)
+
+	// Run Dependent Jobs, including the on failure job. They run on their
+	// own, once the results of this run are in.
+	switch {
+	case err == nil:
+		opts.workflow.jobFinished(cache, j.Id, WorkflowSucceeded, jobRunner)
+	case errors.Is(err, ErrJobDisabled), errors.Is(err, ErrJobDeleted):
+		opts.workflow.jobFinished(cache, j.Id, WorkflowSkipped, jobRunner)
+	case errors.Is(err, ErrJobCancelled):
+		opts.workflow.jobFinished(cache, j.Id, WorkflowCancelled, jobRunner)
+	default:
+		opts.workflow.jobFinished(cache, j.Id, WorkflowFailed, jobRunner)
+	}
+
+	if opts.backfillId != "" {
+		return
+	}
+
 	j.lock.Lock()
 
+	var exhausted *Event
 	if j.ShouldStartWaiting() {
 		go j.StartWaiting(cache, true)
 	} else {
-		j.IsDone = true
+		// Runs skipped because the job is disabled or deleted don't use up
+		// its schedule.
+		skipped := errors.Is(err, ErrJobDisabled) || errors.Is(err, ErrJobDeleted)
+		if !skipped && j.repetitionsUsedUp() {
+			if !j.IsDone && j.Schedule != "" {
+				exhausted = j.event(EventScheduleExhausted)
+			}
+			j.IsDone = true
+		}
 
 		if j.ranChan != nil {
 			j.ranChan <- struct{}{}
@@ -527,6 +1225,52 @@ func (j *Job) Run(cache JobCache) {
 	}
 
 	j.lock.Unlock()
+	notify(cache, exhausted)
+}
+
+// skipRun is called instead of Run when the job's timer fires but the run has
+// to happen elsewhere. The job is rescheduled as though it had run, so that the
+// node keeps up with the schedule in case it has to run the job later.
+func (j *Job) skipRun(cache JobCache) {
+	j.lock.Lock()
+	j.Metadata.LastAttemptedRun = j.clk.Time().Now()
+	j.lock.Unlock()
+
+	if j.ShouldStartWaiting() {
+		j.StartWaiting(cache, false)
+	}
+}
+
+// acquireRunLease takes the lease for the run at the given point of the job's
+// schedule, jitter left out, for jobs that have AtMostOnce set. It returns
+// false if the run must not happen here, either because another node holds
+// the lease or it couldn't be taken.
+func (j *Job) acquireRunLease(cache JobCache, scheduledAt time.Time) bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	if !j.AtMostOnce {
+		return true
+	}
+
+	l, ok := cache.(leaser)
+	if !ok {
+		j.logger().Errorf("Job skipped: %s", ErrLeasesUnsupported)
+		return false
+	}
+
+	// Rounded so that nodes computing the same run point agree on the key,
+	// for jobs without a schedule.
+	key := fmt.Sprintf("%s/%d", j.Id, scheduledAt.Round(time.Second).Unix())
+	acquired, err := l.AcquireLease(key, RunLeaseTTL)
+	if err != nil {
+		j.logger().Errorf("Job skipped, could not acquire run lease: %s", err)
+		return false
+	}
+	if !acquired {
+		j.logger().Infof("Job skipped, run scheduled at %s is held by another lease.", scheduledAt)
+	}
+	return acquired
 }
 
 func (j *Job) StopTimer() {
@@ -555,26 +1299,144 @@ func (j *Job) ShouldStartWaiting() bool {
 		return false
 	}
 
-	if j.hasFixedRepetitions() && int(j.timesToRepeat) < len(j.Stats) {
-		return false
+	return !j.repetitionsUsedUp()
+}
+
+// repetitionsUsedUp returns whether the job has run as many times as its
+// schedule allows.
+func (j *Job) repetitionsUsedUp() bool {
+	return j.hasFixedRepetitions() && int(j.timesToRepeat) < j.runsSoFar()
+}
+
+// runsSoFar is how many times the job has run, leaving out runs of backfills.
+// Stats can't be counted alone, as retention may have removed some of them.
+func (j *Job) runsSoFar() int {
+	runs := 0
+	for _, stat := range j.Stats {
+		if stat.BackfillId == "" {
+			runs++
+		}
 	}
-	return true
+	if n := int(j.Metadata.NumberOfFinishedRuns); n > runs {
+		return n
+	}
+	return runs
 }
 
 func (j *Job) validation() error {
-	var err error
+	errs := j.validationErrors()
+	if len(errs) == 0 {
+		return nil
+	}
+	j.logger().Error(errs[0])
+	return errs[0]
+}
+
+// validationErrors returns everything wrong with the job's type and params.
+func (j *Job) validationErrors() []error {
+	errs := []error{}
 	switch {
 	case j.JobType == LocalJob && (j.Name == "" || j.Command == ""):
-		err = ErrInvalidJob
+		errs = append(errs, ErrInvalidJob)
 	case j.JobType == RemoteJob && (j.Name == "" || j.RemoteProperties.Url == ""):
-		err = ErrInvalidRemoteJob
+		errs = append(errs, ErrInvalidRemoteJob)
 	case j.JobType != LocalJob && j.JobType != RemoteJob:
-		err = ErrInvalidJobType
-	default:
-		return nil
+		errs = append(errs, ErrInvalidJobType)
+	}
+	if j.MisfirePolicy != "" && j.MisfirePolicy != MisfireRunOnce &&
+		j.MisfirePolicy != MisfireRunAll && j.MisfirePolicy != MisfireSkip {
+		errs = append(errs, ErrInvalidMisfirePolicy)
+	}
+	if j.DependencyMode != "" && j.DependencyMode != DependOnAll && j.DependencyMode != DependOnAny {
+		errs = append(errs, ErrInvalidDependencyMode)
+	}
+	for parentId, t := range j.Triggers {
+		if t == nil || !t.valid() || !containsId(j.ParentJobs, parentId) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidTrigger, parentId))
+		}
+	}
+	if err := j.Retention.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, w := range j.Webhooks {
+		if err := w.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func containsId(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the job the way Init does, as well as parsing its templates
+// and checking the jobs it refers to, without adding it to the cache or running
+// it. Every problem found is returned rather than just the first. Checks that
+// need the cache are skipped if it is nil.
+func (j *Job) Validate(cache JobCache) []error {
+	if clker, ok := cache.(Clocker); ok && clker.TimeSet() {
+		j.SetClock(clker.Time())
+	}
+
+	j.lock.RLock()
+	errs := j.validationErrors()
+	j.lock.RUnlock()
+
+	if err := j.InitDelayDuration(true); err != nil {
+		errs = append(errs, err)
+	}
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	if j.TemplateDelimiters != "" {
+		templates := []string{j.Command}
+		if j.JobType == RemoteJob {
+			templates = []string{j.RemoteProperties.Url, j.RemoteProperties.Body}
+		}
+		for _, t := range templates {
+			_, err := j.parseTemplate(t)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if errors.Is(err, ErrInvalidDelimiters) {
+				break
+			}
+		}
 	}
-	log.Errorf(err.Error())
-	return err
+
+	if cache == nil {
+		return errs
+	}
+
+	if j.AtMostOnce {
+		if l, ok := cache.(leaser); !ok || !l.SupportsLeases() {
+			errs = append(errs, ErrLeasesUnsupported)
+		}
+	}
+	if err := j.validateCalendars(cache); err != nil {
+		errs = append(errs, err)
+	}
+	for _, id := range j.ParentJobs {
+		if _, err := cache.Get(id); err != nil {
+			errs = append(errs, fmt.Errorf("Parent job %s: %w", id, err))
+		}
+	}
+	if err := j.checkDependencyCycle(cache); err != nil {
+		errs = append(errs, err)
+	}
+	if j.OnFailureJob != "" {
+		if _, err := cache.Get(j.OnFailureJob); err != nil {
+			errs = append(errs, fmt.Errorf("On failure job %s: %w", j.OnFailureJob, err))
+		}
+	}
+	return errs
 }
 
 func (j *Job) SetClock(clk clock.Clock) {
@@ -590,3 +1452,93 @@ func (j *Job) MarshalJSON() ([]byte, error) {
 	defer j.lock.RUnlock()
 	return json.Marshal(RJob(*j)) //nolint:govet // Copying the lock is okay here
 }
+
+// storedWebhook is a Webhook that keeps its secret when marshaled.
+type storedWebhook Webhook
+
+// StorageJSON returns the JSON of the job for job databases to save. Unlike
+// MarshalJSON, it keeps the secrets of the job's webhooks.
+func (j *Job) StorageJSON() ([]byte, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	hooks := make([]*storedWebhook, len(j.Webhooks))
+	for i, w := range j.Webhooks {
+		hooks[i] = (*storedWebhook)(w)
+	}
+	return json.Marshal(struct {
+		*RJob
+		Webhooks []*storedWebhook `json:"webhooks,omitempty"`
+	}{(*RJob)(j), hooks})
+}
+
+// Fields of a job that are about how its runs have gone, rather than what it
+// is set up to do. Whether it is disabled isn't one of them, so that disabling
+// or enabling a job through one node reaches the others.
+var jobStateFields = []string{"stats", "metadata", "next_run_at", "skipped_runs", "is_done"}
+
+// definitionChanged returns whether what the job is set up to do differs from
+// the other job, e.g. because it was edited through another node.
+func (j *Job) definitionChanged(other *Job) bool {
+	a, err := j.definition()
+	if err != nil {
+		return true
+	}
+	b, err := other.definition()
+	if err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(a, b)
+}
+
+// definition returns the job as decoded JSON, without its state or the fields
+// left empty.
+func (j *Job) definition() (interface{}, error) {
+	b, err := j.StorageJSON()
+	if err != nil {
+		return nil, err
+	}
+	var def map[string]interface{}
+	if err := json.Unmarshal(b, &def); err != nil {
+		return nil, err
+	}
+	for _, field := range jobStateFields {
+		delete(def, field)
+	}
+	return withoutEmpty(def), nil
+}
+
+// withoutEmpty drops the empty values from decoded JSON, so that fields left
+// out and fields set to their zero value, as databases may load them, compare
+// the same.
+func withoutEmpty(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e = withoutEmpty(e); e == nil {
+				delete(v, k)
+			} else {
+				v[k] = e
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	case float64:
+		if v == 0 {
+			return nil
+		}
+	}
+	return v
+}
@@ -26,11 +26,13 @@ func TestRemoteJobRunner(t *testing.T) {
 	cache := NewMockCache()
 	mockRemoteJob.Init(cache)
 	cache.Start(0, 2*time.Second) // Retain 1 minute
+	waitForJob(mockRemoteJob)
 
 	mockRemoteJob.Run(cache)
 
+	// Once when it was added, and once more.
 	mockRemoteJob.lock.RLock()
-	assert.True(t, mockRemoteJob.Metadata.SuccessCount == 1)
+	assert.True(t, mockRemoteJob.Metadata.SuccessCount == 2)
 	mockRemoteJob.lock.RUnlock()
 }
 
@@ -66,10 +68,12 @@ func TestRemoteJobBadStatusSuccess(t *testing.T) {
 	cache := NewMockCache()
 	mockRemoteJob.Init(cache)
 	cache.Start(0, 2*time.Second) // Retain 1 minute
+	waitForJob(mockRemoteJob)
 
 	mockRemoteJob.Run(cache)
 
+	// Once when it was added, and once more.
 	mockRemoteJob.lock.Lock()
-	assert.True(t, mockRemoteJob.Metadata.SuccessCount == 1)
+	assert.True(t, mockRemoteJob.Metadata.SuccessCount == 2)
 	mockRemoteJob.lock.Unlock()
 }
@@ -4,6 +4,7 @@ package job
 import (
 	"time"
 
+	"github.com/mixer/clock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
@@ -80,3 +81,125 @@ func TestGetWatiDuration(t *testing.T) {
 		assert.InDelta(t, float64(testStruct.ExpectedDuration), float64(actualDuration), float64(time.Millisecond*50), "Test of "+testStruct.Name)
 	}
 }
+
+func TestGetWaitDurationWithJitter(t *testing.T) {
+	j := &Job{
+		Schedule: "R/2015-10-17T11:44:54.389361-07:00/PT1H",
+		Jitter:   "PT10M",
+		Metadata: Metadata{
+			LastAttemptedRun: time.Now(),
+		},
+	}
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	for i := 0; i < 20; i++ {
+		actualDuration := j.GetWaitDuration()
+		assert.True(t, actualDuration >= time.Hour-time.Second, "waits %s", actualDuration)
+		assert.True(t, actualDuration < time.Hour+10*time.Minute, "waits %s", actualDuration)
+	}
+}
+
+func TestGetWaitDurationWithJitterSpread(t *testing.T) {
+	newJob := func(id string) *Job {
+		j := &Job{
+			Id:           id,
+			Schedule:     "R/2015-10-17T11:44:54.389361-07:00/PT1H",
+			Jitter:       "PT10M",
+			JitterSpread: true,
+		}
+		assert.NoError(t, j.InitDelayDuration(false))
+		return j
+	}
+
+	// The offset is the same for the same job, and differs between jobs.
+	a, b := newJob("a"), newJob("b")
+	assert.Equal(t, a.nextJitter(), newJob("a").nextJitter())
+	assert.NotEqual(t, a.nextJitter(), b.nextJitter())
+	assert.True(t, a.nextJitter() < 10*time.Minute)
+
+	// The offset doesn't add up from run to run: having run at its jittered
+	// time, the job waits exactly one interval.
+	a.Metadata.LastAttemptedRun = time.Now()
+	assert.InDelta(t, float64(time.Hour), float64(a.GetWaitDuration()), float64(time.Millisecond*50))
+}
+
+func TestStartWaitingSetsJitteredNextRunAt(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Id = "a"
+	j.Jitter = "PT10M"
+	j.JitterSpread = true
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	j.StartWaiting(cache, false)
+	defer j.StopTimer()
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.WithinDuration(t, j.scheduleTime.Add(j.nextJitter()), j.NextRunAt, time.Second)
+	assert.Equal(t, j.nextJitter(), j.jitterOffset)
+}
+
+func TestNextRuns(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	j := &Job{Schedule: "R2/2026-01-15T10:00:00Z/P1M"}
+	j.clk.SetClock(clock.NewMockClock(now))
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	// Three runs in all: the first, and two repetitions.
+	assert.Equal(t, []time.Time{
+		time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, time.February, 15, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, time.March, 15, 10, 0, 0, 0, time.UTC),
+	}, j.NextRuns(10))
+	assert.Len(t, j.NextRuns(2), 2)
+
+	// Runs already done count towards the repetitions.
+	j.Stats = []*JobStat{{}}
+	assert.Len(t, j.NextRuns(10), 2)
+
+	j.Disabled = true
+	assert.Empty(t, j.NextRuns(10))
+}
+
+func TestNextRunsResumeAtNextScheduledTime(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	j := &Job{Schedule: "R/2026-01-01T10:30:00Z/PT1H", ResumeAtNextScheduledTime: true}
+	j.clk.SetClock(clock.NewMockClock(now))
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	assert.Equal(t, []time.Time{
+		now.Add(30 * time.Minute),
+		now.Add(90 * time.Minute),
+	}, j.NextRuns(2))
+}
+
+func TestNextRunsSkipsCalendars(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := &Calendar{Name: "holidays", Dates: []string{"2026-01-02"}}
+	assert.NoError(t, c.Init())
+	j := &Job{Schedule: "R/2026-01-01T10:00:00Z/P1D", calendars: []*Calendar{c}}
+	j.clk.SetClock(clock.NewMockClock(now))
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	assert.Equal(t, []time.Time{
+		time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, time.January, 3, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, time.January, 4, 10, 0, 0, 0, time.UTC),
+	}, j.NextRuns(3))
+}
+
+func TestNextRunsStartsAtPendingRun(t *testing.T) {
+	cache := NewMockCache()
+	cache.Clock.SetClock(clock.NewMockClock(time.Now()))
+	j := GetMockRecurringJobWithSchedule(cache.Time().Now().Add(time.Hour), "PT1H")
+	j.Jitter = "PT10M"
+	assert.NoError(t, j.Init(cache))
+
+	runs := j.NextRuns(2)
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Equal(t, j.NextRunAt, runs[0])
+	// The next run's random jitter is independent of this one's.
+	assert.WithinDuration(t, j.scheduleTime.Add(time.Hour), runs[1], 10*time.Minute)
+}
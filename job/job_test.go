@@ -134,6 +134,50 @@ func TestBrokenDelayHandling(t *testing.T) {
 	}
 }
 
+func TestBrokenMisfirePolicy(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Minute), "PT1H")
+	j.MisfirePolicy = "sometimes"
+
+	err := j.Init(cache)
+	assert.ErrorIs(t, err, ErrInvalidMisfirePolicy)
+}
+
+func TestBrokenMisfireThreshold(t *testing.T) {
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Minute), "PT1H")
+	j.MisfireThreshold = "10 minutes"
+
+	assert.Error(t, j.InitDelayDuration(true))
+}
+
+func TestValidateReturnsAllErrors(t *testing.T) {
+	cache := NewMockCache()
+	j := &Job{
+		Schedule:           "R/tomorrow/PT1H",
+		MisfirePolicy:      "sometimes",
+		TemplateDelimiters: "{{ }}",
+		Command:            "echo {{ .Name",
+		ParentJobs:         []string{"missing"},
+	}
+
+	errs := j.Validate(cache)
+	assert.Len(t, errs, 5)
+	assert.ErrorIs(t, errs[0], ErrInvalidJob)
+	assert.ErrorIs(t, errs[1], ErrInvalidMisfirePolicy)
+	assert.ErrorIs(t, errs[4], ErrJobDoesntExist)
+
+	// Nothing was added to the cache or started.
+	assert.Empty(t, j.Id)
+	assert.Empty(t, cache.GetAll().Jobs)
+	assert.Nil(t, j.jobTimer)
+}
+
+func TestValidateSkipsCacheChecksWithoutCache(t *testing.T) {
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Minute), "PT1H")
+	j.ParentJobs = []string{"missing"}
+	assert.Empty(t, j.Validate(nil))
+}
+
 func TestJobInit(t *testing.T) {
 	cache := NewMockCache()
 
@@ -349,6 +393,7 @@ func TestDependentJobsSimple(t *testing.T) {
 	assert.Equal(t, j.DependentJobs[0], mockChildJob.Id)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 2)
 	briefPause()
 	n := clk.Now()
@@ -414,6 +459,7 @@ func TestDependentJobsTwoChilds(t *testing.T) {
 	assert.True(t, len(j.DependentJobs) == 2)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 2)
 	briefPause()
 	n := clk.Now()
@@ -423,8 +469,10 @@ func TestDependentJobsTwoChilds(t *testing.T) {
 	assert.WithinDuration(t, mockChildJobTwo.Metadata.LastAttemptedRun, n, 4*time.Second)
 	assert.WithinDuration(t, mockChildJobTwo.Metadata.LastSuccess, n, 4*time.Second)
 
-	// Test the fact that the dependent jobs follow a rule of FIFO
-	assert.True(t, mockChildJobOne.Metadata.LastAttemptedRun.UnixNano() < mockChildJobTwo.Metadata.LastAttemptedRun.UnixNano())
+	// Dependent jobs run side by side, both triggered by the same run of the parent
+	assert.Equal(t, mockJob.Id, mockChildJobOne.Stats[0].TriggeredBy)
+	assert.Equal(t, mockJob.Stats[0].Id, mockChildJobOne.Stats[0].TriggeredByRun)
+	assert.Equal(t, mockJob.Stats[0].Id, mockChildJobTwo.Stats[0].TriggeredByRun)
 }
 
 // Parent with child with two childs.
@@ -482,6 +530,7 @@ func TestDependentJobsChildWithTwoChilds(t *testing.T) {
 	assert.True(t, len(c.DependentJobs) == 2)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 2)
 	briefPause()
 	n := clk.Now()
@@ -497,8 +546,10 @@ func TestDependentJobsChildWithTwoChilds(t *testing.T) {
 	assert.True(t, mockChildJob.Metadata.LastAttemptedRun.UnixNano() < mockChildJobOne.Metadata.LastAttemptedRun.UnixNano())
 	assert.True(t, mockChildJob.Metadata.LastAttemptedRun.UnixNano() < mockChildJobTwo.Metadata.LastAttemptedRun.UnixNano())
 
-	// Test the fact that the dependent jobs follow a rule of FIFO
-	assert.True(t, mockChildJobOne.Metadata.LastAttemptedRun.UnixNano() < mockChildJobTwo.Metadata.LastAttemptedRun.UnixNano())
+	// Dependent jobs run side by side, both triggered by the same run of the parent
+	assert.Equal(t, mockChildJob.Id, mockChildJobOne.Stats[0].TriggeredBy)
+	assert.Equal(t, mockChildJob.Stats[0].Id, mockChildJobOne.Stats[0].TriggeredByRun)
+	assert.Equal(t, mockChildJob.Stats[0].Id, mockChildJobTwo.Stats[0].TriggeredByRun)
 }
 
 // Parent with a chain of length 5
@@ -585,6 +636,7 @@ func TestDependentJobsFiveChain(t *testing.T) {
 	assert.True(t, len(cFour.DependentJobs) == 1)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 2)
 	briefPause()
 	n := clk.Now()
@@ -665,6 +717,7 @@ func TestDependentJobsChainWithFailingJob(t *testing.T) {
 	assert.True(t, len(cTwo.DependentJobs) == 1)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second)
 	briefPause()
 	n := clk.Now()
@@ -763,6 +816,7 @@ func TestDependentJobsFiveChainWithSlowJob(t *testing.T) {
 	assert.True(t, len(cFour.DependentJobs) == 1)
 
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 3)
 	briefPause()
 	n := clk.Now()
@@ -827,6 +881,7 @@ func TestDependentJobsTwoParentsSameChild(t *testing.T) {
 	assert.True(t, len(parentTwo.DependentJobs) == 1)
 
 	parentOne.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second)
 	briefPause()
 	n := clk.Now()
@@ -841,6 +896,7 @@ func TestDependentJobsTwoParentsSameChild(t *testing.T) {
 	clk.AddTime(time.Second * 3)
 	briefPause()
 	parentTwo.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second)
 	briefPause()
 	n = clk.Now()
@@ -918,6 +974,7 @@ func TestDependentJobsChildGetsDisabled(t *testing.T) {
 
 	n := clk.Now()
 	j.Run(cache)
+	waitForWorkflowRun(t, cache)
 	clk.AddTime(time.Second * 2)
 	briefPause()
 
@@ -1129,3 +1186,114 @@ func TestOnFailureJobDoesntTriggerOnSuccess(t *testing.T) {
 	onFailureJob.lock.RUnlock()
 	j.lock.RUnlock()
 }
+
+func TestAtMostOnceRequiresLeases(t *testing.T) {
+	cache := NewMockCache()
+
+	j := GetMockJobWithGenericSchedule(time.Now())
+	j.AtMostOnce = true
+
+	assert.Equal(t, ErrLeasesUnsupported, j.Init(cache))
+}
+
+func TestAtMostOnceJobRunsOnceAcrossNodes(t *testing.T) {
+	db := NewMemoryDB()
+	scheduleTime := time.Now().Add(time.Second)
+
+	jobs := []*Job{}
+	for i := 0; i < 2; i++ {
+		cache := NewLockFreeJobCache(db)
+		j := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+		j.Id = "at-most-once"
+		j.AtMostOnce = true
+		j.succeedInstantly = true
+		assert.NoError(t, j.InitDelayDuration(false))
+		assert.NoError(t, cache.Set(j))
+		j.StartWaiting(cache, false)
+		jobs = append(jobs, j)
+	}
+
+	time.Sleep(time.Second * 2)
+
+	runs := 0
+	for _, j := range jobs {
+		j.lock.RLock()
+		runs += int(j.Metadata.SuccessCount)
+		j.lock.RUnlock()
+	}
+	assert.Equal(t, 1, runs)
+}
+
+func TestAtMostOnceJitteredJobRunsOnceAcrossNodes(t *testing.T) {
+	db := NewMemoryDB()
+	scheduleTime := time.Now().Add(time.Second)
+
+	// Each node jitters the runs differently, so some of the jobs are all
+	// but sure to run at different times on the two nodes.
+	jobs := map[string][]*Job{}
+	for i := 0; i < 2; i++ {
+		cache := NewLockFreeJobCache(db)
+		for k := 0; k < 5; k++ {
+			j := GetMockRecurringJobWithSchedule(scheduleTime, "PT1H")
+			j.Id = fmt.Sprintf("at-most-once-%d", k)
+			j.AtMostOnce = true
+			j.Jitter = "PT2S"
+			j.succeedInstantly = true
+			assert.NoError(t, j.InitDelayDuration(false))
+			assert.NoError(t, cache.Set(j))
+			j.StartWaiting(cache, false)
+			jobs[j.Id] = append(jobs[j.Id], j)
+		}
+	}
+
+	time.Sleep(time.Second * 4)
+
+	for id, nodes := range jobs {
+		runs := 0
+		for _, j := range nodes {
+			j.StopTimer()
+			j.lock.RLock()
+			runs += int(j.Metadata.SuccessCount)
+			j.lock.RUnlock()
+		}
+		assert.Equal(t, 1, runs, id)
+	}
+}
+
+func TestNearestSchedulePoint(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	j := GetMockRecurringJobWithSchedule(start, "PT1M")
+	assert.NoError(t, j.InitDelayDuration(false))
+
+	// Far from the start of the schedule, without walking to it.
+	point := start.Add(time.Minute * 3000000)
+	assert.Equal(t, point, j.nearestSchedulePoint(point.Add(20*time.Second)))
+	assert.Equal(t, point, j.nearestSchedulePoint(point.Add(-20*time.Second)))
+	assert.Equal(t, start.Add(-time.Minute*10), j.nearestSchedulePoint(start.Add(-time.Minute*10)))
+
+	monthly := GetMockRecurringJobWithSchedule(start, "P1M")
+	assert.NoError(t, monthly.InitDelayDuration(false))
+	assert.Equal(t, start.AddDate(0, 100, 0), monthly.nearestSchedulePoint(start.AddDate(0, 100, 2)))
+}
+
+func TestRunWithParams(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Retries = 0
+	j.TemplateDelimiters = "{{ }}"
+	j.Command = `bash -c 'test "$(printenv KALA_PARAM_DATE)" = {{ .Params.date }}'`
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+	command := j.Command
+
+	params := map[string]interface{}{"date": "2026-10-01"}
+	j.RunWithParams(cache, params)
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if assert.Len(t, j.Stats, 1) {
+		assert.True(t, j.Stats[0].Success)
+		assert.Equal(t, params, j.Stats[0].Params)
+	}
+	assert.Equal(t, command, j.Command)
+}
@@ -0,0 +1,256 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+// MaxJobRuns is how many runs are kept for looking up and cancelling while
+// they are in progress. Finished runs can still be looked up from the job's
+// stats.
+const MaxJobRuns = 1000
+
+var (
+	ErrJobRunDoesntExist = errors.New("The run you requested does not exist")
+	ErrJobNotStarted     = errors.New("Job cannot run, as kala is shutting down")
+	ErrJobRunFinished    = errors.New("The run has already finished")
+)
+
+// RunStatus is the state of a run of a job.
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+	// The remote job's request timed out.
+	RunTimedOut RunStatus = "timed_out"
+	// The run was cancelled while in progress.
+	RunCancelled RunStatus = "cancelled"
+	// The job didn't run, because it is disabled or deleted, or kala is
+	// shutting down. Skipped runs aren't recorded in the job's stats.
+	RunSkipped RunStatus = "skipped"
+)
+
+// JobRun is a run of a job, which can be followed and cancelled while it is in
+// progress.
+type JobRun struct {
+	Id         string    `json:"id"`
+	JobId      string    `json:"job_id"`
+	Status     RunStatus `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// The point of the schedule the run is for, as in its stats.
+	LogicalTime time.Time `json:"logical_time"`
+
+	// The stats of the run, once it has finished, if the job got to run.
+	Stat *JobStat `json:"stat,omitempty"`
+	// Why the run failed or was skipped.
+	Error string `json:"error,omitempty"`
+
+	lock      sync.RWMutex
+	done      chan struct{}
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// jobRunTracker is implemented by caches that keep runs for looking up and
+// cancelling.
+type jobRunTracker interface {
+	addJobRun(r *JobRun)
+	getJobRun(id string) (*JobRun, bool)
+}
+
+// Start runs the job in the background with the given parameters, and returns
+// the run straight away so that it can be followed. The run's id is also the
+// id of its stats.
+func (j *Job) Start(cache JobCache, params map[string]interface{}) (*JobRun, error) {
+	return j.start(cache, runOptions{params: params})
+}
+
+func (j *Job) start(cache JobCache, opts runOptions) (*JobRun, error) {
+	run, err := newJobRun(j)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.logicalTime.IsZero() {
+		run.LogicalTime = opts.logicalTime
+	}
+	opts.scheduledAt = run.StartedAt
+	opts.jobRun = run
+	opts.ctx = context.Background()
+
+	t, tracked := cache.(runTracker)
+	if tracked {
+		var accepted bool
+		if opts.ctx, accepted = t.startRun(); !accepted {
+			j.logger().Info("Job not started, as kala is shutting down.")
+			run.finish(run.StartedAt, nil, ErrJobNotStarted)
+			return run, nil
+		}
+	}
+	trackJobRun(cache, run)
+	go func() {
+		if tracked {
+			defer t.finishRun()
+		}
+		j.run(cache, opts)
+	}()
+	return run, nil
+}
+
+// GetRun looks up a run of the job by id, either one kept by the cache or one
+// recorded in the job's stats.
+func (j *Job) GetRun(cache JobCache, id string) (*JobRun, error) {
+	if k, ok := cache.(jobRunTracker); ok {
+		if run, ok := k.getJobRun(id); ok && run.JobId == j.Id {
+			return run, nil
+		}
+	}
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	for _, stat := range j.Stats {
+		if stat.Id == id {
+			return newFinishedJobRun(stat), nil
+		}
+	}
+	return nil, ErrJobRunDoesntExist
+}
+
+func newJobRun(j *Job) (*JobRun, error) {
+	u4, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	now := j.clk.Time().Now()
+	return &JobRun{
+		Id:          u4.String(),
+		JobId:       j.Id,
+		Status:      RunRunning,
+		StartedAt:   now,
+		LogicalTime: now,
+		done:        make(chan struct{}),
+	}, nil
+}
+
+func trackJobRun(cache JobCache, run *JobRun) {
+	if k, ok := cache.(jobRunTracker); ok {
+		k.addJobRun(run)
+	}
+}
+
+func newFinishedJobRun(stat *JobStat) *JobRun {
+	run := &JobRun{
+		Id:          stat.Id,
+		JobId:       stat.JobId,
+		Status:      stat.status(),
+		StartedAt:   stat.RanAt,
+		FinishedAt:  stat.RanAt.Add(stat.ExecutionDuration),
+		LogicalTime: stat.LogicalTime,
+		Stat:        stat,
+		done:        make(chan struct{}),
+	}
+	close(run.done)
+	return run
+}
+
+// finish records how the run went. It does nothing if r is nil.
+func (r *JobRun) finish(now time.Time, stat *JobStat, err error) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.Status = runStatus(stat, err)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	r.Stat = stat
+	r.FinishedAt = now
+	close(r.done)
+}
+
+// runStatus returns the status of a run that finished with the given stats, if
+// it got to run, and error.
+func runStatus(stat *JobStat, err error) RunStatus {
+	switch {
+	case stat != nil && stat.Status != "":
+		return stat.Status
+	case err == nil:
+		return RunSucceeded
+	case errors.Is(err, ErrJobDisabled), errors.Is(err, ErrJobDeleted), errors.Is(err, ErrJobNotStarted):
+		return RunSkipped
+	case errors.Is(err, ErrJobCancelled):
+		return RunCancelled
+	default:
+		return RunFailed
+	}
+}
+
+// withCancel returns the context the run should run with, derived from ctx,
+// which is cancelled when the run is. The caller must call the returned
+// function once the run has finished. It does nothing if r is nil.
+func (r *JobRun) withCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if r == nil {
+		return ctx, cancel
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cancel = cancel
+	if r.cancelled {
+		cancel()
+	}
+	return ctx, cancel
+}
+
+// Cancel stops the run: a remote job's request is cancelled, and a local job's
+// process is killed along with any processes it started. The run isn't
+// retried, and its on failure job doesn't run. It returns ErrJobRunFinished if
+// the run has already finished.
+func (r *JobRun) Cancel() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.done:
+		return ErrJobRunFinished
+	default:
+	}
+	r.cancelled = true
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+// isCancelled reports whether Cancel was called. It is false if r is nil.
+func (r *JobRun) isCancelled() bool {
+	if r == nil {
+		return false
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cancelled
+}
+
+// Done returns a channel that is closed once the run has finished.
+func (r *JobRun) Done() <-chan struct{} {
+	return r.done
+}
+
+// MarshalJSON locks the run while it is marshaled.
+func (r *JobRun) MarshalJSON() ([]byte, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	type alias JobRun
+	return json.Marshal((*alias)(r))
+}
@@ -0,0 +1,229 @@
+package job
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobStart(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "sleep 0.2"
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	start := time.Now()
+	run, err := j.Start(cache, map[string]interface{}{"date": "2026-10-01"})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, start, time.Now(), 100*time.Millisecond)
+	assert.Equal(t, RunRunning, run.Status)
+
+	found, err := j.GetRun(cache, run.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, run, found)
+
+	select {
+	case <-run.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't finish")
+	}
+	assert.Equal(t, RunSucceeded, run.Status)
+	if assert.NotNil(t, run.Stat) {
+		assert.Equal(t, run.Id, run.Stat.Id)
+		assert.Equal(t, "2026-10-01", run.Stat.Params["date"])
+	}
+
+	_, err = j.GetRun(cache, "not-a-run")
+	assert.ErrorIs(t, err, ErrJobRunDoesntExist)
+}
+
+func TestJobGetRunFromStats(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	stat := NewJobStat(j.Id)
+	stat.ExecutionDuration = time.Second
+	j.Stats = []*JobStat{stat}
+
+	run, err := j.GetRun(cache, stat.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, RunFailed, run.Status)
+	assert.Equal(t, stat.RanAt.Add(time.Second), run.FinishedAt)
+	assert.Equal(t, stat, run.Stat)
+}
+
+func TestJobStartWhileShuttingDown(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	assert.NoError(t, cache.Shutdown(context.Background()))
+	run, err := j.Start(cache, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, RunSkipped, run.Status)
+	assert.Equal(t, ErrJobNotStarted.Error(), run.Error)
+}
+
+func TestJobRunCancel(t *testing.T) {
+	cache := NewMockCache()
+	onFailure := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, onFailure.Init(cache))
+	defer onFailure.StopTimer()
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	// The shell's child keeps the output open, unless it is killed too.
+	j.Command = "bash -c 'sleep 30; true'"
+	j.Retries = 2
+	j.OnFailureJob = onFailure.Id
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	run, err := j.Start(cache, nil)
+	assert.NoError(t, err)
+	briefPause()
+	assert.NoError(t, run.Cancel())
+
+	select {
+	case <-run.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run wasn't cancelled")
+	}
+	assert.Equal(t, RunCancelled, run.Status)
+	if assert.NotNil(t, run.Stat) {
+		assert.Equal(t, RunCancelled, run.Stat.Status)
+		assert.Equal(t, uint(0), run.Stat.NumberOfRetries)
+	}
+	assert.ErrorIs(t, run.Cancel(), ErrJobRunFinished)
+
+	wf := waitForWorkflowRun(t, cache)
+	assert.Equal(t, WorkflowCancelled, wf.Status)
+	assert.Equal(t, WorkflowSkipped, wf.Jobs[onFailure.Id].Status)
+	onFailure.lock.RLock()
+	assert.Empty(t, onFailure.Stats)
+	onFailure.lock.RUnlock()
+}
+
+func TestJobRunCancelRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	cache := NewMockCache()
+	j := GetMockRemoteJob(RemoteProperties{Url: srv.URL})
+	j.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	run, err := j.Start(cache, nil)
+	assert.NoError(t, err)
+	briefPause()
+	assert.NoError(t, run.Cancel())
+
+	select {
+	case <-run.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run wasn't cancelled")
+	}
+	assert.Equal(t, RunCancelled, run.Status)
+}
+
+func TestJobRunRecordsAttempts(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "bash -c 'exit 3'"
+	j.Retries = 1
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	run, err := j.Start(cache, nil)
+	assert.NoError(t, err)
+	select {
+	case <-run.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't finish")
+	}
+
+	assert.Equal(t, RunFailed, run.Status)
+	if !assert.NotNil(t, run.Stat) {
+		return
+	}
+	stat := run.Stat
+	assert.Equal(t, RunFailed, stat.Status)
+	assert.Equal(t, SourceManual, stat.TriggerSource)
+	assert.Equal(t, run.StartedAt, stat.ScheduledAt)
+	assert.NotEmpty(t, stat.Error)
+	if assert.Len(t, stat.Attempts, 2) {
+		for _, attempt := range stat.Attempts {
+			assert.NotEmpty(t, attempt.Error)
+			if assert.NotNil(t, attempt.ExitCode) {
+				assert.Equal(t, 3, *attempt.ExitCode)
+			}
+		}
+		assert.False(t, stat.Attempts[1].StartedAt.Before(stat.Attempts[0].StartedAt))
+	}
+}
+
+func TestJobRunTimedOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	cache := NewMockCache()
+	j := GetMockRemoteJob(RemoteProperties{Url: srv.URL, Timeout: 1})
+	j.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	run, err := j.Start(cache, nil)
+	assert.NoError(t, err)
+	select {
+	case <-run.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't time out")
+	}
+	assert.Equal(t, RunTimedOut, run.Status)
+	if assert.NotNil(t, run.Stat) && assert.Len(t, run.Stat.Attempts, 1) {
+		assert.Equal(t, RunTimedOut, run.Stat.Status)
+		assert.NotEmpty(t, run.Stat.Attempts[0].Error)
+		assert.Zero(t, run.Stat.Attempts[0].StatusCode)
+	}
+}
+
+func TestJobRunTriggerSource(t *testing.T) {
+	cache := NewMockCache()
+	parent := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	parent.succeedInstantly = true
+	assert.NoError(t, parent.Init(cache))
+	defer parent.StopTimer()
+
+	child := GetMockJob()
+	child.succeedInstantly = true
+	child.ParentJobs = []string{parent.Id}
+	assert.NoError(t, child.Init(cache))
+
+	parent.Run(cache)
+	waitForWorkflowRun(t, cache)
+
+	child.lock.RLock()
+	defer child.lock.RUnlock()
+	if assert.Len(t, child.Stats, 1) {
+		assert.Equal(t, SourceDependency, child.Stats[0].TriggerSource)
+		assert.Equal(t, parent.Id, child.Stats[0].TriggeredBy)
+	}
+	parent.lock.RLock()
+	defer parent.lock.RUnlock()
+	if assert.Len(t, parent.Stats, 1) {
+		assert.Equal(t, SourceManual, parent.Stats[0].TriggerSource)
+		assert.Len(t, parent.Stats[0].Attempts, 1)
+	}
+}
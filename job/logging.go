@@ -0,0 +1,54 @@
+package job
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Fields of the log entries about jobs and their runs, for finding everything
+// logged about a job or a run.
+const (
+	LogFieldJobId    = "job_id"
+	LogFieldJobName  = "job_name"
+	LogFieldOwner    = "owner"
+	LogFieldRunId    = "run_id"
+	LogFieldAttempt  = "attempt"
+	LogFieldDuration = "duration"
+)
+
+// logger returns a log entry with the fields that identify the job.
+func (j *Job) logger() *log.Entry {
+	fields := log.Fields{
+		LogFieldJobId:   j.Id,
+		LogFieldJobName: j.Name,
+	}
+	if j.Owner != "" {
+		fields[LogFieldOwner] = j.Owner
+	}
+	return log.WithFields(fields)
+}
+
+// logger returns a log entry with the fields that identify the job, the run,
+// and the attempt in progress or last made, if any.
+func (j *JobRunner) logger() *log.Entry {
+	fields := log.Fields{}
+	if j.jobRun != nil {
+		fields[LogFieldRunId] = j.jobRun.Id
+	}
+	if j.attempt > 0 {
+		fields[LogFieldAttempt] = j.attempt
+	}
+	if j.workflowRunId != "" {
+		fields["workflow_run_id"] = j.workflowRunId
+	}
+	if j.backfillId != "" {
+		fields["backfill_id"] = j.backfillId
+	}
+	return j.job.logger().WithFields(fields)
+}
+
+// logDuration returns the log field for how long something took, in seconds.
+func logDuration(d time.Duration) log.Fields {
+	return log.Fields{LogFieldDuration: d.Seconds()}
+}
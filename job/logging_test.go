@@ -0,0 +1,98 @@
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// entriesOf returns the log entries about the job.
+func entriesOf(hook *test.Hook, j *Job) []*log.Entry {
+	entries := []*log.Entry{}
+	for _, e := range hook.AllEntries() {
+		if e.Data[LogFieldJobId] == j.Id {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// findEntry returns the first of the entries whose message starts with prefix.
+func findEntry(entries []*log.Entry, prefix string) *log.Entry {
+	for _, e := range entries {
+		if strings.HasPrefix(e.Message, prefix) {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestRunLogFields(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Owner = "aj@ajvb.me"
+	j.Command = "false"
+	j.Retries = 1
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	j.lock.RLock()
+	runId := j.Stats[0].Id
+	j.lock.RUnlock()
+
+	entries := entriesOf(hook, j)
+	started := findEntry(entries, "Job started.")
+	if assert.NotNil(t, started) {
+		assert.Equal(t, j.Name, started.Data[LogFieldJobName])
+		assert.Equal(t, "aj@ajvb.me", started.Data[LogFieldOwner])
+		assert.Equal(t, runId, started.Data[LogFieldRunId])
+	}
+
+	attempts := []*log.Entry{}
+	for _, e := range entries {
+		if e.Message == "Job attempt failed." {
+			attempts = append(attempts, e)
+		}
+	}
+	if assert.Len(t, attempts, 2) {
+		for i, e := range attempts {
+			assert.Equal(t, i+1, e.Data[LogFieldAttempt])
+			assert.Equal(t, runId, e.Data[LogFieldRunId])
+			assert.Contains(t, e.Data, LogFieldDuration)
+			assert.Contains(t, e.Data, log.ErrorKey)
+		}
+	}
+
+	failed := findEntry(entries, "Job failed.")
+	if assert.NotNil(t, failed) {
+		assert.Equal(t, log.ErrorLevel, failed.Level)
+		assert.Equal(t, 2, failed.Data[LogFieldAttempt])
+		assert.Contains(t, failed.Data, LogFieldDuration)
+	}
+}
+
+func TestJobLogFieldsWithoutRun(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Owner = ""
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	scheduled := findEntry(entriesOf(hook, j), "Job repeating in")
+	if assert.NotNil(t, scheduled) {
+		assert.Equal(t, j.Name, scheduled.Data[LogFieldJobName])
+		assert.NotContains(t, scheduled.Data, LogFieldRunId)
+		assert.NotContains(t, scheduled.Data, LogFieldOwner)
+	}
+}
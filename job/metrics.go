@@ -0,0 +1,128 @@
+package job
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	runsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kala",
+		Name:      "job_runs_total",
+		Help:      "Runs of jobs, by how they went and what started them.",
+	}, []string{"job_id", "job_name", "status", "trigger_source"})
+
+	runDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kala",
+		Name:      "job_run_duration_seconds",
+		Help:      "How long runs of jobs took, retries included.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 10), //nolint:gomnd
+	}, []string{"job_id", "job_name"})
+
+	runRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kala",
+		Name:      "job_run_retries_total",
+		Help:      "Retries of runs of jobs.",
+	}, []string{"job_id", "job_name"})
+
+	scheduleLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kala",
+		Name:      "job_schedule_lag_seconds",
+		Help:      "How long after they were due scheduled runs of jobs started.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10), //nolint:gomnd
+	}, []string{"job_id", "job_name"})
+)
+
+// RunCollectors returns the collectors of the metrics about runs of jobs, for
+// registering with a prometheus registry.
+func RunCollectors() []prometheus.Collector {
+	return []prometheus.Collector{runsTotal, runDuration, runRetries, scheduleLag}
+}
+
+// recordRunMetrics adds a finished run of the job with the given id and name
+// to the metrics.
+func recordRunMetrics(id, name string, stat *JobStat) {
+	runsTotal.WithLabelValues(id, name, string(stat.status()), string(stat.TriggerSource)).Inc()
+	runDuration.WithLabelValues(id, name).Observe(stat.ExecutionDuration.Seconds())
+	runRetries.WithLabelValues(id, name).Add(float64(stat.NumberOfRetries))
+	if stat.TriggerSource == SourceSchedule && !stat.ScheduledAt.IsZero() {
+		lag := stat.RanAt.Sub(stat.ScheduledAt)
+		if lag < 0 {
+			lag = 0
+		}
+		scheduleLag.WithLabelValues(id, name).Observe(lag.Seconds())
+	}
+}
+
+// forgetJobMetrics removes the metrics about runs of a deleted job. It must be
+// called with the job's lock held.
+func forgetJobMetrics(j *Job) {
+	for _, status := range []RunStatus{RunSucceeded, RunFailed, RunTimedOut, RunCancelled} {
+		for _, source := range []TriggerSource{SourceSchedule, SourceManual, SourceDependency, SourceFailure, SourceBackfill} {
+			runsTotal.DeleteLabelValues(j.Id, j.Name, string(status), string(source))
+		}
+	}
+	runDuration.DeleteLabelValues(j.Id, j.Name)
+	runRetries.DeleteLabelValues(j.Id, j.Name)
+	scheduleLag.DeleteLabelValues(j.Id, j.Name)
+}
+
+// runGauges is implemented by caches that can tell how many runs are in
+// progress, and how many are waiting to start.
+type runGauges interface {
+	runsInProgress() int
+	runsPending() int
+}
+
+// cacheCollector collects metrics about the jobs in a cache and their runs.
+type cacheCollector struct {
+	cache JobCache
+
+	jobs       *prometheus.Desc
+	inProgress *prometheus.Desc
+	pending    *prometheus.Desc
+}
+
+// NewCacheCollector returns a collector of the number of jobs in the cache,
+// by whether they are disabled, and of the number of runs in progress and
+// waiting to start, if the cache keeps track of those.
+func NewCacheCollector(cache JobCache) prometheus.Collector {
+	return &cacheCollector{
+		cache: cache,
+		jobs: prometheus.NewDesc("kala_jobs",
+			"Jobs in the cache, by whether they are enabled or disabled.",
+			[]string{"state"}, nil),
+		inProgress: prometheus.NewDesc("kala_runs_in_progress",
+			"Runs of jobs in progress.", nil, nil),
+		pending: prometheus.NewDesc("kala_runs_pending",
+			"Runs of jobs waiting to start, such as backfill runs waiting for a slot.", nil, nil),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.jobs
+	ch <- c.inProgress
+	ch <- c.pending
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	jobs := c.cache.GetAll()
+	jobs.Lock.RLock()
+	var enabled, disabled int
+	for _, j := range jobs.Jobs {
+		j.lock.RLock()
+		if j.Disabled {
+			disabled++
+		} else {
+			enabled++
+		}
+		j.lock.RUnlock()
+	}
+	jobs.Lock.RUnlock()
+	ch <- prometheus.MustNewConstMetric(c.jobs, prometheus.GaugeValue, float64(enabled), "enabled")
+	ch <- prometheus.MustNewConstMetric(c.jobs, prometheus.GaugeValue, float64(disabled), "disabled")
+
+	if g, ok := c.cache.(runGauges); ok {
+		ch <- prometheus.MustNewConstMetric(c.inProgress, prometheus.GaugeValue, float64(g.runsInProgress()))
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(g.runsPending()))
+	}
+}
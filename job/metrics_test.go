@@ -0,0 +1,55 @@
+package job
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMetrics(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "false"
+	j.Retries = 1
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	assert.Equal(t, 1.0, testutil.ToFloat64(runsTotal.WithLabelValues(j.Id, j.Name, "failed", "manual")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(runRetries.WithLabelValues(j.Id, j.Name)))
+	duration := &dto.Metric{}
+	assert.NoError(t, runDuration.WithLabelValues(j.Id, j.Name).(prometheus.Metric).Write(duration))
+	assert.Equal(t, uint64(1), duration.GetHistogram().GetSampleCount())
+
+	assert.NoError(t, cache.Delete(j.Id))
+	assert.Equal(t, 0.0, testutil.ToFloat64(runsTotal.WithLabelValues(j.Id, j.Name, "failed", "manual")))
+}
+
+func TestCacheCollector(t *testing.T) {
+	cache := NewMockCache()
+	enabled := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, enabled.Init(cache))
+	defer enabled.StopTimer()
+	disabled := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, disabled.Init(cache))
+	assert.NoError(t, disabled.Disable(cache))
+
+	expected := `
+# HELP kala_jobs Jobs in the cache, by whether they are enabled or disabled.
+# TYPE kala_jobs gauge
+kala_jobs{state="disabled"} 1
+kala_jobs{state="enabled"} 1
+# HELP kala_runs_in_progress Runs of jobs in progress.
+# TYPE kala_runs_in_progress gauge
+kala_runs_in_progress 0
+# HELP kala_runs_pending Runs of jobs waiting to start, such as backfill runs waiting for a slot.
+# TYPE kala_runs_pending gauge
+kala_runs_pending 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewCacheCollector(cache), strings.NewReader(expected)))
+}
@@ -0,0 +1,168 @@
+package job
+
+import (
+	"time"
+
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+// EventType is something that happened to a job, that notifiers can be told
+// about.
+type EventType string
+
+const (
+	// A run of the job started.
+	EventJobStarted EventType = "job.started"
+	// A run of the job succeeded.
+	EventJobSucceeded EventType = "job.succeeded"
+	// A run of the job failed or timed out, after all its retries.
+	EventJobFailed EventType = "job.failed"
+	// The job was disabled.
+	EventJobDisabled EventType = "job.disabled"
+	// The job ran for the last time its schedule allows.
+	EventScheduleExhausted EventType = "job.schedule_exhausted"
+)
+
+var eventTypes = []EventType{
+	EventJobStarted, EventJobSucceeded, EventJobFailed, EventJobDisabled, EventScheduleExhausted,
+}
+
+func (t EventType) valid() bool {
+	for _, e := range eventTypes {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Output longer than this is cut down to its end in events.
+const maxEventOutputSize = 4 * 1024
+
+// Event is sent to notifiers when something happens to a job.
+type Event struct {
+	Id      string    `json:"id"`
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	JobId   string    `json:"job_id"`
+	JobName string    `json:"job_name"`
+	Owner   string    `json:"owner,omitempty"`
+
+	// The run the event is about, if any.
+	RunId         string        `json:"run_id,omitempty"`
+	TriggerSource TriggerSource `json:"trigger_source,omitempty"`
+
+	// How the run went, once it has finished.
+	Status   RunStatus     `json:"status,omitempty"`
+	Attempts int           `json:"attempts,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	// The end of what the last attempt output.
+	Output string `json:"output,omitempty"`
+
+	// The job's own webhooks, which the event goes to as well as the
+	// notifiers' own.
+	webhooks []*Webhook
+}
+
+// Notifier is told about the events of jobs. Notify must not block, as it is
+// called while jobs run.
+type Notifier interface {
+	Notify(e *Event)
+}
+
+// notifying is implemented by caches that have notifiers to tell about the
+// events of their jobs.
+type notifying interface {
+	notifiers() []Notifier
+}
+
+// notify tells the cache's notifiers about the event, if it has any.
+func notify(cache JobCache, e *Event) {
+	if e == nil {
+		return
+	}
+	if n, ok := cache.(notifying); ok {
+		for _, notifier := range n.notifiers() {
+			notifier.Notify(e)
+		}
+	}
+}
+
+// jobForgetter is implemented by notifiers that keep track of jobs, so that
+// they can let go of the jobs that are deleted.
+type jobForgetter interface {
+	forgetJob(id string)
+}
+
+// forgetJob tells the cache's notifiers that keep track of jobs that the job
+// was deleted.
+func forgetJob(cache JobCache, id string) {
+	if n, ok := cache.(notifying); ok {
+		for _, notifier := range n.notifiers() {
+			if f, ok := notifier.(jobForgetter); ok {
+				f.forgetJob(id)
+			}
+		}
+	}
+}
+
+// event returns a new event about the job. It must be called with the job's
+// lock held.
+func (j *Job) event(t EventType) *Event {
+	u4, err := uuid.NewV4()
+	if err != nil {
+		j.logger().Errorf("Error occurred when generating uuid: %s", err)
+		return nil
+	}
+	return &Event{
+		Id:       u4.String(),
+		Type:     t,
+		Time:     j.clk.Time().Now(),
+		JobId:    j.Id,
+		JobName:  j.Name,
+		Owner:    j.Owner,
+		webhooks: j.Webhooks,
+	}
+}
+
+// event returns a new event about the run, with how it went if it has
+// finished. It must be called with the job's lock held.
+func (j *JobRunner) event(t EventType) *Event {
+	e := j.job.event(t)
+	if e == nil {
+		return nil
+	}
+	e.TriggerSource = j.source
+	if j.jobRun != nil {
+		e.RunId = j.jobRun.Id
+	}
+	if t == EventJobStarted || j.currentStat == nil {
+		return e
+	}
+	e.Status = j.currentStat.status()
+	e.Attempts = len(j.currentStat.Attempts)
+	e.Duration = j.currentStat.ExecutionDuration
+	e.Error = j.currentStat.Error
+	e.Output = j.output
+	if len(e.Output) > maxEventOutputSize {
+		e.Output = e.Output[len(e.Output)-maxEventOutputSize:]
+	}
+	return e
+}
+
+// finishedEvent returns the event for how the run went, or nil if it is one
+// that notifiers aren't told about.
+func (j *JobRunner) finishedEvent() *Event {
+	if j.currentStat == nil {
+		return nil
+	}
+	switch j.currentStat.status() {
+	case RunSucceeded:
+		return j.event(EventJobSucceeded)
+	case RunFailed, RunTimedOut:
+		return j.event(EventJobFailed)
+	default:
+		return nil
+	}
+}
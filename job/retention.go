@@ -0,0 +1,162 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ajvb/kala/utils/iso8601"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrInvalidRetention = errors.New("Invalid retention. Limits can't be negative, and ages must be ISO 8601 durations")
+
+// Retention says which of a job's stats are kept. Stats are removed once they
+// pass any of the limits that are set. Failed runs, which includes ones that
+// timed out or were cancelled, are kept by the Failed limits instead, if any
+// of those are set, so that they can be kept for longer than successful runs.
+type Retention struct {
+	// The most stats kept. The latest are.
+	MaxRuns int `json:"max_runs,omitempty"`
+	// ISO 8601 Duration. Stats of runs that started longer ago than this are
+	// removed.
+	MaxAge string `json:"max_age,omitempty"`
+
+	// The same, for failed runs.
+	MaxFailedRuns int    `json:"max_failed_runs,omitempty"`
+	MaxFailedAge  string `json:"max_failed_age,omitempty"`
+}
+
+// validate checks that the limits make sense.
+func (r *Retention) validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.MaxRuns < 0 || r.MaxFailedRuns < 0 {
+		return ErrInvalidRetention
+	}
+	for _, age := range []string{r.MaxAge, r.MaxFailedAge} {
+		if age == "" {
+			continue
+		}
+		if _, err := iso8601.FromString(age); err != nil {
+			return ErrInvalidRetention
+		}
+	}
+	return nil
+}
+
+// splitsFailures reports whether failed runs are kept by limits of their own.
+func (r *Retention) splitsFailures() bool {
+	return r.MaxFailedRuns != 0 || r.MaxFailedAge != ""
+}
+
+// retentionLimits are the limits that apply to some of a job's stats.
+type retentionLimits struct {
+	maxRuns int
+	// Stats of runs that started before this are removed, if it is set.
+	cutoff time.Time
+}
+
+func newRetentionLimits(maxRuns int, maxAge string, now time.Time) retentionLimits {
+	l := retentionLimits{maxRuns: maxRuns}
+	if maxAge != "" {
+		if d, err := iso8601.FromString(maxAge); err == nil {
+			l.cutoff = now.Add(-d.RelativeTo(now))
+		}
+	}
+	return l
+}
+
+// retain returns the stats that the policy keeps at the given time, in the
+// same order. A nil policy keeps them all.
+func (r *Retention) retain(stats []*JobStat, now time.Time) []*JobStat {
+	if r == nil {
+		return stats
+	}
+	succeeded := newRetentionLimits(r.MaxRuns, r.MaxAge, now)
+	failed := succeeded
+	if r.splitsFailures() {
+		failed = newRetentionLimits(r.MaxFailedRuns, r.MaxFailedAge, now)
+	}
+
+	// Count from the latest run back, as the latest runs are the ones kept.
+	keep := make([]bool, len(stats))
+	var succeededKept, failedKept int
+	for i := len(stats) - 1; i >= 0; i-- {
+		limits, kept := &succeeded, &succeededKept
+		if r.splitsFailures() && stats[i].status() != RunSucceeded {
+			limits, kept = &failed, &failedKept
+		}
+		if limits.maxRuns != 0 && *kept >= limits.maxRuns {
+			continue
+		}
+		if !limits.cutoff.IsZero() && stats[i].RanAt.Before(limits.cutoff) {
+			continue
+		}
+		keep[i] = true
+		*kept++
+	}
+
+	retained := make([]*JobStat, 0, len(stats))
+	for i, s := range stats {
+		if keep[i] {
+			retained = append(retained, s)
+		}
+	}
+	return retained
+}
+
+// applyRetention removes the stats that the job's retention policy, or the
+// given default policy if the job has none, doesn't keep. It reports whether
+// any were removed. It must be called with the job's lock held.
+func (j *Job) applyRetention(defaults *Retention, now time.Time) bool {
+	policy := j.Retention
+	if policy == nil {
+		policy = defaults
+	}
+	retained := policy.retain(j.Stats, now)
+	if len(retained) == len(j.Stats) {
+		return false
+	}
+	j.logger().Infof("Job retention: removing %d stats", len(j.Stats)-len(retained))
+	j.Stats = retained
+	return true
+}
+
+// RetainStats removes the stats that the job's retention policy, or the given
+// default policy if it has none, doesn't keep, and reports whether any were.
+// Job dbs use it to apply retention to the jobs they store.
+func (j *Job) RetainStats(defaults *Retention, now time.Time) bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.applyRetention(defaults, now)
+}
+
+// compactDB applies retention to the jobs stored in db as well, if it is a
+// Compactor.
+func compactDB(db JobDB, defaults *Retention, now time.Time) error {
+	c, ok := db.(Compactor)
+	if !ok {
+		return nil
+	}
+	if err := c.Compact(defaults, now); err != nil {
+		log.WithError(err).Error("Job db couldn't be compacted.")
+		return fmt.Errorf("Compacting the job db: %w", err)
+	}
+	return nil
+}
+
+// retainer is implemented by caches that apply retention to job stats.
+type retainer interface {
+	defaultRetention() *Retention
+}
+
+// defaultRetentionOf returns the cache's default retention policy, if any.
+func defaultRetentionOf(cache JobCache) *Retention {
+	if r, ok := cache.(retainer); ok {
+		return r.defaultRetention()
+	}
+	return nil
+}
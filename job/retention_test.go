@@ -0,0 +1,221 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// retentionStats returns a stat for each hour before now, oldest first, with
+// the given ones failed.
+func retentionStats(now time.Time, hours int, failed ...int) []*JobStat {
+	stats := make([]*JobStat, hours)
+	for i := range stats {
+		stats[i] = &JobStat{
+			Id:      string(rune('a' + i)),
+			RanAt:   now.Add(-time.Duration(hours-i) * time.Hour),
+			Success: true,
+		}
+	}
+	for _, i := range failed {
+		stats[i].Success = false
+	}
+	return stats
+}
+
+func TestRetention(t *testing.T) {
+	now := time.Now()
+	stats := retentionStats(now, 5, 0, 3)
+
+	var policy *Retention
+	assert.Equal(t, stats, policy.retain(stats, now))
+
+	policy = &Retention{MaxRuns: 2}
+	assert.Equal(t, stats[3:], policy.retain(stats, now))
+
+	policy = &Retention{MaxAge: "PT2H30M"}
+	assert.Equal(t, stats[3:], policy.retain(stats, now))
+
+	// Failures are kept for longer than successes.
+	policy = &Retention{MaxRuns: 1, MaxFailedAge: "P1D"}
+	assert.Equal(t, []*JobStat{stats[0], stats[3], stats[4]}, policy.retain(stats, now))
+
+	policy = &Retention{MaxAge: "PT1H30M", MaxFailedRuns: 1}
+	assert.Equal(t, []*JobStat{stats[3], stats[4]}, policy.retain(stats, now))
+}
+
+func TestRetentionValidation(t *testing.T) {
+	j := GetMockJob()
+	j.Retention = &Retention{MaxRuns: -1}
+	assert.ErrorIs(t, j.Init(NewMockCache()), ErrInvalidRetention)
+
+	j = GetMockJob()
+	j.Retention = &Retention{MaxFailedAge: "a week"}
+	assert.ErrorIs(t, j.Init(NewMockCache()), ErrInvalidRetention)
+}
+
+func TestRunAppliesRetention(t *testing.T) {
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Retention = &Retention{MaxRuns: 2}
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	for i := 0; i < 3; i++ {
+		j.Run(cache)
+	}
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Len(t, j.Stats, 2)
+	assert.Equal(t, uint(3), j.Metadata.NumberOfFinishedRuns)
+}
+
+func TestRetentionKeepsCountingRepetitions(t *testing.T) {
+	cache := NewMockCache()
+	cache.DefaultRetention = &Retention{MaxRuns: 1}
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Schedule = "R1" + j.Schedule[1:]
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	j.Run(cache)
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Len(t, j.Stats, 1)
+	assert.False(t, j.ShouldStartWaiting())
+}
+
+type savingMockDB struct {
+	MockDB
+	saved []string
+	// Jobs that can't be saved.
+	failing map[string]bool
+}
+
+var errMockSave = errors.New("mock save failed")
+
+func (d *savingMockDB) Save(j *Job) error {
+	if d.failing[j.Id] {
+		return errMockSave
+	}
+	d.saved = append(d.saved, j.Id)
+	return nil
+}
+
+func TestCacheRetainSavesCompactedJobs(t *testing.T) {
+	now := time.Now()
+	cache := NewMockCache()
+	db := &savingMockDB{}
+	cache.jobDB = db
+	cache.DefaultRetention = &Retention{MaxRuns: 2}
+
+	compacted := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	compacted.Stats = retentionStats(now, 3)
+	assert.NoError(t, compacted.Init(cache))
+	defer compacted.StopTimer()
+	// A job's own retention replaces the default.
+	kept := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	kept.Stats = retentionStats(now, 3)
+	kept.Retention = &Retention{MaxAge: "P1D"}
+	assert.NoError(t, kept.Init(cache))
+	defer kept.StopTimer()
+
+	db.saved = nil
+	assert.NoError(t, cache.Retain())
+	assert.Equal(t, []string{compacted.Id}, db.saved)
+	assert.Len(t, compacted.Stats, 2)
+	assert.Len(t, kept.Stats, 3)
+}
+
+func TestMemoryCacheRetain(t *testing.T) {
+	now := time.Now()
+	db := &savingMockDB{}
+	cache := NewMemoryJobCache(db)
+	cache.DefaultRetention = &Retention{MaxAge: "PT90M"}
+
+	j := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	j.Stats = retentionStats(now, 3)
+	assert.NoError(t, cache.Set(j))
+
+	assert.NoError(t, cache.Retain())
+	assert.Equal(t, []string{j.Id}, db.saved)
+	assert.Len(t, j.Stats, 1)
+}
+
+func TestCacheRetainContinuesPastSaveErrors(t *testing.T) {
+	now := time.Now()
+	db := &savingMockDB{failing: map[string]bool{}}
+	cache := NewMemoryJobCache(db)
+	cache.DefaultRetention = &Retention{MaxRuns: 1}
+
+	failing := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	failing.Id = "failing"
+	failing.Stats = retentionStats(now, 3)
+	assert.NoError(t, cache.Set(failing))
+	saved := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	saved.Id = "saved"
+	saved.Stats = retentionStats(now, 3)
+	assert.NoError(t, cache.Set(saved))
+	db.failing[failing.Id] = true
+
+	err := cache.Retain()
+	assert.True(t, errors.Is(err, errMockSave))
+	assert.Equal(t, []string{saved.Id}, db.saved)
+	assert.Len(t, saved.Stats, 1)
+
+	lockFree := NewMockCache()
+	lockFree.jobDB = db
+	lockFree.DefaultRetention = &Retention{MaxRuns: 1}
+	for _, j := range []*Job{failing, saved} {
+		j.Stats = retentionStats(now, 3)
+		lockFree.jobs.Set(j.Id, j)
+	}
+	db.saved = nil
+	err = lockFree.Retain()
+	assert.True(t, errors.Is(err, errMockSave))
+	assert.Equal(t, []string{saved.Id}, db.saved)
+}
+
+type compactingMockDB struct {
+	savingMockDB
+	compacted []*Retention
+	err       error
+}
+
+func (d *compactingMockDB) Compact(defaults *Retention, now time.Time) error {
+	d.compacted = append(d.compacted, defaults)
+	return d.err
+}
+
+func TestCacheRetainCompactsDB(t *testing.T) {
+	now := time.Now()
+	db := &compactingMockDB{}
+	retention := &Retention{MaxRuns: 1}
+
+	cache := NewMemoryJobCache(db)
+	cache.DefaultRetention = retention
+	j := GetMockRecurringJobWithSchedule(now.Add(time.Hour), "PT1H")
+	j.Stats = retentionStats(now, 3)
+	assert.NoError(t, cache.Set(j))
+	assert.NoError(t, cache.Retain())
+	assert.Equal(t, []*Retention{retention}, db.compacted)
+
+	lockFree := NewMockCache()
+	lockFree.jobDB = db
+	lockFree.DefaultRetention = retention
+	assert.NoError(t, lockFree.Retain())
+	assert.Equal(t, []*Retention{retention, retention}, db.compacted)
+
+	// Jobs are still saved when compacting fails.
+	db.err = errMockSave
+	db.saved = nil
+	j.Stats = retentionStats(now, 3)
+	err := cache.Retain()
+	assert.True(t, errors.Is(err, errMockSave))
+	assert.Equal(t, []string{j.Id}, db.saved)
+}
@@ -3,17 +3,20 @@ package job
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/mattn/go-shellwords"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const HTTP_CODE_OK = 200
@@ -24,6 +27,43 @@ type JobRunner struct {
 	numberOfAttempts uint
 	currentRetries   uint
 	currentStat      *JobStat
+
+	// Cancelled to interrupt the run, e.g. when kala is shutting down.
+	ctx context.Context
+
+	// Set when the run makes up for one missed by the schedule.
+	catchUp bool
+
+	// The workflow run the job is running as part of, if any.
+	workflowRunId string
+	// The parent job, and its run, that triggered the run, if any.
+	triggeredBy, triggeredByRun string
+
+	// How the last attempt exited, if it got that far.
+	exitCode   *int
+	statusCode int
+	output     string
+
+	// Outputs of the parent jobs that ran before this one in the workflow run.
+	parents []*ParentOutput
+
+	// Parameters the run was started with, if any.
+	params map[string]interface{}
+
+	// The run, which the stats get the id of, and which can be cancelled.
+	jobRun *JobRun
+
+	// The backfill the run is part of, if any, and the point of the schedule
+	// it runs for.
+	backfillId  string
+	logicalTime time.Time
+
+	// What started the run, and when it was due to start.
+	source      TriggerSource
+	scheduledAt time.Time
+
+	// Number of the attempt in progress, or of the last one made.
+	attempt int
 }
 
 var (
@@ -32,8 +72,14 @@ var (
 	ErrCmdIsEmpty        = errors.New("Job Command is empty.")
 	ErrJobTypeInvalid    = errors.New("Job Type is not valid.")
 	ErrInvalidDelimiters = errors.New("Job has invalid templating delimiters.")
+	ErrJobInterrupted    = errors.New("Job was interrupted before it finished")
+	ErrJobCancelled      = errors.New("Job was cancelled before it finished")
 )
 
+// Parent outputs and parameters larger than this aren't passed as environment
+// variables, as they could keep the command from starting.
+const maxEnvValueSize = 64 * 1024
+
 // Run calls the appropriate run function, collects metadata around the success
 // or failure of the Job's execution, and schedules the next run.
 func (j *JobRunner) Run(cache JobCache) (*JobStat, Metadata, error) {
@@ -44,21 +90,29 @@ func (j *JobRunner) Run(cache JobCache) (*JobStat, Metadata, error) {
 
 	_, err := cache.Get(j.job.Id)
 	if errors.Is(err, ErrJobDoesntExist) {
-		log.Infof("Job %s with id %s tried to run, but exited early because it has benn deleted", j.job.Name, j.job.Id)
+		j.logger().Info("Job tried to run, but exited early because it has been deleted")
 		return nil, j.meta, ErrJobDeleted
 	}
 	if j.job.Disabled {
-		log.Infof("Job %s tried to run, but exited early because its disabled.", j.job.Name)
+		j.logger().Info("Job tried to run, but exited early because it's disabled.")
 		return nil, j.meta, ErrJobDisabled
 	}
 
-	log.Infof("Job %s:%s started.", j.job.Name, j.job.Id)
+	j.logger().Info("Job started.")
 
 	j.runSetup()
+	notify(cache, j.event(EventJobStarted))
 
 	var out string
 	for {
 		var err error
+		attemptStart := j.job.clk.Time().Now()
+		j.exitCode, j.statusCode = nil, 0
+		j.attempt++
+		// The attempt's command or request runs within the attempt's span.
+		runCtx := j.context()
+		var span trace.Span
+		j.ctx, span = startAttemptSpan(runCtx, j.attempt)
 		switch {
 		case j.job.succeedInstantly:
 			out = "Job succeeded instantly for test purposes."
@@ -69,51 +123,52 @@ func (j *JobRunner) Run(cache JobCache) (*JobStat, Metadata, error) {
 		default:
 			err = ErrJobTypeInvalid
 		}
+		j.ctx = runCtx
+		attempt := j.recordAttempt(attemptStart, err)
+		endAttemptSpan(span, attempt)
+
+		// A cancelled run is never retried, and isn't counted as an error.
+		if err != nil && j.jobRun.isCancelled() {
+			return j.failed(RunCancelled, ErrJobCancelled)
+		}
 
 		if err != nil {
-			// Log Error in Metadata
-			// TODO - Error Reporting, email error
-			log.Errorln("Error running job:", j.currentStat.JobId)
-			log.Errorln(err)
+			// Log Error in Metadata. Notifiers are told once the run has
+			// failed for good.
+			j.logger().WithFields(logDuration(attempt.Duration)).WithError(err).Error("Job attempt failed.")
 
 			j.meta.ErrorCount++
 			j.meta.LastError = j.job.clk.Time().Now()
 
+			// An interrupted run is never retried.
+			if j.context().Err() != nil {
+				j.currentStat.Interrupted = true
+				return j.failed(RunFailed, ErrJobInterrupted)
+			}
+
 			// Handle retrying
 			if j.shouldRetry() {
 				j.currentRetries--
 				continue
 			}
 
-			j.collectStats(false)
-			j.meta.NumberOfFinishedRuns++
-
 			// TODO: Wrap error into something better.
-			return j.currentStat, j.meta, err
+			if errors.Is(err, context.DeadlineExceeded) {
+				return j.failed(RunTimedOut, err)
+			}
+			return j.failed(RunFailed, err)
 		} else {
 			break
 		}
 	}
 
-	log.Infof("Job %s:%s finished.", j.job.Name, j.job.Id)
-	log.Debugf("Job %s:%s output: %s", j.job.Name, j.job.Id, out)
 	j.meta.SuccessCount++
 	j.meta.NumberOfFinishedRuns++
 	j.meta.LastSuccess = j.job.clk.Time().Now()
 
 	j.collectStats(true)
-
-	// Run Dependent Jobs
-	if len(j.job.DependentJobs) != 0 {
-		for _, id := range j.job.DependentJobs {
-			newJob, err := cache.Get(id)
-			if err != nil {
-				log.Errorf("Error retrieving dependent job with id of %s", id)
-			} else {
-				newJob.Run(cache)
-			}
-		}
-	}
+	j.logger().WithFields(logDuration(j.currentStat.ExecutionDuration)).Info("Job finished.")
+	j.logger().Debugf("Job output: %s", out)
 
 	return j.currentStat, j.meta, nil
 }
@@ -128,7 +183,7 @@ func (j *JobRunner) RemoteRun() (string, error) {
 	// Calculate a response timeout
 	timeout := j.responseTimeout()
 
-	ctx := context.Background()
+	ctx := j.context()
 	if timeout > 0 {
 		var cncl func()
 		ctx, cncl = context.WithTimeout(ctx, timeout)
@@ -154,19 +209,23 @@ func (j *JobRunner) RemoteRun() (string, error) {
 		return "", err
 	}
 
-	// Set default or user's passed headers
+	// Set default or user's passed headers, and the trace context
 	j.setHeaders(req)
+	injectTraceContext(ctx, req.Header)
 
 	// Do the request
+	j.statusCode, j.output = 0, ""
 	res, err := http.DefaultClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return "", err
 	}
 	defer res.Body.Close()
+	j.statusCode = res.StatusCode
 	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
+	j.output = string(b)
 
 	// Check if we got any of the status codes the user asked for
 	if j.checkExpected(res.StatusCode) {
@@ -185,6 +244,7 @@ func initShParser() *shellwords.Parser {
 
 func (j *JobRunner) runCmd() (string, error) {
 	j.numberOfAttempts++
+	j.exitCode, j.output = nil, ""
 
 	// Get the actual command we're going to be running,
 	// including any necessary templating.
@@ -203,44 +263,143 @@ func (j *JobRunner) runCmd() (string, error) {
 		return "", ErrCmdIsEmpty
 	}
 
-	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // That's the job description
+	cmd := exec.CommandContext(j.context(), args[0], args[1:]...) //nolint:gosec // That's the job description
+	setProcessGroup(cmd)
+	cmd.Env = append(os.Environ(), j.runEnv()...)
 	out, err := cmd.CombinedOutput()
+	if cmd.ProcessState != nil {
+		exitCode := cmd.ProcessState.ExitCode()
+		j.exitCode = &exitCode
+	}
+	j.output = strings.TrimSpace(string(out))
 	if err != nil {
 		return "", fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
 	}
 	return strings.TrimSpace(string(out)), nil
 }
 
-func (j *JobRunner) tryTemplatize(content string) (string, error) {
-	delims := j.job.TemplateDelimiters
+func (j *JobRunner) context() context.Context {
+	if j.ctx == nil {
+		return context.Background()
+	}
+	return j.ctx
+}
 
-	if delims == "" {
+func (j *JobRunner) tryTemplatize(content string) (string, error) {
+	if j.job.TemplateDelimiters == "" {
 		return content, nil
 	}
 
-	split := strings.Split(delims, " ")
-	if len(split) != 2 { //nolint:gomnd
-		return "", ErrInvalidDelimiters
+	t, err := j.job.parseTemplate(content)
+	if err != nil {
+		return "", err
 	}
 
-	left, right := split[0], split[1]
-	if left == "" || right == "" {
-		return "", ErrInvalidDelimiters
+	data := &templateData{Job: j.job, Parents: map[string]*ParentOutput{}, Params: j.params}
+	if j.currentStat != nil {
+		data.LogicalTime = j.currentStat.LogicalTime
 	}
-
-	t, err := template.New("tmpl").Delims(left, right).Parse(content)
-	if err != nil {
-		return "", fmt.Errorf("Error parsing template: %v", err)
+	for _, p := range j.parents {
+		data.Parents[p.Name] = p
+		data.Parents[p.Id] = p
 	}
 
 	b := bytes.NewBuffer(nil)
-	if err := t.Execute(b, j.job); err != nil {
+	if err := t.Execute(b, data); err != nil {
 		return "", fmt.Errorf("Error executing template: %v", err)
 	}
 
 	return b.String(), nil
 }
 
+// templateData is what templates are executed with: the job, the outputs of
+// its parent jobs by name and by id, and the parameters of the run.
+type templateData struct {
+	*Job
+	Parents     map[string]*ParentOutput
+	Params      map[string]interface{}
+	LogicalTime time.Time
+}
+
+// runEnv returns the environment variables the command gets on top of kala's
+// own: KALA_PARENT_<NAME>_OUTPUT for the output of each parent job, and
+// KALA_PARENT_<NAME>_<FIELD> for each field of an output that is a JSON object,
+// as well as KALA_PARAM_<NAME> for each parameter of the run, and
+// KALA_LOGICAL_TIME, and the trace context, e.g. TRACEPARENT, if the run is
+// traced.
+func (j *JobRunner) runEnv() []string {
+	env := []string{}
+	add := func(name, value string) {
+		if len(value) > maxEnvValueSize {
+			j.logger().Warnf("Job not passed %s, as it is too large.", name)
+			return
+		}
+		env = append(env, name+"="+value)
+	}
+	addFields := func(prefix string, fields map[string]interface{}) {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, ok := fields[k].(string)
+			if !ok {
+				b, _ := json.Marshal(fields[k])
+				value = string(b)
+			}
+			add(prefix+envName(k), value)
+		}
+	}
+
+	for _, p := range j.parents {
+		prefix := "KALA_PARENT_" + envName(p.Name) + "_"
+		add(prefix+"OUTPUT", p.Output)
+		fields, _ := p.JSON.(map[string]interface{})
+		addFields(prefix, fields)
+	}
+	addFields("KALA_PARAM_", j.params)
+	if j.currentStat != nil {
+		add("KALA_LOGICAL_TIME", j.currentStat.LogicalTime.Format(time.RFC3339))
+	}
+	env = append(env, traceEnv(j.context())...)
+	return env
+}
+
+// envName turns s into an environment variable name, e.g. "my-job" into
+// "MY_JOB".
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// parseTemplate parses content as a template with the job's delimiters.
+func (j *Job) parseTemplate(content string) (*template.Template, error) {
+	split := strings.Split(j.TemplateDelimiters, " ")
+	if len(split) != 2 { //nolint:gomnd
+		return nil, ErrInvalidDelimiters
+	}
+
+	left, right := split[0], split[1]
+	if left == "" || right == "" {
+		return nil, ErrInvalidDelimiters
+	}
+
+	t, err := template.New("tmpl").Delims(left, right).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing template: %v", err)
+	}
+	return t, nil
+}
+
 func (j *JobRunner) shouldRetry() bool {
 	// Check number of retries left
 	if j.currentRetries == 0 {
@@ -264,15 +423,68 @@ func (j *JobRunner) shouldRetry() bool {
 func (j *JobRunner) runSetup() {
 	// Setup Job Stat
 	j.currentStat = NewJobStat(j.job.Id)
+	if j.jobRun != nil {
+		j.currentStat.Id = j.jobRun.Id
+	}
+	j.currentStat.CatchUp = j.catchUp
+	j.currentStat.WorkflowRunId = j.workflowRunId
+	j.currentStat.TriggeredBy = j.triggeredBy
+	j.currentStat.TriggeredByRun = j.triggeredByRun
+	j.currentStat.Params = j.params
+	j.currentStat.BackfillId = j.backfillId
+	j.currentStat.TriggerSource = j.source
+	j.currentStat.ScheduledAt = j.scheduledAt
+	j.currentStat.LogicalTime = j.logicalTime
+	if j.logicalTime.IsZero() {
+		j.currentStat.LogicalTime = j.currentStat.RanAt
+	}
 
 	// Init retries
 	j.currentRetries = j.job.Retries
 }
 
+// recordAttempt adds an attempt that started at the given time, and how it
+// went, to the stats, and returns it.
+func (j *JobRunner) recordAttempt(startedAt time.Time, err error) *RunAttempt {
+	attempt := &RunAttempt{
+		StartedAt:  startedAt,
+		Duration:   j.job.clk.Time().Now().Sub(startedAt),
+		ExitCode:   j.exitCode,
+		StatusCode: j.statusCode,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	j.currentStat.Attempts = append(j.currentStat.Attempts, attempt)
+	return attempt
+}
+
+// failed finishes the stats of a run that didn't succeed.
+func (j *JobRunner) failed(status RunStatus, err error) (*JobStat, Metadata, error) {
+	j.collectStats(false)
+	j.currentStat.Status = status
+	j.currentStat.Error = err.Error()
+	j.meta.NumberOfFinishedRuns++
+
+	entry := j.logger().WithFields(logDuration(j.currentStat.ExecutionDuration)).WithError(err)
+	if status == RunCancelled {
+		entry.Info("Job cancelled.")
+	} else {
+		entry.Errorf("Job %s.", strings.ReplaceAll(string(status), "_", " "))
+	}
+	return j.currentStat, j.meta, err
+}
+
 func (j *JobRunner) collectStats(success bool) {
 	j.currentStat.ExecutionDuration = j.job.clk.Time().Now().Sub(j.currentStat.RanAt)
 	j.currentStat.Success = success
+	j.currentStat.Status = RunFailed
+	if success {
+		j.currentStat.Status = RunSucceeded
+	}
 	j.currentStat.NumberOfRetries = j.job.Retries - j.currentRetries
+	j.currentStat.ExitCode = j.exitCode
+	j.currentStat.StatusCode = j.statusCode
 }
 
 func (j *JobRunner) checkExpected(statusCode int) bool {
@@ -315,5 +527,5 @@ func (j *JobRunner) setHeaders(req *http.Request) {
 	if j.job.RemoteProperties.Headers["Content-Type"] == nil {
 		j.job.RemoteProperties.Headers["Content-Type"] = []string{"application/json"}
 	}
-	req.Header = j.job.RemoteProperties.Headers
+	req.Header = j.job.RemoteProperties.Headers.Clone()
 }
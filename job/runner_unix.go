@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package job
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs the command in a process group of its own, so that when
+// the run is cancelled or interrupted the processes it started are killed
+// along with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package job
+
+import "os/exec"
+
+// setProcessGroup does nothing on Windows, where only the command's own
+// process is killed when the run is cancelled or interrupted.
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -1,9 +1,26 @@
 package job
 
 import (
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	uuid "github.com/nu7hatch/gouuid"
+	log "github.com/sirupsen/logrus"
 )
 
+func init() {
+	// Parameters of runs are decoded from JSON, so they can hold arrays and
+	// objects, which gob needs to know about to save them with the job.
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
 // KalaStats is the struct for storing app-level metrics
 type KalaStats struct {
 	ActiveJobs   int `json:"active_jobs"`
@@ -70,18 +87,268 @@ func NewKalaStats(cache JobCache) *KalaStats {
 	return ks
 }
 
+// TriggerSource is what started a run of a job.
+type TriggerSource string
+
+const (
+	SourceSchedule TriggerSource = "schedule"
+	SourceManual   TriggerSource = "manual"
+	// A parent job succeeded.
+	SourceDependency TriggerSource = "dependency"
+	// A parent job failed, and the job is its on failure job.
+	SourceFailure  TriggerSource = "failure"
+	SourceBackfill TriggerSource = "backfill"
+)
+
+// RunAttempt is one attempt at a run of a job.
+type RunAttempt struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	// Why the attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Exit code of a local job's command, if it ran.
+	ExitCode *int `json:"exit_code,omitempty"`
+	// HTTP status code of a remote job's response, if it got one.
+	StatusCode int `json:"status_code,omitempty"`
+}
+
 // JobStat is used to store metrics about a specific Job .Run()
 type JobStat struct {
+	Id                string        `json:"id"`
 	JobId             string        `json:"job_id"`
 	RanAt             time.Time     `json:"ran_at"`
 	NumberOfRetries   uint          `json:"number_of_retries"`
 	Success           bool          `json:"success"`
 	ExecutionDuration time.Duration `json:"execution_duration"`
+
+	// How the run went: succeeded, failed, timed out or cancelled. Empty for
+	// runs recorded before it was.
+	Status RunStatus `json:"status,omitempty"`
+
+	// Set if the run was cut short, for example by kala shutting down.
+	Interrupted bool `json:"interrupted"`
+
+	// Set if the run made up for one missed while the job was disabled or
+	// kala was down.
+	CatchUp bool `json:"catch_up"`
+
+	// The workflow run the run was part of, if any.
+	WorkflowRunId string `json:"workflow_run_id"`
+
+	// The parent job, and the id of its run, that triggered the run, if any.
+	TriggeredBy    string `json:"triggered_by,omitempty"`
+	TriggeredByRun string `json:"triggered_by_run,omitempty"`
+
+	// The point of the schedule the run is for. It is when the run started,
+	// unless the run is part of a backfill.
+	LogicalTime time.Time `json:"logical_time"`
+	// The backfill the run was part of, if any.
+	BackfillId string `json:"backfill_id,omitempty"`
+
+	// Parameters the run was started with, if any.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// What started the run: its schedule, someone starting it, a parent job
+	// finishing, or a backfill.
+	TriggerSource TriggerSource `json:"trigger_source,omitempty"`
+	// When the run was due to start. RanAt is when it actually started.
+	ScheduledAt time.Time `json:"scheduled_at"`
+
+	// Each attempt at the run, the retries included, in order.
+	Attempts []*RunAttempt `json:"attempts,omitempty"`
+	// Why the run failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Exit code of the last attempt of a local job, if its command ran.
+	ExitCode *int `json:"exit_code,omitempty"`
+	// HTTP status code of the last attempt of a remote job, if it got a
+	// response.
+	StatusCode int `json:"status_code,omitempty"`
+}
+
+// status returns how the run went, working it out for runs recorded before
+// stats had a status.
+func (s *JobStat) status() RunStatus {
+	switch {
+	case s.Status != "":
+		return s.Status
+	case s.Success:
+		return RunSucceeded
+	}
+	return RunFailed
+}
+
+// code returns the exit code or HTTP status code of the run, if any.
+func (s *JobStat) code() *int {
+	if s.ExitCode != nil {
+		return s.ExitCode
+	}
+	if s.StatusCode != 0 {
+		code := s.StatusCode
+		return &code
+	}
+	return nil
 }
 
 func NewJobStat(id string) *JobStat {
-	return &JobStat{
+	stat := &JobStat{
 		JobId: id,
 		RanAt: time.Now(),
 	}
+	if u4, err := uuid.NewV4(); err == nil {
+		stat.Id = u4.String()
+	} else {
+		log.Errorf("Error occurred when generating uuid: %s", err)
+	}
+	return stat
+}
+
+const (
+	// DefaultStatsLimit is how many stats a page has if the query doesn't say.
+	DefaultStatsLimit = 100
+	// MaxStatsLimit is the most stats a page can have.
+	MaxStatsLimit = 1000
+)
+
+var (
+	ErrInvalidStatsCursor = errors.New("Invalid cursor. It must be one returned with a previous page")
+	ErrInvalidStatsLimit  = fmt.Errorf("Invalid limit. It must be from 1 to %d", MaxStatsLimit)
+	ErrInvalidStatsStatus = errors.New("Invalid status. It must be succeeded, failed, timed_out or cancelled, as skipped runs aren't recorded in the job's stats")
+)
+
+// StatsQuery picks out a page of the stats of runs that match it. Runs are
+// ordered by when they started.
+type StatsQuery struct {
+	// Only runs that started at or after From, and before To, if set.
+	From, To time.Time
+	// Only runs that went one of these ways, if any are given.
+	Statuses []RunStatus
+	// The most stats the page has, DefaultStatsLimit if not set.
+	Limit int
+	// Where the page starts, as returned with the previous page. The first
+	// page if not set.
+	Cursor string
+	// Oldest runs first if set, newest first otherwise.
+	Ascending bool
+	// All of the matching stats in one page if set, which Limit and Cursor
+	// are then ignored for.
+	All bool
+}
+
+// QueryStats returns the page of the job's stats that matches the query, and
+// the cursor of the next page, which is empty if there are no more.
+func (j *Job) QueryStats(q StatsQuery) ([]*JobStat, string, error) {
+	j.lock.RLock()
+	stats := make([]*JobStat, len(j.Stats))
+	copy(stats, j.Stats)
+	j.lock.RUnlock()
+	return QueryStats(stats, q)
+}
+
+// QueryAllStats is QueryStats over the stats of all of the jobs in the cache.
+func QueryAllStats(cache JobCache, q StatsQuery) ([]*JobStat, string, error) {
+	jobs := cache.GetAll()
+	jobs.Lock.RLock()
+	defer jobs.Lock.RUnlock()
+
+	stats := []*JobStat{}
+	for _, j := range jobs.Jobs {
+		j.lock.RLock()
+		stats = append(stats, j.Stats...)
+		j.lock.RUnlock()
+	}
+	return QueryStats(stats, q)
+}
+
+// QueryStats returns the page of stats that matches the query, and the cursor
+// of the next page, which is empty if there are no more.
+func QueryStats(stats []*JobStat, q StatsQuery) ([]*JobStat, string, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultStatsLimit
+	}
+	if limit < 0 || limit > MaxStatsLimit {
+		return nil, "", ErrInvalidStatsLimit
+	}
+	statuses := map[RunStatus]bool{}
+	for _, status := range q.Statuses {
+		switch status {
+		case RunSucceeded, RunFailed, RunTimedOut, RunCancelled:
+			statuses[status] = true
+		default:
+			return nil, "", ErrInvalidStatsStatus
+		}
+	}
+	var after *statsKey
+	if q.Cursor != "" && !q.All {
+		key, err := parseStatsCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &key
+	}
+
+	matched := []*JobStat{}
+	for _, s := range stats {
+		switch {
+		case !q.From.IsZero() && s.RanAt.Before(q.From):
+		case !q.To.IsZero() && !s.RanAt.Before(q.To):
+		case len(statuses) > 0 && !statuses[s.status()]:
+		case after != nil && !after.before(keyOf(s), q.Ascending):
+		default:
+			matched = append(matched, s)
+		}
+	}
+	sort.Slice(matched, func(a, b int) bool {
+		return keyOf(matched[a]).before(keyOf(matched[b]), q.Ascending)
+	})
+
+	if q.All || len(matched) <= limit {
+		return matched, "", nil
+	}
+	page := matched[:limit]
+	return page, keyOf(page[limit-1]).cursor(), nil
+}
+
+// statsKey is where a stat is in the order of a query. Runs that started at
+// the same time are ordered by id.
+type statsKey struct {
+	ranAt time.Time
+	id    string
+}
+
+func keyOf(s *JobStat) statsKey {
+	return statsKey{ranAt: s.RanAt, id: s.Id}
+}
+
+// before reports whether k comes before other, in ascending order if asc is
+// set and descending order otherwise.
+func (k statsKey) before(other statsKey, asc bool) bool {
+	if !k.ranAt.Equal(other.ranAt) {
+		return k.ranAt.Before(other.ranAt) == asc
+	}
+	if k.id == other.id {
+		return false
+	}
+	return (k.id < other.id) == asc
+}
+
+func (k statsKey) cursor() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%s", k.ranAt.UnixNano(), k.id)))
+}
+
+func parseStatsCursor(cursor string) (statsKey, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return statsKey{}, ErrInvalidStatsCursor
+	}
+	parts := strings.SplitN(string(b), "/", 2)
+	if len(parts) != 2 {
+		return statsKey{}, ErrInvalidStatsCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return statsKey{}, ErrInvalidStatsCursor
+	}
+	return statsKey{ranAt: time.Unix(0, nanos), id: parts[1]}, nil
 }
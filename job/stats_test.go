@@ -74,3 +74,78 @@ func TestNextRunAt(t *testing.T) {
 	assert.NotEqual(t, j2.NextRunAt.UnixNano(), kalaStat.NextRunAt.UnixNano())
 	j.lock.RUnlock()
 }
+
+func TestQueryStats(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	stats := []*JobStat{}
+	for i := 0; i < 5; i++ {
+		stat := NewJobStat("job")
+		stat.RanAt = start.Add(time.Duration(i) * time.Hour)
+		stat.Success = i%2 == 0
+		stats = append(stats, stat)
+	}
+	stats[4].Status = RunTimedOut
+
+	page, next, err := QueryStats(stats, StatsQuery{})
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	if assert.Len(t, page, 5) {
+		assert.Equal(t, stats[4], page[0])
+		assert.Equal(t, stats[0], page[4])
+	}
+
+	page, next, err = QueryStats(stats, StatsQuery{Limit: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[4], stats[3], stats[2]}, page)
+	page, next, err = QueryStats(stats, StatsQuery{Limit: 3, Cursor: next})
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[1], stats[0]}, page)
+	assert.Empty(t, next)
+
+	page, next, err = QueryStats(stats, StatsQuery{
+		From:     start.Add(time.Hour),
+		To:       start.Add(4 * time.Hour),
+		Statuses: []RunStatus{RunFailed},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	assert.Equal(t, []*JobStat{stats[3], stats[1]}, page)
+
+	page, next, err = QueryStats(stats, StatsQuery{Statuses: []RunStatus{RunTimedOut}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[4]}, page)
+
+	// Pages carry on where the last one left off, even if more runs were
+	// recorded in between.
+	q := StatsQuery{Limit: 2, Ascending: true}
+	page, next, err = QueryStats(stats, q)
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[0], stats[1]}, page)
+	assert.NotEmpty(t, next)
+
+	stats = append(stats, NewJobStat("job"))
+	stats[5].RanAt = start.Add(5 * time.Hour)
+	q.Cursor = next
+	page, next, err = QueryStats(stats, q)
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[2], stats[3]}, page)
+	q.Cursor = next
+	page, next, err = QueryStats(stats, q)
+	assert.NoError(t, err)
+	assert.Equal(t, []*JobStat{stats[4], stats[5]}, page)
+	assert.Empty(t, next)
+
+	page, next, err = QueryStats(stats, StatsQuery{Limit: 2, Cursor: next, Ascending: true, All: true})
+	assert.NoError(t, err)
+	assert.Equal(t, stats, page)
+	assert.Empty(t, next)
+
+	_, _, err = QueryStats(stats, StatsQuery{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, ErrInvalidStatsCursor)
+	_, _, err = QueryStats(stats, StatsQuery{Limit: MaxStatsLimit + 1})
+	assert.ErrorIs(t, err, ErrInvalidStatsLimit)
+	for _, status := range []RunStatus{RunRunning, RunSkipped} {
+		_, _, err = QueryStats(stats, StatsQuery{Statuses: []RunStatus{status}})
+		assert.ErrorIs(t, err, ErrInvalidStatsStatus)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ajvb/kala/job"
@@ -14,16 +15,27 @@ import (
 )
 
 var (
-	jobBucket = []byte("jobs")
+	jobBucket   = []byte("jobs")
+	leaseBucket = []byte("leases")
+
+	calendarBucket = []byte("calendars")
+)
+
+var (
+	_ job.Leaser    = (*BoltJobDB)(nil)
+	_ job.Compactor = (*BoltJobDB)(nil)
 )
 
+const perms os.FileMode = 0o0600
+
+var options = &bolt.Options{Timeout: time.Second * 10} //nolint:gomnd
+
 func GetBoltDB(path string) *BoltJobDB {
 	if path != "" && !strings.HasSuffix(path, "/") {
 		path += "/"
 	}
 	path += "jobdb.db"
-	var perms os.FileMode = 0o0600
-	database, err := bolt.Open(path, perms, &bolt.Options{Timeout: time.Second * 10}) //nolint:gomnd
+	database, err := bolt.Open(path, perms, options)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,16 +48,33 @@ func GetBoltDB(path string) *BoltJobDB {
 type BoltJobDB struct {
 	dbConn *bolt.DB
 	path   string
+	// Held for writing while Compact swaps the database file, and for
+	// reading by everything else that uses dbConn.
+	lock sync.RWMutex
 }
 
 func (db *BoltJobDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
 	return db.dbConn.Close()
 }
 
+func (db *BoltJobDB) update(fn func(tx *bolt.Tx) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.dbConn.Update(fn)
+}
+
+func (db *BoltJobDB) view(fn func(tx *bolt.Tx) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.dbConn.View(fn)
+}
+
 func (db *BoltJobDB) GetAll() ([]*job.Job, error) {
 	allJobs := []*job.Job{}
 
-	err := db.dbConn.Update(func(tx *bolt.Tx) error {
+	err := db.update(func(tx *bolt.Tx) error {
 		bucket, err := tx.CreateBucketIfNotExists(jobBucket)
 		if err != nil {
 			return err
@@ -81,7 +110,7 @@ func (db *BoltJobDB) GetAll() ([]*job.Job, error) {
 func (db *BoltJobDB) Get(id string) (*job.Job, error) {
 	j := new(job.Job)
 
-	err := db.dbConn.View(func(tx *bolt.Tx) error {
+	err := db.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(jobBucket)
 
 		v := b.Get([]byte(id))
@@ -103,7 +132,7 @@ func (db *BoltJobDB) Get(id string) (*job.Job, error) {
 }
 
 func (db *BoltJobDB) Delete(id string) error {
-	err := db.dbConn.Update(func(tx *bolt.Tx) error {
+	err := db.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(jobBucket)
 		return bucket.Delete([]byte(id))
 	})
@@ -111,7 +140,7 @@ func (db *BoltJobDB) Delete(id string) error {
 }
 
 func (db *BoltJobDB) Save(j *job.Job) error {
-	err := db.dbConn.Update(func(tx *bolt.Tx) error {
+	err := db.update(func(tx *bolt.Tx) error {
 		bucket, err := tx.CreateBucketIfNotExists(jobBucket)
 		if err != nil {
 			return err
@@ -132,3 +161,231 @@ func (db *BoltJobDB) Save(j *job.Job) error {
 	})
 	return err
 }
+
+func (db *BoltJobDB) GetAllCalendars() ([]*job.Calendar, error) {
+	calendars := []*job.Calendar{}
+
+	err := db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(calendarBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			c := new(job.Calendar)
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(c); err != nil {
+				return err
+			}
+			calendars = append(calendars, c)
+			return nil
+		})
+	})
+
+	return calendars, err
+}
+
+func (db *BoltJobDB) SaveCalendar(c *job.Calendar) error {
+	return db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(calendarBucket)
+		if err != nil {
+			return err
+		}
+
+		buffer := new(bytes.Buffer)
+		if err := gob.NewEncoder(buffer).Encode(c); err != nil {
+			return err
+		}
+		return bucket.Put([]byte(c.Name), buffer.Bytes())
+	})
+}
+
+func (db *BoltJobDB) DeleteCalendar(name string) error {
+	return db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(calendarBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Delete([]byte(name))
+	})
+}
+
+// AcquireLease stores the lease's expiry under its key, unless an unexpired
+// lease is already there. Expired leases are cleared out as new ones are taken.
+func (db *BoltJobDB) AcquireLease(key string, ttl time.Duration) (bool, error) {
+	acquired := false
+	err := db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(leaseBucket)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if v := bucket.Get([]byte(key)); v != nil {
+			expiry, err := time.Parse(time.RFC3339Nano, string(v))
+			if err == nil && expiry.After(now) {
+				return nil
+			}
+		}
+
+		if err := deleteExpiredLeases(bucket, now); err != nil {
+			return err
+		}
+
+		acquired = true
+		return bucket.Put([]byte(key), []byte(now.Add(ttl).Format(time.RFC3339Nano)))
+	})
+	return acquired, err
+}
+
+func deleteExpiredLeases(bucket *bolt.Bucket, now time.Time) error {
+	expired := [][]byte{}
+	err := bucket.ForEach(func(k, v []byte) error {
+		expiry, err := time.Parse(time.RFC3339Nano, string(v))
+		if err != nil || !expiry.After(now) {
+			expired = append(expired, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact applies retention to the stored jobs and clears out expired leases.
+// It then rewrites the database file if much of it is free, as bolt reuses the
+// space of deleted data but never gives it back.
+func (db *BoltJobDB) Compact(defaults *job.Retention, now time.Time) error {
+	err := db.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(jobBucket)
+		if err != nil {
+			return err
+		}
+
+		// Buckets can't be changed while they are iterated over.
+		retained := map[string][]byte{}
+		err = bucket.ForEach(func(k, v []byte) error {
+			j := new(job.Job)
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(j); err != nil {
+				log.Errorf("Skipping job %s that couldn't be decoded: %s", k, err)
+				return nil
+			}
+			if !j.RetainStats(defaults, now) {
+				return nil
+			}
+			buffer := new(bytes.Buffer)
+			if err := gob.NewEncoder(buffer).Encode(j); err != nil {
+				return err
+			}
+			retained[string(k)] = buffer.Bytes()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for k, v := range retained {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+
+		leases, err := tx.CreateBucketIfNotExists(leaseBucket)
+		if err != nil {
+			return err
+		}
+		// Leases expire by the wall clock, as in AcquireLease.
+		return deleteExpiredLeases(leases, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	if !db.mostlyFree() {
+		return nil
+	}
+	return db.rewrite()
+}
+
+// Files are only rewritten once at least this share of them is free, as a
+// rewrite copies everything.
+const rewriteFreeShare = 0.25
+
+// mostlyFree reports whether enough of the database file is free to rewrite it.
+func (db *BoltJobDB) mostlyFree() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	var size int64
+	err := db.dbConn.View(func(tx *bolt.Tx) error {
+		size = tx.Size()
+		return nil
+	})
+	if err != nil || size == 0 {
+		return false
+	}
+	free := int64(db.dbConn.Stats().FreePageN) * int64(db.dbConn.Info().PageSize)
+	return float64(free) >= rewriteFreeShare*float64(size)
+}
+
+// rewrite copies the database to a new file, which only takes the space that
+// the data needs, and swaps it in.
+func (db *BoltJobDB) rewrite() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	compactPath := db.path + ".compact"
+	compacted, err := bolt.Open(compactPath, perms, options)
+	if err != nil {
+		return err
+	}
+	err = db.dbConn.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return compacted.Update(func(ctx *bolt.Tx) error {
+				bucket, err := ctx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(bucket, b)
+			})
+		})
+	})
+	if closeErr := compacted.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(compactPath) //nolint:errcheck // it's only left over
+		return err
+	}
+
+	if err := db.dbConn.Close(); err != nil {
+		os.Remove(compactPath) //nolint:errcheck // it's only left over
+		return err
+	}
+	renameErr := os.Rename(compactPath, db.path)
+	// The old file is opened again if it couldn't be replaced.
+	database, err := bolt.Open(db.path, perms, options)
+	if err != nil {
+		return err
+	}
+	db.dbConn = database
+	return renameErr
+}
+
+// copyBucket copies the keys of src, and the buckets nested in it, to dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	// Keys are copied in order, so pages can be filled up.
+	dst.FillPercent = 1
+	return src.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested, err := dst.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+			return copyBucket(nested, src.Bucket(k))
+		}
+		return dst.Put(k, v)
+	})
+}
@@ -1,16 +1,32 @@
 package boltdb
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ajvb/kala/job"
 
 	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
 )
 
 var testDbPath = ""
 
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "kala-boltdb")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	testDbPath = dir
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func setupTest(t *testing.T) {
 	db := GetBoltDB(testDbPath)
 	defer db.Close()
@@ -101,3 +117,103 @@ func TestSaveAndGetAllJobs(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, len(jobs), 2)
 }
+
+func TestAcquireLease(t *testing.T) {
+	db := GetBoltDB(testDbPath)
+	defer db.Close()
+
+	// Leases outlive the test run, so use keys that are new each time.
+	key := fmt.Sprintf("job/%d", time.Now().UnixNano())
+	expiredKey := key + "/expired"
+
+	acquired, err := db.AcquireLease(key, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = db.AcquireLease(key, time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// Expired leases can be taken again.
+	acquired, err = db.AcquireLease(expiredKey, -time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = db.AcquireLease(expiredKey, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestSaveJobWithNestedParams(t *testing.T) {
+	setupTest(t)
+
+	db := GetBoltDB(testDbPath)
+	cache := job.NewLockFreeJobCache(db)
+	defer db.Close()
+
+	params := map[string]interface{}{
+		"dates":   []interface{}{"2026-10-01", "2026-10-02"},
+		"options": map[string]interface{}{"full": true, "limit": 10.0},
+		"none":    nil,
+	}
+	genericMockJob := job.GetMockJobWithGenericSchedule(time.Now())
+	genericMockJob.Init(cache)
+	genericMockJob.Stats = []*job.JobStat{{JobId: genericMockJob.Id, Params: params}}
+	assert.NoError(t, db.Save(genericMockJob))
+
+	j, err := db.Get(genericMockJob.Id)
+	assert.NoError(t, err)
+	if assert.Len(t, j.Stats, 1) {
+		assert.Equal(t, params, j.Stats[0].Params)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	setupTest(t)
+
+	db := GetBoltDB(testDbPath)
+	cache := job.NewLockFreeJobCache(db)
+	defer db.Close()
+
+	now := time.Now()
+	retained := job.GetMockJobWithGenericSchedule(now)
+	retained.Init(cache)
+	for i := 5; i > 0; i-- {
+		retained.Stats = append(retained.Stats, &job.JobStat{JobId: retained.Id, RanAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	assert.NoError(t, db.Save(retained))
+
+	// Deleted jobs leave the file as big as it was.
+	for i := 0; i < 100; i++ {
+		deleted := job.GetMockJobWithGenericSchedule(now)
+		deleted.Init(cache)
+		deleted.Command = strings.Repeat("x", 10000)
+		assert.NoError(t, db.Save(deleted))
+		assert.NoError(t, db.Delete(deleted.Id))
+	}
+	expiredLease := fmt.Sprintf("job/%d/expired", now.UnixNano())
+	_, err := db.AcquireLease(expiredLease, -time.Second)
+	assert.NoError(t, err)
+	before, err := os.Stat(db.path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+
+	after, err := os.Stat(db.path)
+	assert.NoError(t, err)
+	assert.Less(t, after.Size(), before.Size())
+	j, err := db.Get(retained.Id)
+	if assert.NoError(t, err) && assert.Len(t, j.Stats, 2) {
+		assert.Equal(t, retained.Stats[3].RanAt.UnixNano(), j.Stats[0].RanAt.UnixNano())
+	}
+	err = db.view(func(tx *bolt.Tx) error {
+		assert.Nil(t, tx.Bucket(leaseBucket).Get([]byte(expiredLease)))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// The compacted file is used from then on.
+	assert.NoError(t, db.Save(retained))
+	jobs, err := db.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/ajvb/kala/cluster"
 	"github.com/ajvb/kala/job"
 
 	"github.com/hashicorp/consul/api"
@@ -13,7 +15,18 @@ import (
 )
 
 var (
-	prefix = "kala/jobs/"
+	prefix       = "kala/jobs/"
+	nodesPrefix  = "kala/nodes/"
+	leasesPrefix = "kala/leases/"
+
+	calendarsPrefix = "kala/calendars/"
+)
+
+var (
+	_ cluster.Registry = (*ConsulJobDB)(nil)
+	_ job.Leaser       = (*ConsulJobDB)(nil)
+	_ job.CalendarDB   = (*ConsulJobDB)(nil)
+	_ job.Compactor    = (*ConsulJobDB)(nil)
 )
 
 func New(address string) *ConsulJobDB {
@@ -26,12 +39,14 @@ func New(address string) *ConsulJobDB {
 		log.Fatal(err)
 	}
 	return &ConsulJobDB{
-		conn: client.KV(),
+		conn:    client.KV(),
+		session: client.Session(),
 	}
 }
 
 type ConsulJobDB struct {
-	conn *api.KV
+	conn    *api.KV
+	session *api.Session
 }
 
 func (db *ConsulJobDB) Close() error {
@@ -88,13 +103,126 @@ func (db *ConsulJobDB) Delete(id string) error {
 }
 
 func (db *ConsulJobDB) Save(j *job.Job) error {
-	buffer := new(bytes.Buffer)
-	enc := json.NewEncoder(buffer)
-	err := enc.Encode(j)
+	b, err := j.StorageJSON()
 	if err != nil {
 		return err
 	}
-	pair := &api.KVPair{Key: prefix + j.Id, Value: buffer.Bytes()}
+	pair := &api.KVPair{Key: prefix + j.Id, Value: b}
 	_, err = db.conn.Put(pair, &api.WriteOptions{})
 	return err
 }
+
+// Compact applies retention to the stored jobs. Each is written back with a
+// check-and-set, so that a save in the meantime isn't undone. Consul gives back
+// the space of the data removed by itself.
+func (db *ConsulJobDB) Compact(defaults *job.Retention, now time.Time) error {
+	pairs, _, err := db.conn.List(prefix, &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		j := new(job.Job)
+		if err := json.Unmarshal(pair.Value, j); err != nil {
+			log.Errorf("Skipping job %s that couldn't be decoded: %s", pair.Key, err)
+			continue
+		}
+		if !j.RetainStats(defaults, now) {
+			continue
+		}
+		b, err := j.StorageJSON()
+		if err != nil {
+			return err
+		}
+		retained := &api.KVPair{Key: pair.Key, Value: b, ModifyIndex: pair.ModifyIndex}
+		if _, _, err := db.conn.CAS(retained, &api.WriteOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *ConsulJobDB) Heartbeat(m *cluster.Member) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	pair := &api.KVPair{Key: nodesPrefix + m.Id, Value: b}
+	_, err = db.conn.Put(pair, &api.WriteOptions{})
+	return err
+}
+
+func (db *ConsulJobDB) Members() ([]*cluster.Member, error) {
+	members := []*cluster.Member{}
+
+	pairs, _, err := db.conn.List(nodesPrefix, &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		m := new(cluster.Member)
+		if err := json.Unmarshal(pair.Value, m); err != nil {
+			log.Errorf("Skipping cluster member %s that couldn't be decoded: %s", pair.Key, err)
+			continue
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+func (db *ConsulJobDB) Leave(id string) error {
+	_, err := db.conn.Delete(nodesPrefix+id, &api.WriteOptions{})
+	return err
+}
+
+func (db *ConsulJobDB) GetAllCalendars() ([]*job.Calendar, error) {
+	calendars := []*job.Calendar{}
+
+	pairs, _, err := db.conn.List(calendarsPrefix, &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		c := new(job.Calendar)
+		if err := json.Unmarshal(pair.Value, c); err != nil {
+			continue
+		}
+		calendars = append(calendars, c)
+	}
+
+	return calendars, nil
+}
+
+func (db *ConsulJobDB) SaveCalendar(c *job.Calendar) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	pair := &api.KVPair{Key: calendarsPrefix + c.Name, Value: b}
+	_, err = db.conn.Put(pair, &api.WriteOptions{})
+	return err
+}
+
+func (db *ConsulJobDB) DeleteCalendar(name string) error {
+	_, err := db.conn.Delete(calendarsPrefix+name, &api.WriteOptions{})
+	return err
+}
+
+// AcquireLease locks the lease key with a new session that expires after ttl.
+// The key is deleted along with the session, so old leases don't pile up.
+// Consul caps session TTLs at 24 hours.
+func (db *ConsulJobDB) AcquireLease(key string, ttl time.Duration) (bool, error) {
+	id, _, err := db.session.Create(&api.SessionEntry{
+		TTL:      ttl.String(),
+		Behavior: api.SessionBehaviorDelete,
+	}, &api.WriteOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	acquired, _, err := db.conn.Acquire(&api.KVPair{Key: leasesPrefix + key, Session: id}, &api.WriteOptions{})
+	if err != nil || !acquired {
+		_, _ = db.session.Destroy(id, &api.WriteOptions{})
+	}
+	return acquired, err
+}
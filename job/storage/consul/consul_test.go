@@ -99,3 +99,26 @@ func TestSaveAndGetAllJobs(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(jobs))
 }
+
+func TestCompact(t *testing.T) {
+	setupTest(t)
+
+	db := New("")
+	cache := job.NewLockFreeJobCache(db)
+	defer db.Close()
+
+	now := time.Now()
+	genericMockJob := job.GetMockJobWithGenericSchedule(now)
+	genericMockJob.Init(cache)
+	for i := 5; i > 0; i-- {
+		genericMockJob.Stats = append(genericMockJob.Stats, &job.JobStat{JobId: genericMockJob.Id, RanAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	assert.NoError(t, db.Save(genericMockJob))
+
+	assert.NoError(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+
+	j, err := db.Get(genericMockJob.Id)
+	if assert.NoError(t, err) {
+		assert.Len(t, j.Stats, 2)
+	}
+}
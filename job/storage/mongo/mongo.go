@@ -1,6 +1,8 @@
 package mongo
 
 import (
+	"time"
+
 	"github.com/ajvb/kala/job"
 
 	log "github.com/sirupsen/logrus"
@@ -13,6 +15,8 @@ var (
 	collection = "jobs"
 )
 
+var _ job.Compactor = DB{}
+
 // DB is concrete implementation of the JobDB interface, that uses Redis for persistence.
 type DB struct {
 	collection *mgo.Collection
@@ -85,6 +89,31 @@ func (d DB) Save(j *job.Job) error {
 	return nil
 }
 
+// Compact applies retention to the stored jobs, and then compacts the
+// collection to give back the space of the data removed. Jobs that got stats
+// since they were read aren't matched, so that the stats aren't lost, and are
+// left for the next sweep.
+func (d DB) Compact(defaults *job.Retention, now time.Time) error {
+	jobs, err := d.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		stats := len(j.Stats)
+		if !j.RetainStats(defaults, now) {
+			continue
+		}
+		err := d.collection.Update(
+			bson.M{"id": j.Id, "stats": bson.M{"$size": stats}},
+			bson.M{"$set": bson.M{"stats": j.Stats}},
+		)
+		if err != nil && err != mgo.ErrNotFound {
+			return err
+		}
+	}
+	return d.database.Run(bson.D{{Name: "compact", Value: collection}}, nil)
+}
+
 // Close closes the connection to Redis.
 func (d DB) Close() error {
 	d.session.Close()
@@ -100,6 +100,27 @@ func TestSaveAndGetAllJobs(t *testing.T) {
 	assert.Equal(t, 2, len(jobs))
 }
 
+func TestCompact(t *testing.T) {
+	db := NewTestDb(t)
+
+	cache := job.NewLockFreeJobCache(db)
+
+	now := time.Now()
+	genericMockJob := job.GetMockJobWithGenericSchedule(now)
+	genericMockJob.Init(cache)
+	for i := 5; i > 0; i-- {
+		genericMockJob.Stats = append(genericMockJob.Stats, &job.JobStat{JobId: genericMockJob.Id, RanAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	assert.NoError(t, db.Save(genericMockJob))
+
+	assert.NoError(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+
+	j, err := db.Get(genericMockJob.Id)
+	if assert.NoError(t, err) {
+		assert.Len(t, j.Stats, 2)
+	}
+}
+
 func TestEnd(t *testing.T) {
 	db := NewTestDb(t)
 	db.Close()
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 
@@ -19,6 +20,8 @@ var (
 	TableName = "jobs"
 )
 
+var _ job.Compactor = DB{}
+
 type DB struct {
 	conn *sqlx.DB
 }
@@ -96,7 +99,7 @@ func (d DB) Delete(id string) error {
 func (d DB) Save(j *job.Job) error {
 	template := `replace into %[1]s (id, job) values(?, ?);`
 	query := fmt.Sprintf(template, TableName)
-	r, err := json.Marshal(j)
+	r, err := j.StorageJSON()
 	if err != nil {
 		return err
 	}
@@ -118,6 +121,68 @@ func (d DB) Save(j *job.Job) error {
 	return transaction.Commit()
 }
 
+// Compact applies retention to the stored jobs, locking them while it does so
+// that saves in the meantime aren't undone, and then optimizes the table to
+// give back the space of the data removed.
+func (d DB) Compact(defaults *job.Retention, now time.Time) error {
+	transaction, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	retained, err := retainJobs(transaction, defaults, now)
+	if err != nil {
+		transaction.Rollback() //nolint:errcheck // adding insult to injury
+		return err
+	}
+	query := fmt.Sprintf(`update %[1]s set job = ? where id = ?;`, TableName)
+	for _, j := range retained {
+		r, err := j.StorageJSON()
+		if err == nil {
+			_, err = transaction.Exec(query, string(r), j.Id)
+		}
+		if err != nil {
+			transaction.Rollback() //nolint:errcheck // adding insult to injury
+			return err
+		}
+	}
+	if err := transaction.Commit(); err != nil {
+		return err
+	}
+
+	_, err = d.conn.Exec(fmt.Sprintf(`optimize table %s;`, TableName))
+	return err
+}
+
+// retainJobs locks the stored jobs, and returns the ones that retention
+// removed stats from.
+func retainJobs(transaction *sql.Tx, defaults *job.Retention, now time.Time) ([]*job.Job, error) {
+	rows, err := transaction.Query(fmt.Sprintf(`select job from %s for update;`, TableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retained := []*job.Job{}
+	for rows.Next() {
+		var r sql.NullString
+		if err := rows.Scan(&r); err != nil {
+			return nil, err
+		}
+		if !r.Valid {
+			continue
+		}
+		j := &job.Job{}
+		if err := json.Unmarshal([]byte(r.String), j); err != nil {
+			log.Errorf("Skipping job that couldn't be decoded: %s", err)
+			continue
+		}
+		if j.RetainStats(defaults, now) {
+			retained = append(retained, j)
+		}
+	}
+	return retained, rows.Err()
+}
+
 // Close closes the connection to Postgres.
 func (d DB) Close() error {
 	return d.conn.Close()
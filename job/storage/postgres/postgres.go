@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	_ "github.com/lib/pq"
 
@@ -14,6 +15,8 @@ import (
 
 const TABLE_NAME = "jobs"
 
+var _ job.Compactor = DB{}
+
 type DB struct {
 	conn *sql.DB
 }
@@ -90,7 +93,7 @@ func (d DB) Save(j *job.Job) error {
 	}
 
 	query := fmt.Sprintf(template, TABLE_NAME)
-	r, err := json.Marshal(j)
+	r, err := j.StorageJSON()
 	if err != nil {
 		return err
 	}
@@ -112,6 +115,65 @@ func (d DB) Save(j *job.Job) error {
 	return transaction.Commit()
 }
 
+// Compact applies retention to the stored jobs, locking them while it does so
+// that saves in the meantime aren't undone, and then vacuums the table to give
+// back the space of the data removed.
+func (d DB) Compact(defaults *job.Retention, now time.Time) error {
+	transaction, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	retained, err := retainJobs(transaction, defaults, now)
+	if err != nil {
+		transaction.Rollback() //nolint:errcheck // adding insult to injury
+		return err
+	}
+	query := fmt.Sprintf(`update %[1]s set job = $1 where job ->> 'id' = $2;`, TABLE_NAME)
+	for _, j := range retained {
+		r, err := j.StorageJSON()
+		if err == nil {
+			_, err = transaction.Exec(query, string(r), j.Id)
+		}
+		if err != nil {
+			transaction.Rollback() //nolint:errcheck // adding insult to injury
+			return err
+		}
+	}
+	if err := transaction.Commit(); err != nil {
+		return err
+	}
+
+	_, err = d.conn.Exec(fmt.Sprintf(`vacuum %s;`, TABLE_NAME))
+	return err
+}
+
+// retainJobs locks the stored jobs, and returns the ones that retention
+// removed stats from.
+func retainJobs(transaction *sql.Tx, defaults *job.Retention, now time.Time) ([]*job.Job, error) {
+	rows, err := transaction.Query(fmt.Sprintf(`select job from %s for update;`, TABLE_NAME))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retained := []*job.Job{}
+	for rows.Next() {
+		var r string
+		if err := rows.Scan(&r); err != nil {
+			return nil, err
+		}
+		j := &job.Job{}
+		if err := json.Unmarshal([]byte(r), j); err != nil {
+			log.Errorf("Skipping job that couldn't be decoded: %s", err)
+			continue
+		}
+		if j.RetainStats(defaults, now) {
+			retained = append(retained, j)
+		}
+	}
+	return retained, rows.Err()
+}
+
 // Close closes the connection to Postgres.
 func (d DB) Close() error {
 	return d.conn.Close()
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -122,3 +123,44 @@ func TestSaveAndGetAllJobs(t *testing.T) {
 	}
 
 }
+
+// retainedStats matches the JSON of a job that has n stats.
+type retainedStats int
+
+func (n retainedStats) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	j := &job.Job{}
+	return json.Unmarshal([]byte(s), j) == nil && len(j.Stats) == int(n)
+}
+
+func TestCompact(t *testing.T) {
+	db, m := NewTestDb()
+
+	now := time.Now()
+	compacted := job.GetMockJobWithGenericSchedule(now)
+	for i := 5; i > 0; i-- {
+		compacted.Stats = append(compacted.Stats, &job.JobStat{JobId: compacted.Id, RanAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	kept := job.GetMockJobWithGenericSchedule(now)
+
+	jobOne, err := compacted.StorageJSON()
+	assert.NoError(t, err)
+	jobTwo, err := kept.StorageJSON()
+	assert.NoError(t, err)
+
+	m.ExpectBegin()
+	m.ExpectQuery("select job from jobs for update").
+		WillReturnRows(sqlmock.NewRows([]string{"job"}).AddRow(jobOne).AddRow(jobTwo))
+	m.ExpectExec("update jobs set job .*").
+		WithArgs(retainedStats(2), compacted.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+	m.ExpectExec("vacuum jobs").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+	assert.NoError(t, m.ExpectationsWereMet())
+}
@@ -1,6 +1,10 @@
 package redis
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/ajvb/kala/cluster"
 	"github.com/ajvb/kala/job"
 
 	"github.com/garyburd/redigo/redis"
@@ -10,6 +14,19 @@ import (
 var (
 	// HashKey is the hash key where jobs are persisted.
 	HashKey = "kala:jobs"
+	// NodesHashKey is the hash key where cluster members are registered.
+	NodesHashKey = "kala:nodes"
+	// LeasePrefix is the key prefix under which run leases are stored.
+	LeasePrefix = "kala:leases:"
+	// CalendarsHashKey is the hash key where calendars are persisted.
+	CalendarsHashKey = "kala:calendars"
+)
+
+var (
+	_ cluster.Registry = DB{}
+	_ job.Leaser       = DB{}
+	_ job.CalendarDB   = DB{}
+	_ job.Compactor    = DB{}
 )
 
 // DB is concrete implementation of the JobDB interface, that uses Redis for persistence.
@@ -101,6 +118,95 @@ func (d DB) Save(j *job.Job) error {
 	return nil
 }
 
+// compactSource sets a field of a hash to ARGV[3] if it is still ARGV[2], so
+// that compacting a job doesn't undo a save made in the meantime.
+const compactSource = `if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+end
+return 0`
+
+var compactScript = redis.NewScript(1, compactSource)
+
+// Compact applies retention to the stored jobs. Redis gives back the memory of
+// the data removed by itself.
+func (d DB) Compact(defaults *job.Retention, now time.Time) error {
+	vals, err := redis.ByteSlices(d.conn.Do("HGETALL", HashKey))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(vals); i += 2 {
+		id, stored := string(vals[i]), vals[i+1]
+		j, err := job.NewFromBytes(stored)
+		if err != nil {
+			log.Errorf("Skipping job %s that couldn't be decoded: %s", id, err)
+			continue
+		}
+		if !j.RetainStats(defaults, now) {
+			continue
+		}
+		b, err := j.Bytes()
+		if err != nil {
+			return err
+		}
+		if _, err := compactScript.Do(d.conn, HashKey, id, stored, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AcquireLease sets the lease key if it doesn't exist yet, expiring it after ttl.
+func (d DB) AcquireLease(key string, ttl time.Duration) (bool, error) {
+	_, err := redis.String(d.conn.Do("SET", LeasePrefix+key, 1, "NX", "PX", ttl.Milliseconds()))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetAllCalendars returns all persisted Calendars.
+func (d DB) GetAllCalendars() ([]*job.Calendar, error) {
+	calendars := []*job.Calendar{}
+
+	vals, err := redis.ByteSlices(d.conn.Do("HVALS", CalendarsHashKey))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, val := range vals {
+		c := &job.Calendar{}
+		if err := json.Unmarshal(val, c); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, c)
+	}
+
+	return calendars, nil
+}
+
+// SaveCalendar persists a Calendar.
+func (d DB) SaveCalendar(c *job.Calendar) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.Do("HSET", CalendarsHashKey, c.Name, b)
+	return err
+}
+
+// DeleteCalendar deletes a persisted Calendar.
+func (d DB) DeleteCalendar(name string) error {
+	_, err := d.conn.Do("HDEL", CalendarsHashKey, name)
+	return err
+}
+
 // Close closes the connection to Redis.
 func (d DB) Close() error {
 	err := d.conn.Close()
@@ -109,3 +215,41 @@ func (d DB) Close() error {
 	}
 	return nil
 }
+
+// Heartbeat registers a cluster member, or refreshes it if already registered.
+func (d DB) Heartbeat(m *cluster.Member) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.Do("HSET", NodesHashKey, m.Id, b)
+	return err
+}
+
+// Members returns all registered cluster members.
+func (d DB) Members() ([]*cluster.Member, error) {
+	members := []*cluster.Member{}
+
+	vals, err := redis.ByteSlices(d.conn.Do("HVALS", NodesHashKey))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, val := range vals {
+		m := &cluster.Member{}
+		if err := json.Unmarshal(val, m); err != nil {
+			log.Errorf("Skipping cluster member that couldn't be decoded: %s", err)
+			continue
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+// Leave removes a cluster member.
+func (d DB) Leave(id string) error {
+	_, err := d.conn.Do("HDEL", NodesHashKey, id)
+	return err
+}
@@ -1,10 +1,12 @@
 package redis
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/ajvb/kala/cluster"
 	"github.com/ajvb/kala/job"
 	"github.com/rafaeljusto/redigomock"
 	"github.com/stretchr/testify/assert"
@@ -147,6 +149,108 @@ func TestGetAllJobs(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+// retainedStats matches a stored job that has n stats.
+type retainedStats int
+
+func (n retainedStats) Match(input interface{}) bool {
+	b, ok := input.([]byte)
+	if !ok {
+		return false
+	}
+	j, err := job.NewFromBytes(b)
+	return err == nil && len(j.Stats) == int(n)
+}
+
+func TestCompact(t *testing.T) {
+	now := time.Now()
+	j := job.GetMockJobWithGenericSchedule(now)
+	j.Init(cache)
+	for i := 5; i > 0; i-- {
+		j.Stats = append(j.Stats, &job.JobStat{JobId: j.Id, RanAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	stored, err := j.Bytes()
+	assert.Nil(t, err)
+
+	// Jobs that keep all their stats are left as they are.
+	conn.Command("HGETALL", HashKey).
+		Expect([]interface{}{
+			[]byte(j.Id), stored,
+			[]byte(testJobs[1].Job.Id), testJobs[1].Bytes,
+		})
+	compacted := conn.Script([]byte(compactSource), 1, HashKey, j.Id, stored, retainedStats(2)).
+		Expect(int64(1))
+
+	assert.Nil(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+	assert.Equal(t, 1, conn.Stats(compacted))
+
+	// Test error handling
+	conn.Command("HGETALL", HashKey).
+		ExpectError(errors.New("Redis error"))
+
+	assert.NotNil(t, db.Compact(&job.Retention{MaxRuns: 2}, now))
+}
+
+func TestClusterMembership(t *testing.T) {
+	m := &cluster.Member{Id: "node-a", Address: "10.0.0.1:8000", LastSeen: time.Now().UTC()}
+	b, err := json.Marshal(m)
+	assert.Nil(t, err)
+
+	// Expect a HSET operation to be performed on the nodes hash key with the member ID
+	conn.Command("HSET", NodesHashKey, m.Id, b).
+		Expect("ok")
+	assert.Nil(t, db.Heartbeat(m))
+
+	conn.Command("HVALS", NodesHashKey).
+		Expect([]interface{}{b})
+	members, err := db.Members()
+	assert.Nil(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, m.Id, members[0].Id)
+	assert.Equal(t, m.Address, members[0].Address)
+	assert.True(t, m.LastSeen.Equal(members[0].LastSeen))
+
+	conn.Command("HDEL", NodesHashKey, m.Id).
+		Expect("ok")
+	assert.Nil(t, db.Leave(m.Id))
+
+	// Members that can't be decoded are skipped.
+	conn.Command("HVALS", NodesHashKey).
+		Expect([]interface{}{b, []byte("not json")})
+	members, err = db.Members()
+	assert.Nil(t, err)
+	assert.Len(t, members, 1)
+
+	// Test error handling
+	conn.Command("HVALS", NodesHashKey).
+		ExpectError(errors.New("Redis error"))
+	_, err = db.Members()
+	assert.NotNil(t, err)
+}
+
+func TestAcquireLease(t *testing.T) {
+	ttl := time.Minute
+
+	// Expect a SET NX operation with the lease key and ttl in milliseconds
+	conn.Command("SET", LeasePrefix+"job/1", 1, "NX", "PX", ttl.Milliseconds()).
+		Expect("OK")
+	acquired, err := db.AcquireLease("job/1", ttl)
+	assert.Nil(t, err)
+	assert.True(t, acquired)
+
+	// A nil reply means the lease is already held
+	conn.Command("SET", LeasePrefix+"job/1", 1, "NX", "PX", ttl.Milliseconds()).
+		Expect(nil)
+	acquired, err = db.AcquireLease("job/1", ttl)
+	assert.Nil(t, err)
+	assert.False(t, acquired)
+
+	// Test error handling
+	conn.Command("SET", LeasePrefix+"job/1", 1, "NX", "PX", ttl.Milliseconds()).
+		ExpectError(errors.New("Redis error"))
+	_, err = db.AcquireLease("job/1", ttl)
+	assert.NotNil(t, err)
+}
+
 func TestNew(t *testing.T) {
 
 }
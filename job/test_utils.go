@@ -149,14 +149,22 @@ func awaitJobRan(t *testing.T, j *Job, timeout time.Duration) {
 
 var _ JobDB = (*MemoryDB)(nil)
 
+var _ Leaser = (*MemoryDB)(nil)
+
+var _ CalendarDB = (*MemoryDB)(nil)
+
 type MemoryDB struct {
-	m    map[string]*Job
-	lock sync.RWMutex
+	m         map[string]*Job
+	leases    map[string]time.Time
+	calendars map[string]*Calendar
+	lock      sync.RWMutex
 }
 
 func NewMemoryDB() *MemoryDB {
 	return &MemoryDB{
-		m: map[string]*Job{},
+		m:         map[string]*Job{},
+		leases:    map[string]time.Time{},
+		calendars: map[string]*Calendar{},
 	}
 }
 
@@ -200,3 +208,36 @@ func (m *MemoryDB) Save(j *Job) error {
 func (m *MemoryDB) Close() error {
 	return nil
 }
+
+func (m *MemoryDB) AcquireLease(key string, ttl time.Duration) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if expiry, held := m.leases[key]; held && expiry.After(time.Now()) {
+		return false, nil
+	}
+	m.leases[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *MemoryDB) GetAllCalendars() (ret []*Calendar, _ error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, c := range m.calendars {
+		ret = append(ret, c)
+	}
+	return
+}
+
+func (m *MemoryDB) SaveCalendar(c *Calendar) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calendars[c.Name] = c
+	return nil
+}
+
+func (m *MemoryDB) DeleteCalendar(name string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.calendars, name)
+	return nil
+}
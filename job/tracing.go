@@ -0,0 +1,113 @@
+package job
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans go to the global tracer provider, which does nothing unless tracing
+// has been set up, e.g. with kala serve --otlp-endpoint.
+var tracer = otel.Tracer("github.com/ajvb/kala/job")
+
+// startRunSpan starts the span of a run of the job. Runs triggered by a parent
+// job in a workflow run are children of the parent's run. It must be called
+// with the job's lock held.
+func startRunSpan(ctx context.Context, j *Job, opts runOptions) (context.Context, trace.Span) {
+	if opts.parentSpan.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, opts.parentSpan)
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("kala.job.id", j.Id),
+		attribute.String("kala.job.name", j.Name),
+		attribute.String("kala.job.type", jobTypeName(j.JobType)),
+		attribute.String("kala.run.trigger_source", string(opts.source)),
+	}
+	if opts.jobRun != nil {
+		attrs = append(attrs, attribute.String("kala.run.id", opts.jobRun.Id))
+	}
+	if opts.workflow != nil {
+		attrs = append(attrs, attribute.String("kala.workflow_run.id", opts.workflow.Id))
+	}
+	if opts.triggeredBy != "" {
+		attrs = append(attrs, attribute.String("kala.run.triggered_by", opts.triggeredBy))
+	}
+	if opts.backfillId != "" {
+		attrs = append(attrs, attribute.String("kala.backfill.id", opts.backfillId))
+	}
+	return tracer.Start(ctx, "job.run "+j.Name, trace.WithAttributes(attrs...))
+}
+
+// endRunSpan ends the span of a run with how the run went.
+func endRunSpan(span trace.Span, stat *JobStat, err error) {
+	status := runStatus(stat, err)
+	span.SetAttributes(attribute.String("kala.run.status", string(status)))
+	if stat != nil {
+		span.SetAttributes(attribute.Int("kala.run.retries", int(stat.NumberOfRetries)))
+	}
+	if status != RunSucceeded && status != RunSkipped && err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startAttemptSpan starts the span of an attempt of the run, as a child of the
+// run's span.
+func startAttemptSpan(ctx context.Context, attempt int) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "job.attempt", trace.WithAttributes(
+		attribute.Int("kala.attempt", attempt),
+	))
+}
+
+// endAttemptSpan ends the span of an attempt with how the attempt went.
+func endAttemptSpan(span trace.Span, attempt *RunAttempt) {
+	if attempt.ExitCode != nil {
+		span.SetAttributes(attribute.Int("kala.attempt.exit_code", *attempt.ExitCode))
+	}
+	if attempt.StatusCode != 0 {
+		span.SetAttributes(attribute.Int("http.status_code", attempt.StatusCode))
+	}
+	if attempt.Error != "" {
+		span.SetStatus(codes.Error, attempt.Error)
+	}
+	span.End()
+}
+
+// injectTraceContext adds the trace context of ctx to the headers of a
+// request, so that the server handling it can carry on the trace.
+func injectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// traceEnv returns the trace context of ctx as environment variables, e.g.
+// TRACEPARENT, for commands that can carry on the trace.
+func traceEnv(ctx context.Context) []string {
+	carrier := propagation.HeaderCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	env := []string{}
+	for _, k := range carrier.Keys() {
+		env = append(env, strings.ToUpper(k)+"="+carrier.Get(k))
+	}
+	sort.Strings(env)
+	return env
+}
+
+func jobTypeName(t jobType) string {
+	switch t {
+	case LocalJob:
+		return "local"
+	case RemoteJob:
+		return "remote"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
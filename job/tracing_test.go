@@ -0,0 +1,156 @@
+package job
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	spanRecorder     *tracetest.SpanRecorder
+	spanRecorderOnce sync.Once
+)
+
+// recordSpans sets up tracing to a recorder of the spans that the tests can
+// check. The global tracer provider is only ever set once, as the tracers
+// already handed out keep using the first one.
+func recordSpans() *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	return spanRecorder
+}
+
+// spansOf returns the ended spans of runs of the job, and of their attempts.
+func spansOf(sr *tracetest.SpanRecorder, j *Job) (runs, attempts []sdktrace.ReadOnlySpan) {
+	ids := map[string]bool{}
+	for _, s := range sr.Ended() {
+		for _, kv := range s.Attributes() {
+			if kv.Key == "kala.job.id" && kv.Value.AsString() == j.Id {
+				runs = append(runs, s)
+				ids[s.SpanContext().SpanID().String()] = true
+			}
+		}
+	}
+	for _, s := range sr.Ended() {
+		if s.Name() == "job.attempt" && ids[s.Parent().SpanID().String()] {
+			attempts = append(attempts, s)
+		}
+	}
+	return runs, attempts
+}
+
+func spanAttribute(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestRunSpans(t *testing.T) {
+	sr := recordSpans()
+	cache := NewMockCache()
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "false"
+	j.Retries = 1
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+
+	runs, attempts := spansOf(sr, j)
+	if !assert.Len(t, runs, 1) {
+		return
+	}
+	run := runs[0]
+	assert.Equal(t, "job.run "+j.Name, run.Name())
+	assert.Equal(t, codes.Error, run.Status().Code)
+	assert.Equal(t, "failed", spanAttribute(run, "kala.run.status").AsString())
+	assert.Equal(t, "manual", spanAttribute(run, "kala.run.trigger_source").AsString())
+	j.lock.RLock()
+	assert.Equal(t, j.Stats[0].Id, spanAttribute(run, "kala.run.id").AsString())
+	j.lock.RUnlock()
+
+	if assert.Len(t, attempts, 2) {
+		for i, attempt := range attempts {
+			assert.Equal(t, run.SpanContext().TraceID(), attempt.SpanContext().TraceID())
+			assert.Equal(t, int64(i+1), spanAttribute(attempt, "kala.attempt").AsInt64())
+			assert.Equal(t, int64(1), spanAttribute(attempt, "kala.attempt.exit_code").AsInt64())
+			assert.Equal(t, codes.Error, attempt.Status().Code)
+		}
+	}
+}
+
+func TestDependentRunSpans(t *testing.T) {
+	sr := recordSpans()
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	root.succeedInstantly = true
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+
+	// The trace context is passed on to the child's command.
+	child := GetMockJob()
+	child.Retries = 0
+	child.Command = "printenv TRACEPARENT"
+	child.ParentJobs = []string{root.Id}
+	assert.NoError(t, child.Init(cache))
+
+	root.Run(cache)
+	waitForWorkflowRun(t, cache)
+
+	rootRuns, _ := spansOf(sr, root)
+	childRuns, childAttempts := spansOf(sr, child)
+	if !assert.Len(t, rootRuns, 1) || !assert.Len(t, childRuns, 1) {
+		return
+	}
+	assert.Equal(t, rootRuns[0].SpanContext().SpanID(), childRuns[0].Parent().SpanID())
+	assert.Equal(t, rootRuns[0].SpanContext().TraceID(), childRuns[0].SpanContext().TraceID())
+	assert.Equal(t, "dependency", spanAttribute(childRuns[0], "kala.run.trigger_source").AsString())
+	assert.Equal(t, codes.Unset, childRuns[0].Status().Code)
+	assert.Len(t, childAttempts, 1)
+}
+
+func TestRemoteRunSendsTraceContext(t *testing.T) {
+	sr := recordSpans()
+	traceparent := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent <- r.Header.Get("Traceparent")
+	}))
+	defer srv.Close()
+
+	cache := NewMockCache()
+	j := GetMockRemoteJob(RemoteProperties{Url: srv.URL})
+	j.Schedule = "R/" + time.Now().Add(time.Hour).Format(time.RFC3339) + "/PT1H"
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+
+	_, attempts := spansOf(sr, j)
+	if !assert.Len(t, attempts, 1) {
+		return
+	}
+	sc := attempts[0].SpanContext()
+	assert.Equal(t, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", <-traceparent)
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(attempts[0], "http.status_code").AsInt64())
+
+	// The trace context isn't kept with the job's headers.
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	assert.Empty(t, j.RemoteProperties.Headers.Get("Traceparent"))
+}
@@ -0,0 +1,196 @@
+package job
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrInvalidWebhook = errors.New("Invalid webhook. Webhooks need an http or https url, and supported events")
+
+// Headers of the requests that deliver events to webhooks.
+const (
+	WebhookEventHeader     = "X-Kala-Event"
+	WebhookDeliveryHeader  = "X-Kala-Delivery"
+	WebhookSignatureHeader = "X-Kala-Signature"
+)
+
+// How long a webhook has to respond, if the notifier has no client of its own.
+const defaultWebhookTimeout = 10 * time.Second
+
+// Webhook is a URL that events are POSTed to as JSON.
+type Webhook struct {
+	Url string `json:"url"`
+
+	// Events sent to the webhook, all of them if empty.
+	Events []EventType `json:"events,omitempty"`
+
+	// Ids of the jobs whose events are sent to the webhook, all of them if
+	// empty. Only used by the notifier's own webhooks, as the webhooks of a
+	// job only get that job's events.
+	Jobs []string `json:"jobs,omitempty"`
+
+	// If set, requests are signed with an HMAC-SHA256 of the body keyed
+	// with the secret, in the X-Kala-Signature header. It is write-only: the
+	// API never returns it.
+	Secret string `json:"secret,omitempty"`
+}
+
+// MarshalJSON leaves out the secret, so that jobs can be shown without giving
+// it away. Job databases keep it by saving Job.StorageJSON instead.
+func (w *Webhook) MarshalJSON() ([]byte, error) {
+	type webhook Webhook
+	redacted := webhook(*w)
+	redacted.Secret = ""
+	return json.Marshal(&redacted)
+}
+
+// Validate checks that the webhook has a usable url and known events.
+func (w *Webhook) Validate() error {
+	if w == nil {
+		return ErrInvalidWebhook
+	}
+	u, err := url.Parse(w.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidWebhook, w.Url)
+	}
+	for _, t := range w.Events {
+		if !t.valid() {
+			return fmt.Errorf("%w: unknown event %s", ErrInvalidWebhook, t)
+		}
+	}
+	return nil
+}
+
+// wants returns whether the event should be sent to the webhook.
+func (w *Webhook) wants(e *Event) bool {
+	if len(w.Jobs) > 0 && !containsId(w.Jobs, e.JobId) {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, t := range w.Events {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSignature returns the signature of a request body sent to a webhook
+// with the secret, as found in the X-Kala-Signature header.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookNotifier POSTs events to its webhooks, and to the webhooks of the
+// jobs they are about. Failed deliveries are retried in the background.
+type WebhookNotifier struct {
+	// Webhooks that get the events of all jobs, subject to their filters.
+	Hooks []*Webhook
+
+	// How many times a failed delivery is retried, and how long to wait
+	// before the first retry. The wait doubles with every retry.
+	Retries    int
+	RetryDelay time.Duration
+
+	// The client requests are sent with. Defaults to one with a timeout of
+	// 10 seconds.
+	Client *http.Client
+
+	deliveries sync.WaitGroup
+}
+
+func (n *WebhookNotifier) Notify(e *Event) {
+	hooks := []*Webhook{}
+	for _, w := range n.Hooks {
+		if w.wants(e) {
+			hooks = append(hooks, w)
+		}
+	}
+	for _, w := range e.webhooks {
+		if w.wants(e) {
+			hooks = append(hooks, w)
+		}
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		log.WithField(LogFieldJobId, e.JobId).Errorf("Error occurred when marshaling event: %s", err)
+		return
+	}
+	for _, w := range hooks {
+		n.deliveries.Add(1)
+		go n.deliver(w, e, body)
+	}
+}
+
+// Wait waits for the deliveries in progress, retries included, to finish.
+func (n *WebhookNotifier) Wait() {
+	n.deliveries.Wait()
+}
+
+func (n *WebhookNotifier) deliver(w *Webhook, e *Event, body []byte) {
+	defer n.deliveries.Done()
+
+	delay := n.RetryDelay
+	for retry := 0; ; retry++ {
+		err := n.post(w, e, body)
+		if err == nil {
+			return
+		}
+		entry := log.WithFields(log.Fields{LogFieldJobId: e.JobId, "event_id": e.Id, "url": w.Url}).WithError(err)
+		if retry >= n.Retries {
+			entry.Errorf("Webhook delivery of %s failed, giving up.", e.Type)
+			return
+		}
+		entry.Warnf("Webhook delivery of %s failed, retrying in %s.", e.Type, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (n *WebhookNotifier) post(w *Webhook, e *Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(e.Type))
+	req.Header.Set(WebhookDeliveryHeader, e.Id)
+	if w.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(w.Secret, body))
+	}
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package job
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingNotifier keeps the events it is told about.
+type recordingNotifier struct {
+	lock   sync.Mutex
+	events []*Event
+}
+
+func (n *recordingNotifier) Notify(e *Event) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.events = append(n.events, e)
+}
+
+func (n *recordingNotifier) types() []EventType {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	types := []EventType{}
+	for _, e := range n.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+// webhookRequest is a request a webhook server got.
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer starts a server that keeps the requests it gets, and
+// responds to them with the given status codes in turn, then 200.
+func newWebhookServer(codes ...int) (*httptest.Server, func() []webhookRequest) {
+	var lock sync.Mutex
+	requests := []webhookRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		requests = append(requests, webhookRequest{header: r.Header, body: body})
+		code := http.StatusOK
+		if len(codes) > 0 {
+			code, codes = codes[0], codes[1:]
+		}
+		lock.Unlock()
+		w.WriteHeader(code)
+	}))
+	return srv, func() []webhookRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]webhookRequest(nil), requests...)
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	n := &recordingNotifier{}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.Command = "bash -c 'echo oops; false'"
+	j.Retries = 1
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	assert.Equal(t, []EventType{EventJobStarted, EventJobFailed}, n.types())
+	failed := n.events[1]
+	j.lock.RLock()
+	assert.Equal(t, j.Stats[0].Id, failed.RunId)
+	j.lock.RUnlock()
+	assert.Equal(t, j.Id, failed.JobId)
+	assert.Equal(t, j.Owner, failed.Owner)
+	assert.Equal(t, RunFailed, failed.Status)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, "oops", failed.Output)
+	assert.NotEmpty(t, failed.Error)
+
+	assert.NoError(t, j.Disable(cache))
+	assert.Equal(t, EventJobDisabled, n.types()[2])
+}
+
+func TestScheduleExhaustedEvent(t *testing.T) {
+	n := &recordingNotifier{}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+
+	j := GetMockJobWithSchedule(0, time.Now().Add(time.Hour), "PT1H")
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	j.Run(cache)
+	assert.Equal(t, []EventType{
+		EventJobStarted, EventJobSucceeded, EventScheduleExhausted,
+		EventJobStarted, EventJobSucceeded,
+	}, n.types())
+}
+
+func TestDisabledRunDoesntExhaustSchedule(t *testing.T) {
+	n := &recordingNotifier{}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+
+	j := GetMockJobWithSchedule(0, time.Now().Add(time.Hour), "PT1H")
+	j.succeedInstantly = true
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	assert.NoError(t, j.Disable(cache))
+	j.Run(cache)
+	assert.Equal(t, []EventType{EventJobDisabled}, n.types())
+	j.lock.RLock()
+	assert.False(t, j.IsDone)
+	j.lock.RUnlock()
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	global, globalRequests := newWebhookServer()
+	defer global.Close()
+	own, ownRequests := newWebhookServer()
+	defer own.Close()
+
+	n := &WebhookNotifier{Hooks: []*Webhook{{Url: global.URL, Secret: "s3cr3t"}}}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.succeedInstantly = true
+	j.Webhooks = []*Webhook{{Url: own.URL, Events: []EventType{EventJobSucceeded}}}
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	n.Wait()
+
+	// The global webhook gets every event, signed.
+	requests := globalRequests()
+	if assert.Len(t, requests, 2) {
+		for _, r := range requests {
+			assert.Equal(t, WebhookSignature("s3cr3t", r.body), r.header.Get(WebhookSignatureHeader))
+			e := &Event{}
+			assert.NoError(t, json.Unmarshal(r.body, e))
+			assert.Equal(t, j.Id, e.JobId)
+			assert.Equal(t, string(e.Type), r.header.Get(WebhookEventHeader))
+			assert.Equal(t, e.Id, r.header.Get(WebhookDeliveryHeader))
+		}
+	}
+
+	// The job's own webhook only gets the events it subscribed to.
+	requests = ownRequests()
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, string(EventJobSucceeded), requests[0].header.Get(WebhookEventHeader))
+		assert.Empty(t, requests[0].header.Get(WebhookSignatureHeader))
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	srv, requests := newWebhookServer(http.StatusInternalServerError, http.StatusBadGateway)
+	defer srv.Close()
+
+	n := &WebhookNotifier{
+		Hooks:      []*Webhook{{Url: srv.URL, Events: []EventType{EventJobDisabled}}},
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+	}
+	cache := NewMockCache()
+	cache.Notifiers = []Notifier{n}
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, j.Init(cache))
+
+	assert.NoError(t, j.Disable(cache))
+	n.Wait()
+	assert.Len(t, requests(), 3)
+
+	// Deliveries are given up on after the retries.
+	srv2, requests2 := newWebhookServer(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer srv2.Close()
+	n.Hooks[0].Url = srv2.URL
+	n.Retries = 1
+	assert.NoError(t, j.Disable(cache))
+	n.Wait()
+	assert.Len(t, requests2(), 2)
+}
+
+func TestWebhookFilters(t *testing.T) {
+	w := &Webhook{Url: "http://localhost", Events: []EventType{EventJobFailed}, Jobs: []string{"a"}}
+	assert.True(t, w.wants(&Event{Type: EventJobFailed, JobId: "a"}))
+	assert.False(t, w.wants(&Event{Type: EventJobFailed, JobId: "b"}))
+	assert.False(t, w.wants(&Event{Type: EventJobStarted, JobId: "a"}))
+	assert.True(t, (&Webhook{Url: "http://localhost"}).wants(&Event{Type: EventJobStarted, JobId: "b"}))
+}
+
+func TestWebhookValidation(t *testing.T) {
+	j := GetMockJob()
+	j.Webhooks = []*Webhook{{Url: "https://example.com/hook", Events: []EventType{EventJobFailed}}}
+	assert.NoError(t, j.validation())
+
+	for _, w := range []*Webhook{
+		{Url: "example.com/hook"},
+		{Url: "ftp://example.com/hook"},
+		{Url: "https://example.com/hook", Events: []EventType{"job.exploded"}},
+		nil,
+	} {
+		j.Webhooks = []*Webhook{w}
+		assert.ErrorIs(t, j.validation(), ErrInvalidWebhook)
+	}
+}
+
+func TestWebhookSecretIsWriteOnly(t *testing.T) {
+	j := GetMockJob()
+	j.Webhooks = []*Webhook{{Url: "https://example.com/hook", Secret: "s3cr3t"}}
+
+	b, err := json.Marshal(j)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "s3cr3t")
+
+	// Job databases keep it.
+	b, err = j.StorageJSON()
+	assert.NoError(t, err)
+	stored := &Job{}
+	assert.NoError(t, json.Unmarshal(b, stored))
+	assert.Equal(t, j.Id, stored.Id)
+	if assert.Len(t, stored.Webhooks, 1) {
+		assert.Equal(t, "s3cr3t", stored.Webhooks[0].Secret)
+	}
+}
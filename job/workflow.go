@@ -0,0 +1,483 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mixer/clock"
+	uuid "github.com/nu7hatch/gouuid"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxWorkflowRuns is how many workflow runs are kept for looking up.
+const MaxWorkflowRuns = 1000
+
+var (
+	ErrWorkflowRunDoesntExist = errors.New("The workflow run you requested does not exist")
+	ErrDependencyCycle        = errors.New("Invalid Job. Its parent and dependent jobs form a cycle")
+	ErrInvalidDependencyMode  = errors.New("Invalid dependency mode. Modes supported: all and any")
+	ErrInvalidTrigger         = errors.New("Invalid trigger. Triggers must be for parent jobs, and on success, failure or completion")
+)
+
+// DependencyMode decides when a job with several parent jobs runs within a
+// workflow run.
+type DependencyMode string
+
+const (
+	// Run once all of the parent jobs in the workflow run have succeeded.
+	DependOnAll DependencyMode = "all"
+	// Run as soon as one of the parent jobs in the workflow run has succeeded.
+	DependOnAny DependencyMode = "any"
+)
+
+// TriggerCondition is how a parent job's run needs to have gone for a
+// dependency edge to fire.
+type TriggerCondition string
+
+const (
+	TriggerOnSuccess    TriggerCondition = "success"
+	TriggerOnFailure    TriggerCondition = "failure"
+	TriggerOnCompletion TriggerCondition = "completion"
+)
+
+// Trigger decides whether a job runs after one of its parent jobs, depending
+// on how the parent's run went.
+type Trigger struct {
+	// Defaults to "success", or to "completion" if Codes is set.
+	On TriggerCondition `json:"on"`
+
+	// If set, the parent's exit code (for local jobs) or HTTP status code (for
+	// remote jobs) also needs to be one of these.
+	Codes []int `json:"codes"`
+}
+
+// fires reports whether the edge fires after a parent job finished as given.
+func (t Trigger) fires(parent *WorkflowJob) bool {
+	on := t.On
+	if on == "" && len(t.Codes) != 0 {
+		on = TriggerOnCompletion
+	}
+	switch {
+	case parent.Status == WorkflowSucceeded && (on == "" || on == TriggerOnSuccess || on == TriggerOnCompletion):
+	case parent.Status == WorkflowFailed && (on == TriggerOnFailure || on == TriggerOnCompletion):
+	default:
+		return false
+	}
+	if len(t.Codes) == 0 {
+		return true
+	}
+	for _, c := range t.Codes {
+		if parent.Code != nil && *parent.Code == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Trigger) valid() bool {
+	return t.On == "" || t.On == TriggerOnSuccess || t.On == TriggerOnFailure || t.On == TriggerOnCompletion
+}
+
+type WorkflowStatus string
+
+const (
+	WorkflowPending   WorkflowStatus = "pending"
+	WorkflowRunning   WorkflowStatus = "running"
+	WorkflowSucceeded WorkflowStatus = "succeeded"
+	WorkflowFailed    WorkflowStatus = "failed"
+	// The job was cancelled while running. None of its dependent jobs run.
+	WorkflowCancelled WorkflowStatus = "cancelled"
+	// The job didn't run, because none or not all of the triggers from its
+	// parent jobs fired, or it is disabled or deleted.
+	WorkflowSkipped WorkflowStatus = "skipped"
+)
+
+// WorkflowRun is one run of a job together with the jobs that depend on it,
+// directly or not. Each job runs at most once per workflow run.
+type WorkflowRun struct {
+	Id         string                  `json:"id"`
+	RootJob    string                  `json:"root_job"`
+	Status     WorkflowStatus          `json:"status"`
+	StartedAt  time.Time               `json:"started_at"`
+	FinishedAt time.Time               `json:"finished_at"`
+	Jobs       map[string]*WorkflowJob `json:"jobs"`
+
+	// The edges of the workflow, by job id.
+	parents  map[string][]string
+	children map[string][]string
+	triggers map[string]map[string]Trigger // by child, then parent
+	modes    map[string]DependencyMode
+
+	clk  clock.Clock
+	lock sync.RWMutex
+	done chan struct{}
+}
+
+// WorkflowJob is the state of a job within a workflow run.
+type WorkflowJob struct {
+	Name       string         `json:"name"`
+	Status     WorkflowStatus `json:"status"`
+	FinishedAt time.Time      `json:"finished_at"`
+
+	// Id of the job's run, once it has run.
+	RunId string `json:"run_id,omitempty"`
+
+	// Exit code of a local job, or HTTP status code of a remote job, once
+	// it has finished. Unset if it couldn't be told.
+	Code *int `json:"code,omitempty"`
+
+	// What the job's run output, kept for its dependent jobs until the
+	// workflow run finishes.
+	output *ParentOutput
+	// The span of the job's run, which its dependent jobs' runs are
+	// children of.
+	spanContext trace.SpanContext
+}
+
+// ParentOutput is what a parent job's run in the workflow run output. It is
+// passed to the jobs depending on it.
+type ParentOutput struct {
+	Id     string
+	Name   string
+	Output string
+	// The output parsed as JSON, or nil if it isn't JSON.
+	JSON interface{}
+}
+
+func newParentOutput(id, name, output string) *ParentOutput {
+	o := &ParentOutput{Id: id, Name: name, Output: output}
+	if err := json.Unmarshal([]byte(output), &o.JSON); err != nil {
+		o.JSON = nil
+	}
+	return o
+}
+
+// WorkflowCache is implemented by job caches that keep workflow runs.
+type WorkflowCache interface {
+	GetWorkflowRun(id string) (*WorkflowRun, error)
+	GetAllWorkflowRuns() []*WorkflowRun
+}
+
+// workflowTracker is implemented by caches that keep workflow runs for
+// looking up.
+type workflowTracker interface {
+	addWorkflowRun(wf *WorkflowRun)
+}
+
+// startWorkflowRun starts a workflow run rooted at the given job, made up of
+// all the jobs that depend on it, including on failure jobs. It returns nil if
+// no jobs depend on it.
+func startWorkflowRun(cache JobCache, root *Job) *WorkflowRun {
+	root.lock.RLock()
+	hasDependents := len(root.DependentJobs) != 0 || root.OnFailureJob != ""
+	root.lock.RUnlock()
+	if !hasDependents {
+		return nil
+	}
+
+	u4, err := uuid.NewV4()
+	if err != nil {
+		log.Errorf("Error occurred when generating uuid: %s", err)
+		return nil
+	}
+	wf := &WorkflowRun{
+		Id:       u4.String(),
+		RootJob:  root.Id,
+		Status:   WorkflowRunning,
+		Jobs:     map[string]*WorkflowJob{},
+		parents:  map[string][]string{},
+		children: map[string][]string{},
+		triggers: map[string]map[string]Trigger{},
+		done:     make(chan struct{}),
+		modes:    map[string]DependencyMode{},
+		clk:      root.clk.Time(),
+	}
+	wf.StartedAt = wf.clk.Now()
+
+	// Walk the jobs that depend on the root, breadth first.
+	queue := []*Job{root}
+	for len(queue) > 0 {
+		j := queue[0]
+		queue = queue[1:]
+
+		j.lock.RLock()
+		wf.Jobs[j.Id] = &WorkflowJob{Name: j.Name, Status: WorkflowPending}
+		wf.modes[j.Id] = j.DependencyMode
+		for parentId, t := range j.Triggers {
+			if wf.triggers[j.Id] == nil {
+				wf.triggers[j.Id] = map[string]Trigger{}
+			}
+			if _, ok := wf.triggers[j.Id][parentId]; !ok {
+				wf.triggers[j.Id][parentId] = *t
+			}
+		}
+		dependents := append([]string(nil), j.DependentJobs...)
+		onFailure := j.OnFailureJob
+		j.lock.RUnlock()
+
+		// The on failure job is a dependent triggered by failure, unless it
+		// depends on the job anyway.
+		if onFailure != "" && !containsId(dependents, onFailure) {
+			dependents = append(dependents, onFailure)
+			if wf.triggers[onFailure] == nil {
+				wf.triggers[onFailure] = map[string]Trigger{}
+			}
+			wf.triggers[onFailure][j.Id] = Trigger{On: TriggerOnFailure}
+		}
+
+		for _, id := range dependents {
+			if _, seen := wf.Jobs[id]; !seen {
+				child, err := cache.Get(id)
+				if err != nil {
+					log.WithField("workflow_run_id", wf.Id).Errorf("Error retrieving dependent job with id of %s", id)
+					continue
+				}
+				// Reserved here so the job is only queued once.
+				wf.Jobs[id] = &WorkflowJob{}
+				queue = append(queue, child)
+			}
+			wf.parents[id] = append(wf.parents[id], j.Id)
+			wf.children[j.Id] = append(wf.children[j.Id], id)
+		}
+	}
+	wf.Jobs[root.Id].Status = WorkflowRunning
+
+	if t, ok := cache.(workflowTracker); ok {
+		t.addWorkflowRun(wf)
+	}
+	root.logger().WithField("workflow_run_id", wf.Id).Infof("Job started workflow run of %d jobs.", len(wf.Jobs))
+	return wf
+}
+
+// jobFinished records how a job of the workflow run went, and runs the jobs
+// that are ready to as a result. The runner is nil if the job didn't get to
+// run. It does nothing if wf is nil.
+func (wf *WorkflowRun) jobFinished(cache JobCache, id string, status WorkflowStatus, runner *JobRunner) {
+	if wf == nil {
+		return
+	}
+	wf.lock.Lock()
+	if runner != nil && runner.currentStat != nil {
+		wf.Jobs[id].RunId = runner.currentStat.Id
+		wf.Jobs[id].Code = runner.currentStat.code()
+		wf.Jobs[id].output = newParentOutput(id, wf.Jobs[id].Name, runner.output)
+		wf.Jobs[id].spanContext = trace.SpanContextFromContext(runner.context())
+	}
+	ready := wf.settle(id, status)
+	wf.lock.Unlock()
+
+	for _, r := range ready {
+		child, err := cache.Get(r.id)
+		if err != nil {
+			log.WithField("workflow_run_id", wf.Id).Errorf("Error retrieving dependent job with id of %s", r.id)
+			wf.jobFinished(cache, r.id, WorkflowSkipped, nil)
+			continue
+		}
+		wf.dispatch(cache, child, r.parent)
+	}
+}
+
+// readyJob is a job of the workflow run that is ready to run, and the parent
+// job whose finishing made it so.
+type readyJob struct {
+	id, parent string
+}
+
+// dispatch runs a job of the workflow run in a goroutine of its own, so that
+// the parent job that triggered it can carry on. The run is registered with
+// the cache straight away, so that shutting down waits for it.
+func (wf *WorkflowRun) dispatch(cache JobCache, j *Job, parent string) {
+	wf.lock.RLock()
+	opts := runOptions{
+		workflow:       wf,
+		triggeredBy:    parent,
+		triggeredByRun: wf.Jobs[parent].RunId,
+		ctx:            context.Background(),
+		source:         SourceDependency,
+		parentSpan:     wf.Jobs[parent].spanContext,
+	}
+	if wf.Jobs[parent].Status == WorkflowFailed {
+		opts.source = SourceFailure
+	}
+	wf.lock.RUnlock()
+
+	t, tracked := cache.(runTracker)
+	if tracked {
+		var accepted bool
+		if opts.ctx, accepted = t.startRun(); !accepted {
+			j.logger().Info("Job not started, as kala is shutting down.")
+			wf.jobFinished(cache, j.Id, WorkflowSkipped, nil)
+			return
+		}
+	}
+	go func() {
+		if tracked {
+			defer t.finishRun()
+		}
+		j.run(cache, opts)
+	}()
+}
+
+// settle sets the status of a finished job, and works out which of its
+// dependent jobs are now ready to run and which never will. It must be called
+// with the lock held.
+func (wf *WorkflowRun) settle(id string, status WorkflowStatus) []readyJob {
+	now := wf.clk.Now()
+	wf.Jobs[id].Status = status
+	wf.Jobs[id].FinishedAt = now
+
+	ready := []readyJob{}
+	for _, childId := range wf.children[id] {
+		child := wf.Jobs[childId]
+		if child.Status != WorkflowPending {
+			continue
+		}
+
+		var fired, unfinished int
+		for _, p := range wf.parents[childId] {
+			switch parent := wf.Jobs[p]; parent.Status {
+			case WorkflowPending, WorkflowRunning:
+				unfinished++
+			default:
+				if wf.triggers[childId][p].fires(parent) {
+					fired++
+				}
+			}
+		}
+		notFired := len(wf.parents[childId]) - fired - unfinished
+
+		switch {
+		case wf.modes[childId] == DependOnAny && fired > 0,
+			wf.modes[childId] != DependOnAny && notFired == 0 && unfinished == 0:
+			child.Status = WorkflowRunning
+			ready = append(ready, readyJob{id: childId, parent: id})
+		case wf.modes[childId] == DependOnAny && unfinished == 0,
+			wf.modes[childId] != DependOnAny && notFired > 0:
+			ready = append(ready, wf.settle(childId, WorkflowSkipped)...)
+		}
+	}
+
+	wf.finishIfDone(now)
+	return ready
+}
+
+func (wf *WorkflowRun) finishIfDone(now time.Time) {
+	if wf.Status != WorkflowRunning {
+		return
+	}
+	status := WorkflowSucceeded
+	for _, j := range wf.Jobs {
+		switch j.Status {
+		case WorkflowPending, WorkflowRunning:
+			return
+		case WorkflowFailed:
+			status = WorkflowFailed
+		case WorkflowCancelled:
+			if status == WorkflowSucceeded {
+				status = WorkflowCancelled
+			}
+		}
+	}
+	wf.Status = status
+	wf.FinishedAt = now
+	// Nothing is left to run that could use the outputs.
+	for _, j := range wf.Jobs {
+		j.output = nil
+	}
+	close(wf.done)
+	log.WithField("workflow_run_id", wf.Id).WithFields(logDuration(now.Sub(wf.StartedAt))).Infof("Workflow run %s.", status)
+}
+
+// Done returns a channel that is closed once every job of the workflow run has
+// finished or been skipped.
+func (wf *WorkflowRun) Done() <-chan struct{} {
+	return wf.done
+}
+
+// parentOutputs returns the outputs of the parent jobs of the given job that
+// ran in the workflow run. It does nothing if wf is nil.
+func (wf *WorkflowRun) parentOutputs(id string) []*ParentOutput {
+	if wf == nil {
+		return nil
+	}
+	wf.lock.RLock()
+	defer wf.lock.RUnlock()
+
+	outputs := []*ParentOutput{}
+	for _, p := range wf.parents[id] {
+		if o := wf.Jobs[p].output; o != nil {
+			outputs = append(outputs, o)
+		}
+	}
+	return outputs
+}
+
+// MarshalJSON locks the workflow run while it is marshaled.
+func (wf *WorkflowRun) MarshalJSON() ([]byte, error) {
+	wf.lock.RLock()
+	defer wf.lock.RUnlock()
+
+	type alias WorkflowRun
+	return json.Marshal(&struct {
+		*alias
+		Jobs map[string]WorkflowJob `json:"jobs"`
+	}{
+		alias: (*alias)(wf),
+		Jobs:  wf.jobsCopy(),
+	})
+}
+
+func (wf *WorkflowRun) jobsCopy() map[string]WorkflowJob {
+	jobs := make(map[string]WorkflowJob, len(wf.Jobs))
+	for id, j := range wf.Jobs {
+		jobs[id] = *j
+	}
+	return jobs
+}
+
+// checkDependencyCycle returns ErrDependencyCycle if one of the jobs the job
+// is to depend on also depends on it, directly or not.
+func (j *Job) checkDependencyCycle(cache JobCache) error {
+	parents := make(map[string]struct{}, len(j.ParentJobs)+1)
+	for _, id := range j.ParentJobs {
+		parents[id] = struct{}{}
+	}
+	if j.Id != "" {
+		parents[j.Id] = struct{}{}
+	}
+
+	seen := map[string]struct{}{}
+	queue := append([]string(nil), j.DependentJobs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := parents[id]; ok {
+			return ErrDependencyCycle
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		child, err := cache.Get(id)
+		if err != nil {
+			continue
+		}
+		child.lock.RLock()
+		queue = append(queue, child.DependentJobs...)
+		child.lock.RUnlock()
+	}
+	return nil
+}
+
+// sortWorkflowRuns sorts workflow runs newest first.
+func sortWorkflowRuns(runs []*WorkflowRun) {
+	sort.SliceStable(runs, func(a, b int) bool {
+		return runs[a].StartedAt.After(runs[b].StartedAt)
+	})
+}
@@ -0,0 +1,310 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWorkflowRun waits for the workflow runs in the cache to finish, and
+// returns the latest.
+func waitForWorkflowRun(t *testing.T, cache *LockFreeJobCache) *WorkflowRun {
+	t.Helper()
+	runs := cache.GetAllWorkflowRuns()
+	if len(runs) == 0 {
+		t.Fatal("No workflow run was started")
+	}
+	for _, wf := range runs {
+		select {
+		case <-wf.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("Workflow run didn't finish")
+		}
+	}
+	return runs[0]
+}
+
+// newDiamond sets up a job with two dependent jobs, and a job depending on
+// both of those.
+func newDiamond(t *testing.T, cache *LockFreeJobCache, configure func(left, join *Job)) (root, left, right, join *Job) {
+	t.Helper()
+
+	root = GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	root.succeedInstantly = true
+	assert.NoError(t, root.Init(cache))
+
+	left, right, join = GetMockJob(), GetMockJob(), GetMockJob()
+	left.succeedInstantly, right.succeedInstantly, join.succeedInstantly = true, true, true
+	configure(left, join)
+
+	left.ParentJobs = []string{root.Id}
+	assert.NoError(t, left.Init(cache))
+	right.ParentJobs = []string{root.Id}
+	assert.NoError(t, right.Init(cache))
+	join.ParentJobs = []string{left.Id, right.Id}
+	assert.NoError(t, join.Init(cache))
+	return root, left, right, join
+}
+
+func TestWorkflowRunWaitsForAllParents(t *testing.T) {
+	cache := NewMockCache()
+	root, left, right, join := newDiamond(t, cache, func(_, _ *Job) {})
+	defer root.StopTimer()
+
+	root.Run(cache)
+	wf := waitForWorkflowRun(t, cache)
+
+	join.lock.RLock()
+	defer join.lock.RUnlock()
+	if !assert.Len(t, join.Stats, 1) {
+		return
+	}
+	runId := join.Stats[0].WorkflowRunId
+	assert.NotEmpty(t, runId)
+	triggeredBy := map[string]string{}
+	for _, j := range []*Job{root, left, right} {
+		j.lock.RLock()
+		assert.Equal(t, runId, j.Stats[0].WorkflowRunId)
+		triggeredBy[j.Id] = j.Stats[0].Id
+		j.lock.RUnlock()
+	}
+
+	// The join was triggered by whichever of its parents finished last.
+	assert.Contains(t, []string{left.Id, right.Id}, join.Stats[0].TriggeredBy)
+	assert.Equal(t, triggeredBy[join.Stats[0].TriggeredBy], join.Stats[0].TriggeredByRun)
+	left.lock.RLock()
+	assert.Equal(t, root.Id, left.Stats[0].TriggeredBy)
+	left.lock.RUnlock()
+
+	found, err := cache.GetWorkflowRun(runId)
+	assert.NoError(t, err)
+	assert.Equal(t, wf, found)
+	assert.Equal(t, WorkflowSucceeded, wf.Status)
+	assert.Equal(t, root.Id, wf.RootJob)
+	assert.Len(t, wf.Jobs, 4)
+	assert.Equal(t, WorkflowSucceeded, wf.Jobs[join.Id].Status)
+	assert.Equal(t, []*WorkflowRun{wf}, cache.GetAllWorkflowRuns())
+}
+
+func TestWorkflowRunDoesntWaitForDependentJobs(t *testing.T) {
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	root.succeedInstantly = true
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+
+	child := GetMockJob()
+	child.Command = "sleep 1"
+	child.ParentJobs = []string{root.Id}
+	assert.NoError(t, child.Init(cache))
+
+	start := time.Now()
+	root.Run(cache)
+	assert.WithinDuration(t, start, time.Now(), 500*time.Millisecond)
+
+	wf := waitForWorkflowRun(t, cache)
+	assert.Equal(t, WorkflowSucceeded, wf.Status)
+	child.lock.RLock()
+	assert.Len(t, child.Stats, 1)
+	child.lock.RUnlock()
+}
+
+func TestWorkflowRunSkipsJobsAfterFailedParent(t *testing.T) {
+	cache := NewMockCache()
+	root, left, _, join := newDiamond(t, cache, func(left, _ *Job) {
+		left.succeedInstantly = false
+		left.Command = "false"
+		left.Retries = 0
+	})
+	defer root.StopTimer()
+
+	root.Run(cache)
+	wf := waitForWorkflowRun(t, cache)
+
+	join.lock.RLock()
+	assert.Empty(t, join.Stats)
+	join.lock.RUnlock()
+
+	assert.Equal(t, WorkflowFailed, wf.Status)
+	assert.Equal(t, WorkflowFailed, wf.Jobs[left.Id].Status)
+	assert.Equal(t, WorkflowSkipped, wf.Jobs[join.Id].Status)
+}
+
+func TestWorkflowRunWithAnyParent(t *testing.T) {
+	cache := NewMockCache()
+	root, _, _, join := newDiamond(t, cache, func(left, join *Job) {
+		left.succeedInstantly = false
+		left.Command = "false"
+		left.Retries = 0
+		join.DependencyMode = DependOnAny
+	})
+	defer root.StopTimer()
+
+	root.Run(cache)
+	wf := waitForWorkflowRun(t, cache)
+
+	// Runs once, after the parent that succeeded.
+	join.lock.RLock()
+	assert.Len(t, join.Stats, 1)
+	join.lock.RUnlock()
+	assert.Equal(t, WorkflowFailed, wf.Status)
+}
+
+func TestDependencyCycleIsRejected(t *testing.T) {
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+	child := GetMockJob()
+	child.ParentJobs = []string{root.Id}
+	assert.NoError(t, child.Init(cache))
+
+	j := GetMockJob()
+	j.ParentJobs = []string{child.Id}
+	j.DependentJobs = []string{root.Id}
+	assert.ErrorIs(t, j.Init(cache), ErrDependencyCycle)
+
+	j.DependencyMode = "most"
+	errs := j.Validate(cache)
+	assert.Contains(t, errs, ErrDependencyCycle)
+	assert.Contains(t, errs, ErrInvalidDependencyMode)
+}
+
+func TestWorkflowRunTriggers(t *testing.T) {
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	root.Command = "bash -c 'exit 3'"
+	root.Retries = 0
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+
+	children := map[string]*Trigger{
+		"success":    nil,
+		"failure":    {On: TriggerOnFailure},
+		"completion": {On: TriggerOnCompletion},
+		"exit 3":     {Codes: []int{1, 3}},
+		"exit 1":     {Codes: []int{1}},
+		"success 3":  {On: TriggerOnSuccess, Codes: []int{3}},
+	}
+	jobs := map[string]*Job{}
+	for name, trigger := range children {
+		j := GetMockJob()
+		j.Name = name
+		j.succeedInstantly = true
+		j.ParentJobs = []string{root.Id}
+		if trigger != nil {
+			j.Triggers = map[string]*Trigger{root.Id: trigger}
+		}
+		assert.NoError(t, j.Init(cache))
+		jobs[name] = j
+	}
+
+	root.Run(cache)
+	wf := waitForWorkflowRun(t, cache)
+
+	ran := map[string]bool{}
+	for name, j := range jobs {
+		j.lock.RLock()
+		ran[name] = len(j.Stats) == 1
+		j.lock.RUnlock()
+	}
+	assert.Equal(t, map[string]bool{
+		"success":    false,
+		"failure":    true,
+		"completion": true,
+		"exit 3":     true,
+		"exit 1":     false,
+		"success 3":  false,
+	}, ran)
+
+	assert.Equal(t, WorkflowFailed, wf.Status)
+	if assert.NotNil(t, wf.Jobs[root.Id].Code) {
+		assert.Equal(t, 3, *wf.Jobs[root.Id].Code)
+	}
+	assert.Equal(t, WorkflowSkipped, wf.Jobs[jobs["exit 1"].Id].Status)
+}
+
+func TestWorkflowRunSkippedOnFailureJobDoesntFailRun(t *testing.T) {
+	cache := NewMockCache()
+	onFailure := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, onFailure.Init(cache))
+	defer onFailure.StopTimer()
+
+	j := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	j.succeedInstantly = true
+	j.OnFailureJob = onFailure.Id
+	assert.NoError(t, j.Init(cache))
+	defer j.StopTimer()
+
+	j.Run(cache)
+	wf := waitForWorkflowRun(t, cache)
+
+	onFailure.lock.RLock()
+	assert.Empty(t, onFailure.Stats)
+	onFailure.lock.RUnlock()
+	assert.Equal(t, WorkflowSucceeded, wf.Status)
+	assert.Equal(t, WorkflowSkipped, wf.Jobs[onFailure.Id].Status)
+}
+
+func TestInvalidTriggersAreRejected(t *testing.T) {
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+
+	j := GetMockJob()
+	j.ParentJobs = []string{root.Id}
+	j.Triggers = map[string]*Trigger{root.Id: {On: "sometimes"}}
+	assert.ErrorIs(t, j.Init(cache), ErrInvalidTrigger)
+
+	j = GetMockJob()
+	j.ParentJobs = []string{root.Id}
+	j.Triggers = map[string]*Trigger{"not-a-parent": {On: TriggerOnFailure}}
+	assert.ErrorIs(t, j.Init(cache), ErrInvalidTrigger)
+}
+
+func TestWorkflowRunPassesParentOutputs(t *testing.T) {
+	cache := NewMockCache()
+	root := GetMockRecurringJobWithSchedule(time.Now().Add(time.Hour), "PT1H")
+	root.Name = "extract"
+	root.Command = `echo '{"rows": 3, "table": "users"}'`
+	assert.NoError(t, root.Init(cache))
+	defer root.StopTimer()
+
+	child := GetMockJob()
+	child.Retries = 0
+	child.TemplateDelimiters = "{{ }}"
+	child.Command = `bash -c 'test "$(printenv KALA_PARENT_EXTRACT_TABLE)" = {{ (index .Parents "extract").JSON.table }}'`
+	child.ParentJobs = []string{root.Id}
+	assert.NoError(t, child.Init(cache))
+
+	root.Run(cache)
+	waitForWorkflowRun(t, cache)
+
+	child.lock.RLock()
+	defer child.lock.RUnlock()
+	if assert.Len(t, child.Stats, 1) {
+		assert.True(t, child.Stats[0].Success)
+	}
+}
+
+func TestRunEnv(t *testing.T) {
+	r := &JobRunner{
+		job: GetMockJob(),
+		parents: []*ParentOutput{
+			newParentOutput("1", "extract-users", `{"rows": 3, "table": "users", "ok": true}`),
+			newParentOutput("2", "notify", "sent"),
+		},
+		params: map[string]interface{}{"date": "2026-10-01", "dry-run": true},
+	}
+	assert.Equal(t, []string{
+		`KALA_PARENT_EXTRACT_USERS_OUTPUT={"rows": 3, "table": "users", "ok": true}`,
+		"KALA_PARENT_EXTRACT_USERS_OK=true",
+		"KALA_PARENT_EXTRACT_USERS_ROWS=3",
+		"KALA_PARENT_EXTRACT_USERS_TABLE=users",
+		"KALA_PARENT_NOTIFY_OUTPUT=sent",
+		"KALA_PARAM_DATE=2026-10-01",
+		"KALA_PARAM_DRY_RUN=true",
+	}, r.runEnv())
+}
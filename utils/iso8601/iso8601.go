@@ -137,6 +137,16 @@ func (d *Duration) Add(t time.Time) time.Time {
 	return result
 }
 
+// Sub goes back from t by the duration, undoing Add.
+func (d *Duration) Sub(t time.Time) time.Time {
+	result := t
+	result = result.Add(-time.Second * time.Duration(d.Seconds))
+	result = result.Add(-time.Minute * time.Duration(d.Minutes))
+	result = result.Add(-time.Hour * time.Duration(d.Hours))
+	result = result.AddDate(-d.Years, -d.Months, -(d.Days + d.Weeks*7))
+	return result
+}
+
 func (d *Duration) IsZero() bool {
 	switch {
 	case d.Years != 0:
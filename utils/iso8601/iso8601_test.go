@@ -139,3 +139,14 @@ func TestRelativeTo(t *testing.T) {
 	t.Logf("Anchor plus duration '%s' is: %s", d.String(), d.Add(anchor).Format(time.RFC822))
 	assert.Equal(t, d.RelativeTo(anchor), time.Hour*24*59)
 }
+
+func TestSub(t *testing.T) {
+	anchor := time.Date(2026, time.October, 3, 2, 30, 0, 0, time.UTC)
+
+	d := iso8601.Duration{Days: 1, Hours: 2}
+	assert.Equal(t, time.Date(2026, time.October, 2, 0, 30, 0, 0, time.UTC), d.Sub(anchor))
+	assert.Equal(t, anchor, d.Add(d.Sub(anchor)))
+
+	d = iso8601.Duration{Months: 1}
+	assert.Equal(t, time.Date(2026, time.September, 3, 2, 30, 0, 0, time.UTC), d.Sub(anchor))
+}
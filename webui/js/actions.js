@@ -96,6 +96,19 @@ var actions = (function() {
         })
     },
 
+    getGraph: function() {
+      store.do('startLoading');
+      return Promise.all([
+        kala.getGraph(),
+        new Promise(function(resolve) {
+          setTimeout(resolve, 750)
+        })
+      ])
+        .then(function(results) {
+          store.do('setGraph', results[0]);
+        })
+    },
+
     getJobs: function() {
       store.do('startLoading');
       return Promise.all([
@@ -115,6 +128,8 @@ var actions = (function() {
         this.getJobs();
       } else if (routeID === 'metrics') {
         this.getMetrics();
+      } else if (routeID === 'graph') {
+        this.getGraph();
       }
     },
 
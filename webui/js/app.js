@@ -16,7 +16,7 @@ var app = new Reef('#app', {
         <div class="container">
           <h1 class="title">
             ${route.title} ${route.id === 'jobs' ? ' - ' + Object.keys(props.jobs).length : ''}
-            <span class="is-pulled-right ${activeForRoutes('jobs', 'metrics')}">
+            <span class="is-pulled-right ${activeForRoutes('jobs', 'metrics', 'graph')}">
               <button class="button is-rounded is-info is-outlined" onclick="actions.refresh('${route.id}')" ${props.loading && 'disabled'}>
                 <span class="icon">
                   <i class="fas fa-sync"></i>
@@ -29,6 +29,7 @@ var app = new Reef('#app', {
             <div class="container" style="min-height: 300px">
               <div id="jobsTable" class="${activeForRoutes('jobs')}"></div>
               <div id="metricsPanel" class="${activeForRoutes('metrics')}"></div>
+              <div id="graphPanel" class="${activeForRoutes('graph')}"></div>
               <div id="createPage" class="${activeForRoutes('create')}"></div>
               <div class="loader-wrapper ${props.loading ? 'is-active' : ''}">
                 <div class="loader is-loading"></div>
@@ -72,6 +73,9 @@ var navbar = new Reef('#nav', {
             <a class="navbar-item ${activeForRoute('jobs')}" href="jobs">
               Jobs
             </a>
+            <a class="navbar-item ${activeForRoute('graph')}" href="graph">
+              Graph
+            </a>
             <a class="navbar-item ${activeForRoute('create')}" href="create">
               Create
             </a>
@@ -157,6 +161,7 @@ var jobDetail = new Reef('#jobDetailModal', {
               <button class="delete" aria-label="close" onclick="store.do('clearJobDetail')"></button>
             </header>
             <section class="modal-card-body">
+              ${skippedRunsTable(props.jobDetail.skipped_runs)}
               <pre>${JSON.stringify(props.jobDetail, undefined, 4)}</pre>
             </section>
             <footer class="modal-card-foot">
@@ -231,6 +236,21 @@ var metricsPanel = new Reef('#metricsPanel', {
     },
 });
 
+var graphPanel = new Reef('#graphPanel', {
+    store: store,
+    attachTo: app,
+    template: function(props, route) {
+        if (!props.graph.nodes.length) {
+            return html`<p>No jobs depend on other jobs yet.</p>`
+        }
+        return html`
+      <div style="overflow-x: auto">
+        ${dependencyGraphSvg(props.graph)}
+      </div>
+    `
+    },
+});
+
 var createPanel = new Reef('#createPage', {
     store: store,
     attachTo: app,
@@ -384,4 +404,7 @@ var createPanel = new Reef('#createPage', {
 actions.getJobs()
     .then(function() {
         actions.getMetrics()
+    })
+    .then(function() {
+        actions.getGraph()
     });
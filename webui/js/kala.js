@@ -87,6 +87,18 @@
           console.error('getting job stats failed: ', ex)
         })
     },
+    getGraph: function() {
+      return fetch(ApiBase + 'graph/')
+        .then(function(resp) {
+          return resp.json()
+        })
+        .then(function(json) {
+          return json.graph
+        })
+        .catch(function(ex) {
+          console.error('getting graph failed: ', ex)
+        })
+    },
     metrics: function() {
       return fetch(ApiBase + 'stats/')
         .then(function(resp) {
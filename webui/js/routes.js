@@ -16,6 +16,11 @@ var router = new Reef.Router({
       title: 'Metrics',
       url: '/metrics/'
     },
+    {
+      id: 'graph',
+      title: 'Graph',
+      url: '/graph/'
+    },
     {
       id: 'create',
       title: 'Create Job',
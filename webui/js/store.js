@@ -4,6 +4,7 @@ var store = new Reef.Store({
     jobs: {},
     jobDetail: null,
     metrics: {},
+    graph: {nodes: [], edges: []},
     loading: false,
 
     createType: 'local',
@@ -48,6 +49,10 @@ var store = new Reef.Store({
         }
       }
     },
+    setGraph: function(props, graph) {
+      props.graph = graph || {nodes: [], edges: []};
+      props.loading = false;
+    },
     setMetrics: function(props, metrics) {
       props.metrics = metrics;
       props.loading = false;
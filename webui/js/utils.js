@@ -15,3 +15,119 @@ function html(strings) {
     return acc + str + (args[idx + 1] || '');
   }, '')
 }
+
+function skippedRunsTable(skippedRuns) {
+  if (!skippedRuns || skippedRuns.length === 0) {
+    return '';
+  }
+  var rows = skippedRuns.slice().reverse().reduce(function(acc, run) {
+    return acc + html`
+      <tr>
+        <td>${run.scheduled_at}</td>
+        <td>${run.calendar}</td>
+      </tr>
+    `
+  }, '');
+  return html`
+    <h2 class="subtitle">Skipped Runs</h2>
+    <table class="table is-fullwidth is-striped">
+      <thead>
+        <tr>
+          <th>Scheduled At</th>
+          <th>Calendar</th>
+        </tr>
+      </thead>
+      <tbody>
+        ${rows}
+      </tbody>
+    </table>
+  `
+}
+
+function escapeHTML(s) {
+  return String(s)
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&#39;');
+}
+
+function triggerLabel(trigger) {
+  var on = trigger.on;
+  var codes = trigger.codes || [];
+  if (!on && codes.length) {
+    on = 'completion';
+  }
+  if (on === 'success' && !codes.length) {
+    on = '';
+  }
+  return ((on || '') + ' ' + codes.join(',')).trim();
+}
+
+// Lays the dependency graph out in columns, each job one column right of its
+// furthest parent, and renders it as SVG.
+function dependencyGraphSvg(graph) {
+  var nodeWidth = 160, nodeHeight = 40, colGap = 80, rowGap = 30;
+  var colors = {succeeded: '#48c774', failed: '#f14668', timed_out: '#ffb70f', cancelled: '#7a7a7a'};
+
+  var depth = {};
+  graph.nodes.forEach(function(node) {
+    depth[node.id] = 0;
+  });
+  // Bounded, in case the jobs somehow form a cycle.
+  for (var i = 0; i < graph.nodes.length; i++) {
+    graph.edges.forEach(function(edge) {
+      depth[edge.to] = Math.max(depth[edge.to], depth[edge.from] + 1);
+    });
+  }
+
+  var pos = {}, rows = [];
+  graph.nodes.forEach(function(node) {
+    var col = depth[node.id];
+    rows[col] = (rows[col] || 0) + 1;
+    pos[node.id] = {
+      x: col * (nodeWidth + colGap) + 10,
+      y: (rows[col] - 1) * (nodeHeight + rowGap) + 10
+    };
+  });
+  var width = rows.length * (nodeWidth + colGap);
+  var height = Math.max.apply(null, rows) * (nodeHeight + rowGap) + 10;
+
+  var edges = graph.edges.reduce(function(acc, edge) {
+    var from = pos[edge.from], to = pos[edge.to];
+    var x1 = from.x + nodeWidth, y1 = from.y + nodeHeight / 2;
+    var x2 = to.x, y2 = to.y + nodeHeight / 2;
+    var label = triggerLabel(edge.trigger);
+    return acc + html`
+      <path d="M${x1},${y1} C${x1 + colGap / 2},${y1} ${x2 - colGap / 2},${y2} ${x2},${y2}"
+            fill="none" stroke="#7a7a7a" marker-end="url(#arrow)"></path>
+      ${label && html`<text x="${(x1 + x2) / 2}" y="${(y1 + y2) / 2 - 4}" font-size="11" text-anchor="middle" fill="#4a4a4a">${escapeHTML(label)}</text>`}
+    `
+  }, '');
+
+  var nodes = graph.nodes.reduce(function(acc, node) {
+    var p = pos[node.id];
+    var name = node.name.length > 20 ? node.name.slice(0, 19) + '…' : node.name;
+    return acc + html`
+      <g style="cursor: pointer" onclick="return store.do('showJobDetail', '${escapeHTML(node.id)}')">
+        <title>${escapeHTML(node.name)}${node.last_run_status ? ' - last run ' + node.last_run_status : ''}</title>
+        <rect x="${p.x}" y="${p.y}" width="${nodeWidth}" height="${nodeHeight}" rx="6" fill="white"
+              stroke="${colors[node.last_run_status] || '#b5b5b5'}" stroke-width="2" ${node.disabled ? 'stroke-dasharray="5,3"' : ''}></rect>
+        <text x="${p.x + nodeWidth / 2}" y="${p.y + nodeHeight / 2 + 5}" font-size="13" text-anchor="middle">${escapeHTML(name)}</text>
+      </g>
+    `
+  }, '');
+
+  return html`
+    <svg width="${width}" height="${height}" xmlns="http://www.w3.org/2000/svg">
+      <defs>
+        <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto">
+          <path d="M0,0 L10,5 L0,10 z" fill="#7a7a7a"></path>
+        </marker>
+      </defs>
+      ${edges}
+      ${nodes}
+    </svg>
+  `
+}